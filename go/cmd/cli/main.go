@@ -2,14 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 func main() {
@@ -25,6 +32,16 @@ func main() {
 		listAlertsCmd(os.Args[2:])
 	case "list-vitals":
 		listVitalsCmd(os.Args[2:])
+	case "import-vitals":
+		importVitalsCmd(os.Args[2:])
+	case "watch-alerts":
+		watchAlertsCmd(os.Args[2:])
+	case "get-alert":
+		getAlertCmd(os.Args[2:])
+	case "ack-alert":
+		ackAlertCmd(os.Args[2:])
+	case "resolve-alert":
+		resolveAlertCmd(os.Args[2:])
 	default:
 		usage()
 		os.Exit(1)
@@ -38,8 +55,18 @@ func insertVitalCmd(args []string) {
 	systolic := fs.Int("systolic", 0, "systolic blood pressure")
 	diastolic := fs.Int("diastolic", 0, "diastolic blood pressure")
 	takenAt := fs.Int64("taken-at", 0, "unix timestamp when blood pressure was taken")
+	idempotencyKey := fs.String("idempotency-key", "", "optional key that makes retries of this reading safe")
+	kind := fs.String("kind", "blood_pressure", "blood_pressure, pulse, spo2, weight or glucose")
+	value := fs.Float64("value", 0, "reading for kinds other than blood_pressure")
+	unit := fs.String("unit", "", "unit of --value; defaults to the kind's unit (bpm, %, kg, mg/dL)")
 	fs.Parse(args)
 
+	vitalKind, ok := vitalsv1.VitalKind_value["VITAL_KIND_"+strings.ToUpper(*kind)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown kind %q\n", *kind)
+		os.Exit(1)
+	}
+
 	client, cleanup := newClient(*addr)
 	defer cleanup()
 
@@ -51,10 +78,14 @@ func insertVitalCmd(args []string) {
 	}
 
 	resp, err := client.IngestVital(ctx, &vitalsv1.IngestVitalRequest{
-		PatientId: *patientID,
-		Systolic:  int32(*systolic),
-		Diastolic: int32(*diastolic),
-		TakenAt:   *takenAt,
+		PatientId:      *patientID,
+		Kind:           vitalsv1.VitalKind(vitalKind),
+		Systolic:       int32(*systolic),
+		Diastolic:      int32(*diastolic),
+		Value:          *value,
+		Unit:           *unit,
+		TakenAt:        *takenAt,
+		IdempotencyKey: *idempotencyKey,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "insert vital failed: %v\n", err)
@@ -62,13 +93,296 @@ func insertVitalCmd(args []string) {
 	}
 
 	vital := resp.GetVital()
-	fmt.Printf("stored vital id=%d patient=%s bp=%d/%d taken_at=%d received_at=%d\n", vital.GetId(), vital.GetPatientId(), vital.GetSystolic(), vital.GetDiastolic(), vital.GetTakenAt(), vital.GetReceivedAt())
+	fmt.Printf("stored vital id=%d patient=%s %s taken_at=%d received_at=%d%s\n", vital.GetId(), vital.GetPatientId(), measurement(vital), vital.GetTakenAt(), vital.GetReceivedAt(), qualityFlags(vital))
+}
+
+func getAlertCmd(args []string) {
+	fs := flag.NewFlagSet("get-alert", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:50051", "gRPC server address")
+	id := fs.Int64("id", 0, "alert id")
+	fs.Parse(args)
+
+	client, cleanup := newClient(*addr)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.GetAlert(ctx, &vitalsv1.GetAlertRequest{AlertId: *id})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "get alert failed: %v\n", err)
+		os.Exit(1)
+	}
+	alert := resp.GetAlert()
+	vital := alert.GetVital()
+	fmt.Printf("alert id=%d patient=%s %s status=%s severity=%s reason=%s created_at=%d\n", alert.GetId(), vital.GetPatientId(), measurement(vital), alert.GetState().String(), alert.GetSeverity().String(), alert.GetReason(), alert.GetCreatedAt())
+	for _, entry := range resp.GetHistory() {
+		fmt.Printf("  %d %s%s\n", entry.GetAt(), strings.TrimPrefix(entry.GetType().String(), "ALERT_HISTORY_TYPE_"), historyDetails(entry))
+	}
+}
+
+// historyDetails formats the fields set on a timeline entry.
+func historyDetails(entry *vitalsv1.AlertHistoryEntry) string {
+	var b strings.Builder
+	if entry.GetFromState() != entry.GetState() {
+		fmt.Fprintf(&b, " %s -> %s", entry.GetFromState(), entry.GetState())
+	}
+	if id := entry.GetVitalId(); id != 0 {
+		fmt.Fprintf(&b, " vital=%d", id)
+	}
+	if id := entry.GetMessageId(); id != 0 {
+		fmt.Fprintf(&b, " message=%d", id)
+	}
+	if r := entry.GetRecipient(); r != vitalsv1.Recipient_RECIPIENT_UNSPECIFIED {
+		fmt.Fprintf(&b, " to=%s", r)
+	}
+	if actor := entry.GetActor(); actor != "" {
+		fmt.Fprintf(&b, " by=%s", actor)
+	}
+	if reason := entry.GetResolutionReason(); reason != vitalsv1.ResolutionReason_RESOLUTION_REASON_UNSPECIFIED {
+		fmt.Fprintf(&b, " reason=%s", reason)
+	}
+	if note := entry.GetNote(); note != "" {
+		fmt.Fprintf(&b, " note=%q", note)
+	}
+	return b.String()
+}
+
+func ackAlertCmd(args []string) {
+	fs := flag.NewFlagSet("ack-alert", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:50051", "gRPC server address")
+	id := fs.Int64("id", 0, "alert id")
+	clinician := fs.String("clinician", "", "who is acknowledging the alert")
+	note := fs.String("note", "", "optional note")
+	fs.Parse(args)
+
+	client, cleanup := newClient(*addr)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.AcknowledgeAlert(ctx, &vitalsv1.AcknowledgeAlertRequest{
+		AlertId:   *id,
+		Clinician: *clinician,
+		Note:      *note,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "acknowledge alert failed: %v\n", err)
+		os.Exit(1)
+	}
+	alert := resp.GetAlert()
+	fmt.Printf("alert id=%d status=%s acknowledged_by=%s\n", alert.GetId(), alert.GetState().String(), alert.GetAcknowledgement().GetClinician())
+}
+
+func resolveAlertCmd(args []string) {
+	fs := flag.NewFlagSet("resolve-alert", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:50051", "gRPC server address")
+	id := fs.Int64("id", 0, "alert id")
+	clinician := fs.String("clinician", "", "who is resolving the alert")
+	reason := fs.String("reason", "", "treated, false_alarm, measurement_error, duplicate or other")
+	note := fs.String("note", "", "optional note")
+	fs.Parse(args)
+
+	resolution, ok := vitalsv1.ResolutionReason_value["RESOLUTION_REASON_"+strings.ToUpper(*reason)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown reason %q\n", *reason)
+		os.Exit(1)
+	}
+
+	client, cleanup := newClient(*addr)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.ResolveAlert(ctx, &vitalsv1.ResolveAlertRequest{
+		AlertId:   *id,
+		Clinician: *clinician,
+		Reason:    vitalsv1.ResolutionReason(resolution),
+		Note:      *note,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resolve alert failed: %v\n", err)
+		os.Exit(1)
+	}
+	alert := resp.GetAlert()
+	fmt.Printf("alert id=%d status=%s resolved_by=%s reason=%s\n", alert.GetId(), alert.GetState().String(), alert.GetResolution().GetClinician(), alert.GetResolutionReason().String())
+}
+
+// listFlags are the paging and filtering options shared by the list commands.
+type listFlags struct {
+	pageSize    *int
+	pageToken   *string
+	takenAfter  *int64
+	takenBefore *int64
+	order       *string
+}
+
+// importVitalsCmd streams readings from a CSV file with rows of
+// patient_id,systolic,diastolic,taken_at[,idempotency_key].
+func importVitalsCmd(args []string) {
+	fs := flag.NewFlagSet("import-vitals", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:50051", "gRPC server address")
+	path := fs.String("file", "", "CSV file of readings; - reads stdin")
+	fs.Parse(args)
+
+	in := os.Stdin
+	if *path != "-" {
+		f, err := os.Open(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "open readings: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read readings: %v\n", err)
+		os.Exit(1)
+	}
+
+	client, cleanup := newClient(*addr)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamIngestVitals(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import vitals failed: %v\n", err)
+		os.Exit(1)
+	}
+	for i, row := range rows {
+		req, err := parseVitalRow(row)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "row %d: %v\n", i+1, err)
+			os.Exit(1)
+		}
+		if err := stream.Send(req); err != nil {
+			break // the server ended the stream; CloseAndRecv reports why
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import vitals failed: %v\n", err)
+		os.Exit(1)
+	}
+	for _, result := range resp.GetResults() {
+		if result.GetErrorCode() != 0 {
+			fmt.Printf("row %d rejected: %s: %s\n", result.GetIndex()+1, codes.Code(result.GetErrorCode()), result.GetErrorMessage())
+		}
+	}
+	fmt.Printf("imported %d of %d readings\n", resp.GetAcceptedCount(), len(rows))
+}
+
+func parseVitalRow(row []string) (*vitalsv1.IngestVitalRequest, error) {
+	if len(row) < 4 || len(row) > 5 {
+		return nil, fmt.Errorf("expected patient_id,systolic,diastolic,taken_at[,idempotency_key], got %d fields", len(row))
+	}
+	var nums [3]int64
+	for i, field := range row[1:4] {
+		bits := 32
+		if i == 2 {
+			bits = 64
+		}
+		n, err := strconv.ParseInt(field, 10, bits)
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %w", i+2, err)
+		}
+		nums[i] = n
+	}
+	req := &vitalsv1.IngestVitalRequest{
+		PatientId: row[0],
+		Systolic:  int32(nums[0]),
+		Diastolic: int32(nums[1]),
+		TakenAt:   nums[2],
+	}
+	if len(row) == 5 {
+		req.IdempotencyKey = row[4]
+	}
+	return req, nil
+}
+
+func addListFlags(fs *flag.FlagSet) listFlags {
+	return listFlags{
+		pageSize:    fs.Int("page-size", 0, "results per page (server default 100, max 1000)"),
+		pageToken:   fs.String("page-token", "", "next_page_token from a previous call"),
+		takenAfter:  fs.Int64("taken-after", 0, "only readings taken at or after this unix timestamp"),
+		takenBefore: fs.Int64("taken-before", 0, "only readings taken before this unix timestamp"),
+		order:       fs.String("order", "asc", "sort by taken_at: asc or desc"),
+	}
+}
+
+func (f listFlags) sortOrder() vitalsv1.SortOrder {
+	switch strings.ToLower(*f.order) {
+	case "asc":
+		return vitalsv1.SortOrder_SORT_ORDER_ASC
+	case "desc":
+		return vitalsv1.SortOrder_SORT_ORDER_DESC
+	default:
+		fmt.Fprintf(os.Stderr, "invalid --order %q (want asc or desc)\n", *f.order)
+		os.Exit(1)
+		return 0
+	}
+}
+
+func parseAlertStates(list string) []vitalsv1.AlertState {
+	var states []vitalsv1.AlertState
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		value, ok := vitalsv1.AlertState_value["ALERT_STATE_"+strings.ToUpper(name)]
+		if !ok || value == int32(vitalsv1.AlertState_ALERT_STATE_UNSPECIFIED) {
+			fmt.Fprintf(os.Stderr, "invalid --status %q (want active, auto_resolved, resolved_by_retake, confirmed_abnormal, acknowledged or resolved)\n", name)
+			os.Exit(1)
+		}
+		states = append(states, vitalsv1.AlertState(value))
+	}
+	return states
+}
+
+// measurement prints blood pressure as bp=120/80 and other kinds as, e.g.,
+// pulse=72bpm.
+func measurement(vital *vitalsv1.Vital) string {
+	kind := vital.GetKind()
+	if kind == vitalsv1.VitalKind_VITAL_KIND_UNSPECIFIED || kind == vitalsv1.VitalKind_VITAL_KIND_BLOOD_PRESSURE {
+		return fmt.Sprintf("bp=%d/%d", vital.GetSystolic(), vital.GetDiastolic())
+	}
+	name := strings.ToLower(strings.TrimPrefix(kind.String(), "VITAL_KIND_"))
+	return fmt.Sprintf("%s=%g%s", name, vital.GetValue(), vital.GetUnit())
+}
+
+// qualityFlags prints a reading's quality flags, if any, as " flags=A,B".
+func qualityFlags(vital *vitalsv1.Vital) string {
+	if len(vital.GetQualityFlags()) == 0 {
+		return ""
+	}
+	names := make([]string, len(vital.GetQualityFlags()))
+	for i, flag := range vital.GetQualityFlags() {
+		names[i] = strings.TrimPrefix(flag.String(), "QUALITY_FLAG_")
+	}
+	return " flags=" + strings.Join(names, ",")
+}
+
+func printNextPage(token string) {
+	if token != "" {
+		fmt.Printf("next_page_token=%s\n", token)
+	}
 }
 
 func listAlertsCmd(args []string) {
 	fs := flag.NewFlagSet("list-alerts", flag.ExitOnError)
 	addr := fs.String("addr", "127.0.0.1:50051", "gRPC server address")
 	patientID := fs.String("patient", "", "patient identifier")
+	status := fs.String("status", "", "comma-separated statuses to keep: active, acknowledged, resolved, auto_resolved")
+	list := addListFlags(fs)
 	fs.Parse(args)
 
 	client, cleanup := newClient(*addr)
@@ -77,7 +391,15 @@ func listAlertsCmd(args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, err := client.ListAlerts(ctx, &vitalsv1.ListAlertsRequest{PatientId: *patientID})
+	resp, err := client.ListAlerts(ctx, &vitalsv1.ListAlertsRequest{
+		PatientId:   *patientID,
+		PageSize:    int32(*list.pageSize),
+		PageToken:   *list.pageToken,
+		TakenAfter:  *list.takenAfter,
+		TakenBefore: *list.takenBefore,
+		Order:       list.sortOrder(),
+		States:      parseAlertStates(*status),
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "list alerts failed: %v\n", err)
 		os.Exit(1)
@@ -90,14 +412,16 @@ func listAlertsCmd(args []string) {
 
 	for _, alert := range resp.GetAlerts() {
 		vital := alert.GetVital()
-		fmt.Printf("alert id=%d patient=%s bp=%d/%d status=%s reason=%s created_at=%d\n", alert.GetId(), vital.GetPatientId(), vital.GetSystolic(), vital.GetDiastolic(), alert.GetStatus().String(), alert.GetReason(), alert.GetCreatedAt())
+		fmt.Printf("alert id=%d patient=%s %s status=%s severity=%s reason=%s created_at=%d\n", alert.GetId(), vital.GetPatientId(), measurement(vital), alert.GetState().String(), alert.GetSeverity().String(), alert.GetReason(), alert.GetCreatedAt())
 	}
+	printNextPage(resp.GetNextPageToken())
 }
 
 func listVitalsCmd(args []string) {
 	fs := flag.NewFlagSet("list-vitals", flag.ExitOnError)
 	addr := fs.String("addr", "127.0.0.1:50051", "gRPC server address")
 	patientID := fs.String("patient", "", "patient identifier")
+	list := addListFlags(fs)
 	fs.Parse(args)
 
 	client, cleanup := newClient(*addr)
@@ -106,7 +430,14 @@ func listVitalsCmd(args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	resp, err := client.ListVitals(ctx, &vitalsv1.ListVitalsRequest{PatientId: *patientID})
+	resp, err := client.ListVitals(ctx, &vitalsv1.ListVitalsRequest{
+		PatientId:   *patientID,
+		PageSize:    int32(*list.pageSize),
+		PageToken:   *list.pageToken,
+		TakenAfter:  *list.takenAfter,
+		TakenBefore: *list.takenBefore,
+		Order:       list.sortOrder(),
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "list vitals failed: %v\n", err)
 		os.Exit(1)
@@ -118,7 +449,110 @@ func listVitalsCmd(args []string) {
 	}
 
 	for _, vital := range resp.GetVitals() {
-		fmt.Printf("vital id=%d patient=%s bp=%d/%d taken_at=%d received_at=%d\n", vital.GetId(), vital.GetPatientId(), vital.GetSystolic(), vital.GetDiastolic(), vital.GetTakenAt(), vital.GetReceivedAt())
+		fmt.Printf("vital id=%d patient=%s %s taken_at=%d received_at=%d%s\n", vital.GetId(), vital.GetPatientId(), measurement(vital), vital.GetTakenAt(), vital.GetReceivedAt(), qualityFlags(vital))
+	}
+	printNextPage(resp.GetNextPageToken())
+}
+
+// watchAlertsCmd tails the alert stream until interrupted, reconnecting with
+// the last resume token so no events are lost across a dropped connection.
+func watchAlertsCmd(args []string) {
+	fs := flag.NewFlagSet("watch-alerts", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:50051", "gRPC server address")
+	patientID := fs.String("patient", "", "patient identifier (empty watches everyone)")
+	includeVitals := fs.Bool("vitals", false, "also print incoming vitals")
+	token := fs.String("resume-token", "", "resume after this event")
+	fs.Parse(args)
+
+	client, cleanup := newClient(*addr)
+	defer cleanup()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	backoff := time.Second
+	relist := false
+	for {
+		streamCtx, cancelStream := context.WithCancel(ctx)
+		stream, err := client.WatchAlerts(streamCtx, &vitalsv1.WatchAlertsRequest{
+			PatientId:     *patientID,
+			ResumeToken:   *token,
+			IncludeVitals: *includeVitals,
+		})
+		if err == nil && relist {
+			// The stream is open before listing, so a change made while
+			// listing is printed again rather than missed.
+			if err = printOpenAlerts(ctx, client, *patientID); err == nil {
+				relist = false
+			}
+		}
+		for err == nil {
+			var event *vitalsv1.WatchEvent
+			if event, err = stream.Recv(); err == nil {
+				printWatchEvent(event)
+				*token = event.GetResumeToken()
+				backoff = time.Second
+			}
+		}
+		cancelStream()
+		if ctx.Err() != nil {
+			return
+		}
+		if status.Code(err) == codes.OutOfRange {
+			fmt.Fprintln(os.Stderr, "resume point expired; some events were missed, re-listing open alerts and watching from now")
+			*token = ""
+			relist = true
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "watch interrupted: %v; reconnecting in %s\n", err, backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, 30*time.Second)
+	}
+}
+
+// printOpenAlerts lists every open alert, so a watcher that missed events
+// still knows the current state.
+func printOpenAlerts(ctx context.Context, client vitalsv1.VitalsServiceClient, patientID string) error {
+	req := &vitalsv1.ListAlertsRequest{
+		PatientId: patientID,
+		PageSize:  1000,
+		States: []vitalsv1.AlertState{
+			vitalsv1.AlertState_ALERT_STATE_ACTIVE,
+			vitalsv1.AlertState_ALERT_STATE_CONFIRMED_ABNORMAL,
+			vitalsv1.AlertState_ALERT_STATE_ACKNOWLEDGED,
+		},
+	}
+	for {
+		resp, err := client.ListAlerts(ctx, req)
+		if err != nil {
+			return err
+		}
+		for _, alert := range resp.GetAlerts() {
+			vital := alert.GetVital()
+			fmt.Printf("alert open id=%d patient=%s %s status=%s severity=%s reason=%s\n", alert.GetId(), vital.GetPatientId(), measurement(vital), alert.GetState().String(), alert.GetSeverity().String(), alert.GetReason())
+		}
+		if resp.GetNextPageToken() == "" {
+			return nil
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
+
+func printWatchEvent(event *vitalsv1.WatchEvent) {
+	switch event.GetType() {
+	case vitalsv1.WatchEventType_WATCH_EVENT_TYPE_VITAL_RECEIVED:
+		vital := event.GetVital()
+		fmt.Printf("vital id=%d patient=%s %s taken_at=%d\n", vital.GetId(), vital.GetPatientId(), measurement(vital), vital.GetTakenAt())
+	case vitalsv1.WatchEventType_WATCH_EVENT_TYPE_ALERT_CREATED:
+		alert := event.GetAlert()
+		fmt.Printf("alert created id=%d patient=%s severity=%s reason=%s\n", alert.GetId(), alert.GetVital().GetPatientId(), alert.GetSeverity().String(), alert.GetReason())
+	case vitalsv1.WatchEventType_WATCH_EVENT_TYPE_ALERT_STATUS_CHANGED:
+		alert := event.GetAlert()
+		fmt.Printf("alert status id=%d patient=%s %s -> %s\n", alert.GetId(), alert.GetVital().GetPatientId(), event.GetPreviousState().String(), alert.GetState().String())
 	}
 }
 
@@ -135,6 +569,13 @@ func newClient(addr string) (vitalsv1.VitalsServiceClient, func()) {
 func usage() {
 	fmt.Fprintln(os.Stderr, "usage:")
 	fmt.Fprintln(os.Stderr, "  cli insert-vital --patient <id> --systolic <value> --diastolic <value> [--taken-at <unix>] [--addr host:port]")
-	fmt.Fprintln(os.Stderr, "  cli list-alerts [--patient <id>] [--addr host:port]")
-	fmt.Fprintln(os.Stderr, "  cli list-vitals [--patient <id>] [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli insert-vital --patient <id> --kind pulse|spo2|weight|glucose --value <value> [--unit <unit>] [--taken-at <unix>] [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli list-alerts [--patient <id>] [--status active,auto_resolved,resolved_by_retake,confirmed_abnormal,acknowledged,resolved] [list options] [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli list-vitals [--patient <id>] [list options] [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli import-vitals --file <readings.csv|-> [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli watch-alerts [--patient <id>] [--vitals] [--resume-token <token>] [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli get-alert --id <alert id> [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli ack-alert --id <alert id> --clinician <name> [--note <text>] [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  cli resolve-alert --id <alert id> --clinician <name> --reason treated|false_alarm|measurement_error|duplicate|other [--note <text>] [--addr host:port]")
+	fmt.Fprintln(os.Stderr, "  list options: [--page-size <n>] [--page-token <token>] [--taken-after <unix>] [--taken-before <unix>] [--order asc|desc]")
 }
@@ -0,0 +1,65 @@
+// Command fakenotify runs fake SMS, SMTP and webhook servers so the server's
+// notification channels can be tried offline. It logs every message it
+// receives.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"cadence-vitals-interview/internal/notify/notifytest"
+)
+
+func main() {
+	smsAddr := flag.String("sms-addr", "127.0.0.1:8025", "listen address for the Twilio-compatible SMS API")
+	accountSID := flag.String("twilio-account-sid", "ACfake", "account SID the SMS API accepts")
+	authToken := flag.String("twilio-auth-token", "fake-token", "auth token the SMS API accepts")
+	smtpAddr := flag.String("smtp-addr", "127.0.0.1:2525", "listen address for the SMTP server")
+	webhookAddr := flag.String("webhook-addr", "127.0.0.1:8026", "listen address for the webhook receiver")
+	webhookSecret := flag.String("webhook-secret", "", "reject webhook requests not signed with this secret")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sms := notifytest.NewSMSProvider(*accountSID, *authToken)
+	sms.Logf = log.Printf
+	webhook := notifytest.NewWebhookReceiver(*webhookSecret)
+	webhook.Logf = log.Printf
+
+	smtp := notifytest.NewSMTPServer()
+	smtp.Logf = log.Printf
+	if err := smtp.Start(*smtpAddr); err != nil {
+		log.Fatalf("failed to listen on %s: %v", *smtpAddr, err)
+	}
+	defer smtp.Close()
+
+	servers := []*http.Server{
+		{Addr: *smsAddr, Handler: sms},
+		{Addr: *webhookAddr, Handler: webhook},
+	}
+	for _, srv := range servers {
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("failed to serve on %s: %v", srv.Addr, err)
+			}
+		}()
+	}
+
+	log.Printf("fake SMS API on http://%s (account %s, token %s)", *smsAddr, *accountSID, *authToken)
+	log.Printf("fake SMTP server on %s", smtp.Addr())
+	log.Printf("fake webhook receiver on http://%s/", *webhookAddr)
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, srv := range servers {
+		srv.Shutdown(shutdownCtx)
+	}
+}
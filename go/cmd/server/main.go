@@ -3,16 +3,22 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
+	// Contacts name IANA time zones; don't depend on the host having them.
+	_ "time/tzdata"
 
 	"cadence-vitals-interview/internal/api"
 	"cadence-vitals-interview/internal/app"
+	"cadence-vitals-interview/internal/notify"
 	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
 	"google.golang.org/grpc"
 )
@@ -20,24 +26,119 @@ import (
 func main() {
 	grpcAddr := flag.String("grpc-addr", ":50051", "gRPC listen address")
 	httpAddr := flag.String("http-addr", ":8080", "HTTP listen address for dashboard")
+	storeKind := flag.String("store", "memory", "storage backend: memory, journal or sqlite")
+	dbPath := flag.String("db-path", "vitals.db", "SQLite database file (with --store=sqlite)")
+	dataDir := flag.String("data-dir", "vitals-data", "journal and snapshot directory (with --store=journal)")
+	dedupWindow := flag.Duration("dedup-window", app.DefaultDedupPolicy().Window, "fold abnormal readings into a patient's open alert seen within this window (0 disables dedup)")
+	messageWorkers := flag.Int("message-workers", 4, "messages sent at once; each patient's messages are still sent one at a time, in order")
+	notifyCooldown := flag.Duration("notify-cooldown", 2*time.Minute, "minimum time between notifications to the same patient")
+	idempotencyRetention := flag.Duration("idempotency-retention", app.DefaultIdempotencyRetention, "how long an ingest idempotency key returns the vital it first stored")
+	alertFlagged := flag.String("alert-flagged", "", "comma-separated quality flags whose readings still alert (NARROW_PULSE_PRESSURE, ATYPICAL_VALUE)")
+	escalation := flag.String("escalation", "on_call_nurse=15m,physician=15m", "comma-separated recipient=wait tiers an unanswered alert escalates through, each wait counted from the previous step (empty disables)")
+	contacts := flag.String("contacts", "", "JSON contacts file; when set, messages are delivered over each recipient's preferred channels instead of simulated")
+	twilioURL := flag.String("twilio-url", "https://api.twilio.com", "base URL of the Twilio-compatible SMS API")
+	twilioAccountSID := flag.String("twilio-account-sid", "", "SMS API account SID (enables the sms channel)")
+	twilioAuthToken := flag.String("twilio-auth-token", os.Getenv("TWILIO_AUTH_TOKEN"), "SMS API auth token (default $TWILIO_AUTH_TOKEN)")
+	smsFrom := flag.String("sms-from", "", "phone number SMS messages are sent from")
+	smtpAddr := flag.String("smtp-addr", "", "SMTP relay host:port (enables the email channel)")
+	smtpUsername := flag.String("smtp-username", "", "SMTP username, if the relay needs auth")
+	smtpPassword := flag.String("smtp-password", os.Getenv("SMTP_PASSWORD"), "SMTP password (default $SMTP_PASSWORD)")
+	emailFrom := flag.String("email-from", "alerts@cadence.example", "address emails are sent from")
+	smtpAllowPlaintext := flag.Bool("smtp-allow-plaintext", false, "send email unencrypted to a relay that doesn't offer STARTTLS (always allowed on localhost)")
+	webhookSecret := flag.String("webhook-secret", "", "sign webhook requests with this secret")
+	templatesDir := flag.String("templates", "", "directory of <locale>/<name>.tmpl notification templates overriding the built-in ones")
 	flag.Parse()
 
-	store := app.NewInMemoryStore()
-	pubsub := app.NewPubSub()
-	service := app.NewService(store, pubsub)
+	quality, err := parseQualityPolicy(*alertFlagged)
+	if err != nil {
+		log.Fatal(err)
+	}
+	escalationPolicy, err := parseEscalationPolicy(*escalation)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	// Message queue for patient notifications (5-20 second simulated delay)
-	messageQueue := app.NewMessageQueue(5*time.Second, 20*time.Second)
-	messageWorker := app.NewMessageWorker(messageQueue)
+	templates := app.DefaultTemplates()
+	if *templatesDir != "" {
+		templates, err = app.LoadTemplates(*templatesDir)
+		if err != nil {
+			log.Fatalf("failed to load templates: %v", err)
+		}
+	}
+
+	store, err := openStore(*storeKind, *dbPath, *dataDir)
+	if err != nil {
+		log.Fatalf("failed to open %s store: %v", *storeKind, err)
+	}
+	pubsub := app.NewPubSub()
+	relay := app.NewOutboxRelay(store, pubsub)
+
+	// Message queue for patient notifications (5-20 second simulated delay
+	// unless --contacts routes them to real channels)
+	queueOpts := []app.MessageQueueOption{app.WithPatientCooldown(*notifyCooldown)}
+	inbox := notify.NewInbox()
+	composer := app.NewMessageComposer(templates, nil)
+	if *contacts != "" {
+		directory, err := notify.LoadDirectory(*contacts)
+		if err != nil {
+			log.Fatalf("failed to load contacts: %v", err)
+		}
+		channels := []notify.Channel{inbox, notify.NewWebhook(notify.WebhookConfig{Secret: *webhookSecret})}
+		if *twilioAccountSID != "" {
+			channels = append(channels, notify.NewSMS(notify.TwilioConfig{
+				BaseURL:    *twilioURL,
+				AccountSID: *twilioAccountSID,
+				AuthToken:  *twilioAuthToken,
+				From:       *smsFrom,
+			}))
+		}
+		if *smtpAddr != "" {
+			channels = append(channels, notify.NewEmail(notify.SMTPConfig{
+				Addr:           *smtpAddr,
+				From:           *emailFrom,
+				Username:       *smtpUsername,
+				Password:       *smtpPassword,
+				AllowPlaintext: *smtpAllowPlaintext,
+			}))
+		}
+		queueOpts = append(queueOpts,
+			app.WithMessageSender(notify.NewRouter(directory, channels...)),
+			app.WithQuietHours(directory))
+		composer = app.NewMessageComposer(templates, directory)
+	}
+	messageQueue := app.NewMessageQueue(5*time.Second, 20*time.Second, queueOpts...)
+	service := app.NewService(store,
+		app.WithOutboxRelay(relay),
+		app.WithAlertEvents(pubsub),
+		app.WithMessageQueue(messageQueue),
+		app.WithIdempotencyRetention(*idempotencyRetention))
+	messageQueue.AddListener(app.RecordMessageHistory(store))
+	messageWorker := app.NewMessageWorker(messageQueue, app.WithMessageWorkers(*messageWorkers))
 
 	// Alert worker with message queue
-	worker := app.NewAlertWorker(pubsub, store, 16, messageQueue)
+	dedup := app.DedupPolicy{Enabled: *dedupWindow > 0, Window: *dedupWindow}
+	worker := app.NewAlertWorker(pubsub, store, 16, messageQueue, app.WithDedupPolicy(dedup), app.WithQualityPolicy(quality),
+		app.WithMessageComposer(composer))
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	go worker.Run(ctx)
-	go messageWorker.Run(ctx)
+	escalator := app.NewEscalator(store, messageQueue, app.WithEscalationPolicy(escalationPolicy), app.WithEscalatorComposer(composer))
+	// The message queue starts empty; requests that hadn't gone out when
+	// the server last stopped are queued again.
+	if n, err := worker.ResendRetakeRequests(ctx); err != nil {
+		log.Printf("failed to resend retake requests: %v", err)
+	} else if n > 0 {
+		log.Printf("queued %d retake requests again", n)
+	}
+	var workers sync.WaitGroup
+	for _, run := range []func(context.Context){worker.Run, escalator.Run, relay.Run, messageWorker.Run} {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx)
+		}()
+	}
 
 	// Start gRPC server
 	lis, err := net.Listen("tcp", *grpcAddr)
@@ -46,13 +147,16 @@ func main() {
 	}
 
 	grpcServer := grpc.NewServer()
-	vitalsv1.RegisterVitalsServiceServer(grpcServer, api.NewServer(service))
+	vitalsv1.RegisterVitalsServiceServer(grpcServer, api.NewServer(service, pubsub))
 
 	// Start HTTP server for dashboard
-	httpServer := api.NewHTTPServer(service, messageQueue)
+	httpServer := api.NewHTTPServer(service, messageQueue, pubsub)
+	mux := http.NewServeMux()
+	mux.Handle("/inbox/", inbox.Handler())
+	mux.Handle("/", httpServer.Handler())
 	httpSrv := &http.Server{
 		Addr:    *httpAddr,
-		Handler: httpServer.Handler(),
+		Handler: mux,
 	}
 
 	go func() {
@@ -62,20 +166,82 @@ func main() {
 		}
 	}()
 
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		<-ctx.Done()
-		grpcServer.GracefulStop()
-
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		httpSrv.Shutdown(shutdownCtx)
-
+		// Closing pubsub first ends open watch streams; GracefulStop would
+		// otherwise wait on them forever.
 		pubsub.Close()
-		store.Close()
+		grpcServer.GracefulStop()
 	}()
 
 	log.Printf("gRPC server listening on %s", *grpcAddr)
 	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("grpc server stopped: %v", err)
+		log.Printf("grpc server stopped: %v", err)
+	}
+
+	// Serve returns as soon as GracefulStop begins, while RPCs may still be
+	// draining; wait for them and stop everything else here so nothing is
+	// still using the store when it closes.
+	stop()
+	<-stopped
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	httpSrv.Shutdown(shutdownCtx)
+	workers.Wait()
+	store.Close()
+}
+
+func openStore(kind, dbPath, dataDir string) (app.Store, error) {
+	switch kind {
+	case "memory":
+		return app.NewInMemoryStore(), nil
+	case "journal":
+		return app.OpenJournaledStore(app.JournalOptions{Dir: dataDir})
+	case "sqlite":
+		return app.OpenSQLiteStore(dbPath)
+	default:
+		return nil, fmt.Errorf("unknown store %q (want memory, journal or sqlite)", kind)
+	}
+}
+
+func parseQualityPolicy(list string) (app.QualityPolicy, error) {
+	var policy app.QualityPolicy
+	for name := range strings.SplitSeq(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		qf, ok := app.ParseQualityFlag(name)
+		if !ok {
+			return app.QualityPolicy{}, fmt.Errorf("unknown quality flag %q", name)
+		}
+		policy.AlertOn = append(policy.AlertOn, qf)
+	}
+	return policy, nil
+}
+
+func parseEscalationPolicy(list string) (app.EscalationPolicy, error) {
+	var policy app.EscalationPolicy
+	for tier := range strings.SplitSeq(list, ",") {
+		tier = strings.TrimSpace(tier)
+		if tier == "" {
+			continue
+		}
+		name, wait, ok := strings.Cut(tier, "=")
+		if !ok {
+			return app.EscalationPolicy{}, fmt.Errorf("escalation tier %q: want recipient=wait", tier)
+		}
+		recipient, ok := app.ParseRecipient(strings.TrimSpace(name))
+		if !ok || recipient == app.RecipientPatient {
+			return app.EscalationPolicy{}, fmt.Errorf("escalation tier %q: unknown recipient %q (want on_call_nurse or physician)", tier, name)
+		}
+		after, err := time.ParseDuration(strings.TrimSpace(wait))
+		if err != nil || after < 0 {
+			return app.EscalationPolicy{}, fmt.Errorf("escalation tier %q: invalid wait %q", tier, wait)
+		}
+		policy.Tiers = append(policy.Tiers, app.EscalationTier{Recipient: recipient, After: after})
 	}
+	return policy, nil
 }
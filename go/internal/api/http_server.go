@@ -2,8 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,7 +21,9 @@ type HTTPServer struct {
 	sseClients map[chan []byte]struct{}
 }
 
-func NewHTTPServer(service *app.Service, messageQueue *app.MessageQueue) *HTTPServer {
+// NewHTTPServer serves the dashboard and JSON API. Message updates and, if
+// events is non-nil, alert events are pushed to dashboards over /events.
+func NewHTTPServer(service *app.Service, messageQueue *app.MessageQueue, events *app.PubSub) *HTTPServer {
 	s := &HTTPServer{
 		service:      service,
 		messageQueue: messageQueue,
@@ -28,6 +33,14 @@ func NewHTTPServer(service *app.Service, messageQueue *app.MessageQueue) *HTTPSe
 	if messageQueue != nil {
 		messageQueue.AddListener(s.onMessageUpdate)
 	}
+	if events != nil {
+		alerts, _ := events.SubscribeWith(app.SubscribeOptions{
+			Buffer: 64,
+			Types:  []app.EventType{app.EventTypeAlertCreated, app.EventTypeAlertStatusChanged},
+			Policy: app.SlowConsumerDropOldest,
+		})
+		go s.forwardAlertEvents(alerts)
+	}
 
 	return s
 }
@@ -37,7 +50,14 @@ func (s *HTTPServer) Handler() http.Handler {
 	mux.HandleFunc("/", s.handleDashboard)
 	mux.HandleFunc("/vitals", s.handleVitals)
 	mux.HandleFunc("/alerts", s.handleAlerts)
+	mux.HandleFunc("/alerts/{id}", s.handleAlert)
+	mux.HandleFunc("/alerts/{id}/ack", s.handleAcknowledgeAlert)
+	mux.HandleFunc("/alerts/{id}/resolve", s.handleResolveAlert)
+	mux.HandleFunc("/thresholds", s.handleThresholds)
+	mux.HandleFunc("/thresholds/{patient_id}", s.handlePatientThreshold)
 	mux.HandleFunc("/messages", s.handleMessages)
+	mux.HandleFunc("/messages/dead-letters", s.handleDeadLetters)
+	mux.HandleFunc("/messages/{id}/requeue", s.handleRequeueMessage)
 	mux.HandleFunc("/events", s.handleSSE)
 	return mux
 }
@@ -56,21 +76,35 @@ func (s *HTTPServer) handleVitals(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
-		patientID := r.URL.Query().Get("patient_id")
-		vitals, err := s.service.ListVitals(r.Context(), patientID)
+		params, err := parseListParams(r)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		page, err := s.service.ListVitals(r.Context(), app.ListVitalsRequest{
+			PatientID: params.patientID,
+			TakenAt:   params.takenAt,
+			Order:     params.order,
+			PageSize:  params.pageSize,
+			PageToken: params.pageToken,
+		})
 		if err != nil {
-			writeError(w, http.StatusInternalServerError, err.Error())
+			writeServiceError(w, err)
 			return
 		}
-		resp := map[string]any{"vitals": vitalsToJSON(vitals)}
+		resp := map[string]any{"vitals": vitalsToJSON(page.Vitals), "next_page_token": page.NextPageToken}
 		json.NewEncoder(w).Encode(resp)
 
 	case http.MethodPost:
 		var req struct {
-			PatientID string `json:"patient_id"`
-			Systolic  int32  `json:"systolic"`
-			Diastolic int32  `json:"diastolic"`
-			TakenAt   int64  `json:"taken_at"`
+			PatientID      string  `json:"patient_id"`
+			Kind           string  `json:"kind"`
+			Systolic       int32   `json:"systolic"`
+			Diastolic      int32   `json:"diastolic"`
+			Value          float64 `json:"value"`
+			Unit           string  `json:"unit"`
+			TakenAt        int64   `json:"taken_at"`
+			IdempotencyKey string  `json:"idempotency_key"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid request body")
@@ -80,9 +114,31 @@ func (s *HTTPServer) handleVitals(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "taken_at is required")
 			return
 		}
-		vital, err := s.service.IngestVital(r.Context(), req.PatientID, req.Systolic, req.Diastolic, time.Unix(req.TakenAt, 0).UTC())
+		kind := app.VitalKindBloodPressure
+		if req.Kind != "" {
+			var ok bool
+			if kind, ok = app.ParseVitalKind(req.Kind); !ok {
+				writeError(w, http.StatusBadRequest, "unknown kind "+req.Kind)
+				return
+			}
+		}
+		// The conventional Idempotency-Key header works too; the body wins.
+		if req.IdempotencyKey == "" {
+			req.IdempotencyKey = r.Header.Get("Idempotency-Key")
+		}
+		vital, err := s.service.IngestVital(r.Context(), app.IngestVitalRequest{
+			PatientID:      req.PatientID,
+			Kind:           kind,
+			Systolic:       req.Systolic,
+			Diastolic:      req.Diastolic,
+			Value:          req.Value,
+			Unit:           req.Unit,
+			TakenAt:        time.Unix(req.TakenAt, 0).UTC(),
+			IdempotencyKey: req.IdempotencyKey,
+			ClientID:       r.Header.Get("X-Client-ID"),
+		})
 		if err != nil {
-			writeError(w, http.StatusBadRequest, err.Error())
+			writeServiceError(w, err)
 			return
 		}
 		resp := map[string]any{"vital": vitalToJSON(vital)}
@@ -100,16 +156,202 @@ func (s *HTTPServer) handleAlerts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	patientID := r.URL.Query().Get("patient_id")
-	alerts, err := s.service.ListAlerts(r.Context(), patientID)
+	params, err := parseListParams(r)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeServiceError(w, err)
+		return
+	}
+	var statuses []app.AlertStatus
+	for _, value := range r.URL.Query()["status"] {
+		for _, name := range strings.Split(value, ",") {
+			status, ok := app.ParseAlertStatus(strings.TrimSpace(name))
+			if !ok {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown status %q", name))
+				return
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	page, err := s.service.ListAlerts(r.Context(), app.ListAlertsRequest{
+		PatientID: params.patientID,
+		Statuses:  statuses,
+		TakenAt:   params.takenAt,
+		Order:     params.order,
+		PageSize:  params.pageSize,
+		PageToken: params.pageToken,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	resp := map[string]any{"alerts": alertsToJSON(page.Alerts), "next_page_token": page.NextPageToken}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *HTTPServer) handleAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, app.ErrAlertNotFound.Error())
+		return
+	}
+	alert, history, err := s.service.GetAlert(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
 		return
 	}
-	resp := map[string]any{"alerts": alertsToJSON(alerts)}
+	resp := map[string]any{"alert": alertToJSON(alert), "history": alertHistoryToJSON(history)}
 	json.NewEncoder(w).Encode(resp)
 }
 
+func (s *HTTPServer) handleAcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, app.ErrAlertNotFound.Error())
+		return
+	}
+	var req struct {
+		Clinician string `json:"clinician"`
+		Note      string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	alert, err := s.service.AcknowledgeAlert(r.Context(), app.AcknowledgeAlertRequest{
+		AlertID:   id,
+		Clinician: req.Clinician,
+		Note:      req.Note,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"alert": alertToJSON(alert)})
+}
+
+func (s *HTTPServer) handleResolveAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, app.ErrAlertNotFound.Error())
+		return
+	}
+	var req struct {
+		Clinician string `json:"clinician"`
+		Reason    string `json:"reason"`
+		Note      string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	reason, ok := app.ParseResolutionReason(req.Reason)
+	if !ok && req.Reason != "" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown reason %q", req.Reason))
+		return
+	}
+	alert, err := s.service.ResolveAlert(r.Context(), app.ResolveAlertRequest{
+		AlertID:   id,
+		Clinician: req.Clinician,
+		Reason:    reason,
+		Note:      req.Note,
+	})
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"alert": alertToJSON(alert)})
+}
+
+func (s *HTTPServer) handleThresholds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	thresholds, err := s.service.ListPatientThresholds(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	resp := map[string]any{"thresholds": thresholdsToJSON(thresholds)}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *HTTPServer) handlePatientThreshold(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	patientID := r.PathValue("patient_id")
+
+	switch r.Method {
+	case http.MethodGet:
+		threshold, history, err := s.service.GetPatientThreshold(r.Context(), patientID)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		resp := map[string]any{
+			"threshold": thresholdToJSON(threshold),
+			"history":   thresholdsToJSON(history),
+		}
+		json.NewEncoder(w).Encode(resp)
+
+	case http.MethodPut:
+		var req struct {
+			MaxSystolic  int32  `json:"max_systolic"`
+			MaxDiastolic int32  `json:"max_diastolic"`
+			MinSystolic  int32  `json:"min_systolic"`
+			SetBy        string `json:"set_by"`
+			Reason       string `json:"reason"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		thresholds := app.Thresholds{
+			MaxSystolic:  req.MaxSystolic,
+			MaxDiastolic: req.MaxDiastolic,
+			MinSystolic:  req.MinSystolic,
+		}
+		threshold, err := s.service.SetPatientThreshold(r.Context(), patientID, thresholds, req.SetBy, req.Reason)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		resp := map[string]any{"threshold": thresholdToJSON(threshold)}
+		json.NewEncoder(w).Encode(resp)
+
+	case http.MethodDelete:
+		threshold, err := s.service.DeletePatientThreshold(r.Context(), patientID, r.URL.Query().Get("deleted_by"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		resp := map[string]any{"threshold": thresholdToJSON(threshold)}
+		json.NewEncoder(w).Encode(resp)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
 func (s *HTTPServer) handleMessages(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -122,10 +364,45 @@ func (s *HTTPServer) handleMessages(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	messages := s.messageQueue.ListMessages()
-	resp := map[string]any{"messages": messagesToJSON(messages)}
+	resp := map[string]any{"messages": messagesToJSON(messages), "depth": depthToJSON(s.messageQueue.Depth())}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *HTTPServer) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if s.messageQueue == nil {
+		json.NewEncoder(w).Encode(map[string]any{"messages": []any{}})
+		return
+	}
+	resp := map[string]any{"messages": messagesToJSON(s.messageQueue.DeadLetters())}
 	json.NewEncoder(w).Encode(resp)
 }
 
+func (s *HTTPServer) handleRequeueMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, app.ErrMessageNotFound.Error())
+		return
+	}
+	msg, err := s.service.RequeueMessage(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"message": messageToJSON(msg)})
+}
+
 func (s *HTTPServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -176,7 +453,22 @@ func (s *HTTPServer) onMessageUpdate(msg app.Message) {
 		"type":    "message_update",
 		"message": messageToJSON(msg),
 	})
+	s.broadcast(data)
+}
 
+// forwardAlertEvents pushes alert events to dashboards until the pubsub
+// closes.
+func (s *HTTPServer) forwardAlertEvents(events <-chan app.Event) {
+	for event := range events {
+		data, _ := json.Marshal(map[string]any{
+			"type":  "alert_update",
+			"alert": alertToJSON(event.Alert),
+		})
+		s.broadcast(data)
+	}
+}
+
+func (s *HTTPServer) broadcast(data []byte) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -195,15 +487,100 @@ func writeError(w http.ResponseWriter, code int, message string) {
 	json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
 
+type listParams struct {
+	patientID string
+	takenAt   app.TimeRange
+	order     app.SortOrder
+	pageSize  int
+	pageToken string
+}
+
+// parseListParams reads the paging query parameters shared by /vitals and
+// /alerts. Times are unix seconds, matching taken_at elsewhere in the API.
+func parseListParams(r *http.Request) (listParams, error) {
+	q := r.URL.Query()
+	params := listParams{
+		patientID: q.Get("patient_id"),
+		pageToken: q.Get("page_token"),
+	}
+	var err error
+	if params.order, err = app.ParseSortOrder(q.Get("order")); err != nil {
+		return listParams{}, err
+	}
+	if v := q.Get("page_size"); v != "" {
+		if params.pageSize, err = strconv.Atoi(v); err != nil {
+			return listParams{}, fmt.Errorf("%w: page_size must be an integer", app.ErrInvalidQuery)
+		}
+	}
+	bounds := []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"taken_after", &params.takenAt.After},
+		{"taken_before", &params.takenAt.Before},
+	}
+	for _, b := range bounds {
+		v := q.Get(b.name)
+		if v == "" {
+			continue
+		}
+		secs, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return listParams{}, fmt.Errorf("%w: %s must be a unix timestamp", app.ErrInvalidQuery, b.name)
+		}
+		*b.dst = time.Unix(secs, 0).UTC()
+	}
+	return params, nil
+}
+
+// writeServiceError maps domain errors onto HTTP status codes.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var invalid *app.ValidationError
+	if errors.As(err, &invalid) {
+		violations := make([]map[string]string, len(invalid.Violations))
+		for i, v := range invalid.Violations {
+			violations[i] = map[string]string{"field": v.Field, "description": v.Description}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]any{"error": err.Error(), "violations": violations})
+		return
+	}
+	switch {
+	case errors.Is(err, app.ErrInvalidVital), errors.Is(err, app.ErrInvalidThreshold), errors.Is(err, app.ErrInvalidQuery),
+		errors.Is(err, app.ErrInvalidAlertAction):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, app.ErrThresholdNotFound), errors.Is(err, app.ErrAlertNotFound), errors.Is(err, app.ErrMessageNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, app.ErrIdempotencyKeyReused), errors.Is(err, app.ErrInvalidAlertTransition), errors.Is(err, app.ErrAlertConflict),
+		errors.Is(err, app.ErrMessageNotFailed), errors.Is(err, app.ErrAlertClosed):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 func vitalToJSON(v app.Vital) map[string]any {
 	return map[string]any{
 		"id":          v.ID,
 		"patient_id":  v.PatientID,
+		"kind":        v.Kind.String(),
 		"systolic":    v.Systolic,
 		"diastolic":   v.Diastolic,
+		"value":       v.Value,
+		"unit":        v.Kind.Unit(),
 		"taken_at":    v.TakenAt.Unix(),
 		"received_at": v.ReceivedAt.Unix(),
+		"flags":       qualityFlagsToJSON(v.Flags),
+	}
+}
+
+func qualityFlagsToJSON(flags []app.QualityFlag) []string {
+	names := make([]string, len(flags))
+	for i, flag := range flags {
+		names[i] = string(flag)
 	}
+	return names
 }
 
 func vitalsToJSON(vitals []app.Vital) []map[string]any {
@@ -220,17 +597,80 @@ func alertToJSON(a app.Alert) map[string]any {
 		"vital": map[string]any{
 			"id":          a.VitalID,
 			"patient_id":  a.PatientID,
+			"kind":        a.Kind.String(),
 			"systolic":    a.Systolic,
 			"diastolic":   a.Diastolic,
+			"value":       a.Value,
+			"unit":        a.Kind.Unit(),
 			"taken_at":    a.TakenAt.Unix(),
 			"received_at": a.ReceivedAt.Unix(),
 		},
 		"reason":     a.Reason,
+		"severity":   a.Severity.String(),
 		"created_at": a.Created.Unix(),
 		"status":     alertStatusString(a.Status),
+
+		"threshold_version": a.ThresholdVersion,
+		"acknowledgement":   alertActionToJSON(a.Acknowledgement),
+		"resolution":        alertActionToJSON(a.Resolution),
+		"resolution_reason": a.ResolutionReason.String(),
+		"escalations":       escalationsToJSON(a.Escalations),
 	}
 }
 
+func alertHistoryToJSON(history []app.AlertHistoryEntry) []map[string]any {
+	result := make([]map[string]any, len(history))
+	for i, e := range history {
+		entry := map[string]any{
+			"id":   e.ID,
+			"type": string(e.Type),
+			"at":   e.At.Unix(),
+		}
+		if e.RecordsStatus() {
+			entry["from_status"] = alertStatusString(e.FromStatus)
+			entry["status"] = alertStatusString(e.Status)
+		}
+		if e.VitalID != 0 {
+			entry["vital_id"] = e.VitalID
+		}
+		if e.MessageID != 0 {
+			entry["message_id"] = e.MessageID
+		}
+		if e.MessageID != 0 || e.Type == app.AlertHistoryEscalated {
+			entry["recipient"] = e.Recipient.String()
+		}
+		if e.Actor != "" {
+			entry["actor"] = e.Actor
+			entry["note"] = e.Note
+		}
+		if e.Type == app.AlertHistoryResolved {
+			entry["resolution_reason"] = e.ResolutionReason.String()
+		}
+		result[i] = entry
+	}
+	return result
+}
+
+func escalationsToJSON(steps []app.EscalationStep) []map[string]any {
+	result := make([]map[string]any, len(steps))
+	for i, step := range steps {
+		result[i] = map[string]any{
+			"tier":       step.Tier,
+			"recipient":  step.Recipient.String(),
+			"at":         step.At.Unix(),
+			"message_id": step.MessageID,
+		}
+	}
+	return result
+}
+
+func alertActionToJSON(action app.AlertAction) map[string]any {
+	if action.By == "" {
+		return nil
+	}
+	return map[string]any{"clinician": action.By, "at": action.At.Unix(), "note": action.Note}
+}
+
 func alertsToJSON(alerts []app.Alert) []map[string]any {
 	result := make([]map[string]any, len(alerts))
 	for i, a := range alerts {
@@ -249,24 +689,83 @@ func alertStatusString(s app.AlertStatus) string {
 		return "RESOLVED_BY_RETAKE"
 	case app.AlertStatusConfirmedAbnormal:
 		return "CONFIRMED_ABNORMAL"
+	case app.AlertStatusAcknowledged:
+		return "ACKNOWLEDGED"
+	case app.AlertStatusResolved:
+		return "RESOLVED"
 	default:
 		return "ACTIVE"
 	}
 }
 
+func thresholdToJSON(t app.PatientThreshold) map[string]any {
+	return map[string]any{
+		"patient_id":    t.PatientID,
+		"max_systolic":  t.Thresholds.MaxSystolic,
+		"max_diastolic": t.Thresholds.MaxDiastolic,
+		"min_systolic":  t.Thresholds.MinSystolic,
+		"version":       t.Version,
+		"set_by":        t.SetBy,
+		"set_at":        t.SetAt.Unix(),
+		"reason":        t.Reason,
+		"deleted":       t.Deleted,
+	}
+}
+
+func thresholdsToJSON(thresholds []app.PatientThreshold) []map[string]any {
+	result := make([]map[string]any, len(thresholds))
+	for i, t := range thresholds {
+		result[i] = thresholdToJSON(t)
+	}
+	return result
+}
+
 func messageToJSON(m app.Message) map[string]any {
 	result := map[string]any{
 		"id":         m.ID,
 		"patient_id": m.PatientID,
+		"recipient":  m.Recipient.String(),
+		"alert_id":   m.AlertID,
+		"subject":    m.Subject,
 		"content":    m.Content,
+		"priority":   m.Priority.String(),
 		"status":     m.Status.String(),
 		"queued_at":  m.QueuedAt.Unix(),
+		"attempts":   m.Attempts,
+		"last_error": m.LastError,
+		"channel":    m.Channel,
+	}
+	if m.ProviderMessageID != "" {
+		result["provider_message_id"] = m.ProviderMessageID
 	}
 	if !m.SentAt.IsZero() {
 		result["sent_at"] = m.SentAt.Unix()
 	} else {
 		result["sent_at"] = 0
 	}
+	if !m.CancelledAt.IsZero() {
+		result["cancelled_at"] = m.CancelledAt.Unix()
+	} else {
+		result["cancelled_at"] = 0
+	}
+	if !m.ScheduledFor.IsZero() {
+		result["scheduled_for"] = m.ScheduledFor.Unix()
+	}
+	if !m.NextAttemptAt.IsZero() {
+		result["next_attempt_at"] = m.NextAttemptAt.Unix()
+	}
+	if !m.FailedAt.IsZero() {
+		result["failed_at"] = m.FailedAt.Unix()
+	}
+	return result
+}
+
+// depthToJSON keys queue depth by priority name.
+func depthToJSON(depth map[app.MessagePriority]int) map[string]int {
+	result := make(map[string]int, len(depth))
+	for priority, n := range depth {
+		result[priority.String()] = n
+	}
 	return result
 }
 
@@ -300,6 +799,8 @@ const dashboardHTML = `
         .status.QUEUED { background: #fff3e0; color: #e65100; }
         .status.PROCESSING { background: #e3f2fd; color: #1565c0; }
         .status.SENT { background: #e8f5e9; color: #2e7d32; }
+        .status.CANCELLED { background: #eeeeee; color: #616161; }
+        .status.FAILED { background: #ffebee; color: #c62828; }
         .full-width { grid-column: 1 / -1; }
         .quick-buttons { display: flex; gap: 10px; margin-bottom: 15px; }
         .btn { padding: 10px 20px; border: none; border-radius: 6px; cursor: pointer; font-size: 14px; font-weight: 500; }
@@ -315,6 +816,12 @@ const dashboardHTML = `
         .form-row input[name="patient_id"] { width: 150px; }
         .empty { color: #999; font-style: italic; padding: 20px; text-align: center; }
         .time { color: #999; font-size: 12px; }
+        .depth { display: flex; gap: 16px; font-size: 13px; color: #666; margin-bottom: 8px; }
+        .priority { font-size: 12px; font-weight: 600; }
+        .priority.CRITICAL { color: #c62828; }
+        .priority.HIGH { color: #e65100; }
+        .priority.LOW { color: #999; }
+        .btn-small { padding: 2px 8px; border: 1px solid #ddd; border-radius: 4px; background: white; cursor: pointer; font-size: 12px; }
     </style>
 </head>
 <body>
@@ -346,7 +853,7 @@ const dashboardHTML = `
                 </div>
             </div>
             <div class="card">
-                <h2>Active Alerts</h2>
+                <h2>Alerts</h2>
                 <div class="list" id="alerts-list">
                     <div class="empty">No alerts</div>
                 </div>
@@ -355,6 +862,7 @@ const dashboardHTML = `
 
         <div class="card full-width">
             <h2>Message Queue</h2>
+            <div class="depth" id="queue-depth"></div>
             <div class="list" id="messages-list">
                 <div class="empty">No messages queued</div>
             </div>
@@ -400,17 +908,25 @@ const dashboardHTML = `
             sendVital(patientId, systolic, diastolic);
         }
 
+        function measurement(v) {
+            if (v.kind === 'BLOOD_PRESSURE') {
+                return v.systolic + '/' + v.diastolic;
+            }
+            return v.kind.toLowerCase() + ' ' + v.value + ' ' + v.unit;
+        }
+
         function renderVitals(vitals) {
             const list = document.getElementById('vitals-list');
             if (!vitals.length) {
                 list.innerHTML = '<div class="empty">No vitals yet</div>';
                 return;
             }
-            list.innerHTML = vitals.slice().reverse().map(v => {
-                const isAbnormal = v.systolic > 180 || v.diastolic > 120;
+            list.innerHTML = vitals.map(v => {
+                const isBP = v.kind === 'BLOOD_PRESSURE';
+                const isAbnormal = isBP && (v.systolic > 180 || v.diastolic > 120);
                 return '<div class="item ' + (isAbnormal ? 'abnormal' : 'normal') + '">' +
-                    '<strong>' + v.patient_id + '</strong>: ' + v.systolic + '/' + v.diastolic +
-                    (isAbnormal ? ' ⚠️' : ' ✓') +
+                    '<strong>' + v.patient_id + '</strong>: ' + measurement(v) +
+                    (isAbnormal ? ' ⚠️' : isBP ? ' ✓' : '') +
                     ' <span class="time">' + formatTime(v.received_at) + '</span>' +
                 '</div>';
             }).join('');
@@ -422,12 +938,42 @@ const dashboardHTML = `
                 list.innerHTML = '<div class="empty">No alerts</div>';
                 return;
             }
-            list.innerHTML = alerts.slice().reverse().map(a =>
-                '<div class="item abnormal">' +
-                    '<strong>' + a.vital.patient_id + '</strong>: ' + a.vital.systolic + '/' + a.vital.diastolic +
+            list.innerHTML = alerts.map(a => {
+                const open = a.status === 'ACTIVE' || a.status === 'ACKNOWLEDGED' || a.status === 'CONFIRMED_ABNORMAL';
+                return '<div class="item ' + (open ? 'abnormal' : 'normal') + '">' +
+                    '<span class="status">' + a.status + '</span> ' +
+                    '<strong>' + a.vital.patient_id + '</strong>: ' + measurement(a.vital) +
                     ' <span class="time">' + formatTime(a.created_at) + '</span>' +
-                '</div>'
-            ).join('');
+                    (a.status === 'ACTIVE' || a.status === 'CONFIRMED_ABNORMAL' ?
+                        ' <button class="btn-small" onclick="acknowledgeAlert(' + a.id + ')">Ack</button>' : '') +
+                    (open ? ' <button class="btn-small" onclick="resolveAlert(' + a.id + ')">Resolve</button>' : '') +
+                '</div>';
+            }).join('');
+        }
+
+        function actOnAlert(id, action, body) {
+            fetch('/alerts/' + id + '/' + action, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify(body)
+            }).then(r => r.json()).then(data => {
+                if (data.error) alert(data.error);
+                refreshData();
+            });
+        }
+
+        function acknowledgeAlert(id) {
+            const clinician = prompt('Clinician');
+            if (!clinician) return;
+            actOnAlert(id, 'ack', { clinician: clinician, note: prompt('Note (optional)') || '' });
+        }
+
+        function resolveAlert(id) {
+            const clinician = prompt('Clinician');
+            if (!clinician) return;
+            const reason = prompt('Reason: TREATED, FALSE_ALARM, MEASUREMENT_ERROR, DUPLICATE or OTHER', 'TREATED');
+            if (!reason) return;
+            actOnAlert(id, 'resolve', { clinician: clinician, reason: reason, note: prompt('Note (optional)') || '' });
         }
 
         function renderMessages(messages) {
@@ -439,23 +985,45 @@ const dashboardHTML = `
             list.innerHTML = messages.slice().reverse().map(m =>
                 '<div class="item">' +
                     '<span class="status ' + m.status + '">' + m.status + '</span> ' +
+                    (m.priority !== 'NORMAL' ? '<span class="priority ' + m.priority + '">' + m.priority + '</span> ' : '') +
+                    (m.recipient !== 'PATIENT' ? '<em>' + m.recipient + '</em> ' : '') +
+                    (m.channel ? '<em>via ' + m.channel + '</em> ' : '') +
                     '<strong>' + m.patient_id + '</strong>: ' + m.content +
-                    ' <span class="time">' + formatTime(m.status === 'SENT' ? m.sent_at : m.queued_at) + '</span>' +
+                    ' <span class="time">' + formatTime(m.status === 'SENT' ? m.sent_at : (m.status === 'CANCELLED' ? m.cancelled_at : (m.status === 'FAILED' ? m.failed_at : m.queued_at))) + '</span>' +
+                    (m.status === 'QUEUED' && m.scheduled_for ? ' <span class="time">scheduled for ' + formatTime(m.scheduled_for) + '</span>' : '') +
+                    ((m.status === 'QUEUED' || m.status === 'FAILED') && m.last_error ? ' <span class="time">' + m.attempts + ' failed: ' + m.last_error + '</span>' : '') +
+                    (m.status === 'FAILED' ? ' <button class="btn-small" onclick="requeueMessage(' + m.id + ')">Requeue</button>' : '') +
                 '</div>'
             ).join('');
         }
 
+        function renderDepth(depth) {
+            document.getElementById('queue-depth').innerHTML = ['CRITICAL', 'HIGH', 'NORMAL', 'LOW'].map(p =>
+                '<span><span class="priority ' + p + '">' + p + '</span> ' + (depth[p] || 0) + ' queued</span>'
+            ).join('');
+        }
+
+        function requeueMessage(id) {
+            fetch('/messages/' + id + '/requeue', { method: 'POST' }).then(r => r.json()).then(data => {
+                if (data.error) alert(data.error);
+                refreshData();
+            });
+        }
+
         function refreshData() {
-            fetch('/vitals').then(r => r.json()).then(data => renderVitals(data.vitals || []));
-            fetch('/alerts').then(r => r.json()).then(data => renderAlerts(data.alerts || []));
-            fetch('/messages').then(r => r.json()).then(data => renderMessages(data.messages || []));
+            fetch('/vitals?order=desc&page_size=50').then(r => r.json()).then(data => renderVitals(data.vitals || []));
+            fetch('/alerts?order=desc&page_size=50').then(r => r.json()).then(data => renderAlerts(data.alerts || []));
+            fetch('/messages').then(r => r.json()).then(data => {
+                renderMessages(data.messages || []);
+                renderDepth(data.depth || {});
+            });
         }
 
         // Server-Sent Events for real-time updates
         const eventSource = new EventSource('/events');
         eventSource.onmessage = function(event) {
             const data = JSON.parse(event.data);
-            if (data.type === 'message_update') {
+            if (data.type === 'message_update' || data.type === 'alert_update') {
                 refreshData();
             }
         };
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"cadence-vitals-interview/internal/app"
+	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) BatchIngestVitals(ctx context.Context, req *vitalsv1.BatchIngestVitalsRequest) (*vitalsv1.BatchIngestVitalsResponse, error) {
+	reqs := make([]app.IngestVitalRequest, 0, len(req.GetVitals()))
+	client := clientID(ctx)
+	for _, vital := range req.GetVitals() {
+		reqs = append(reqs, fromProtoIngestVitalRequest(vital, client))
+	}
+	return s.ingestBatch(ctx, reqs)
+}
+
+func (s *Server) StreamIngestVitals(stream grpc.ClientStreamingServer[vitalsv1.IngestVitalRequest, vitalsv1.BatchIngestVitalsResponse]) error {
+	var reqs []app.IngestVitalRequest
+	client := clientID(stream.Context())
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(reqs) == app.MaxIngestBatch {
+			return status.Errorf(codes.InvalidArgument, "stream has more than %d readings", app.MaxIngestBatch)
+		}
+		reqs = append(reqs, fromProtoIngestVitalRequest(req, client))
+	}
+	resp, err := s.ingestBatch(stream.Context(), reqs)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+func (s *Server) ingestBatch(ctx context.Context, reqs []app.IngestVitalRequest) (*vitalsv1.BatchIngestVitalsResponse, error) {
+	results, err := s.service.IngestVitals(ctx, reqs)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &vitalsv1.BatchIngestVitalsResponse{Results: make([]*vitalsv1.IngestVitalResult, 0, len(results))}
+	for i, result := range results {
+		out := &vitalsv1.IngestVitalResult{Index: int32(i)}
+		if result.Err != nil {
+			st := status.Convert(statusError(result.Err))
+			out.ErrorCode = int32(st.Code())
+			out.ErrorMessage = st.Message()
+			resp.RejectedCount++
+		} else {
+			out.Vital = toProtoVital(result.Vital)
+			resp.AcceptedCount++
+		}
+		resp.Results = append(resp.Results, out)
+	}
+	return resp, nil
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cadence-vitals-interview/internal/app"
+	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func reading(patientID string, takenAt time.Time, key string) *vitalsv1.IngestVitalRequest {
+	return &vitalsv1.IngestVitalRequest{
+		PatientId:      patientID,
+		Systolic:       120,
+		Diastolic:      80,
+		TakenAt:        takenAt.Unix(),
+		IdempotencyKey: key,
+	}
+}
+
+func TestBatchIngestVitalsReportsEachReading(t *testing.T) {
+	client := startServer(t, app.NewService(app.NewInMemoryStore()), app.NewPubSub())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-client-id", "clinic-app")
+	now := time.Now()
+
+	if _, err := client.IngestVital(ctx, reading("patient-1", now.Add(-time.Hour), "key-1")); err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	reused := reading("patient-1", now.Add(-time.Hour), "key-1")
+	reused.Systolic = 150
+
+	resp, err := client.BatchIngestVitals(ctx, &vitalsv1.BatchIngestVitalsRequest{Vitals: []*vitalsv1.IngestVitalRequest{
+		reading("patient-1", now.Add(-time.Minute), ""),
+		reading("", now.Add(-time.Minute), ""),
+		reused,
+		// Taken before the first reading; stored first, reported in place.
+		reading("patient-1", now.Add(-30*time.Minute), ""),
+	}})
+	if err != nil {
+		t.Fatalf("batch: %v", err)
+	}
+	if resp.GetAcceptedCount() != 2 || resp.GetRejectedCount() != 2 {
+		t.Fatalf("expected 2 accepted and 2 rejected, got %d and %d", resp.GetAcceptedCount(), resp.GetRejectedCount())
+	}
+	want := []codes.Code{codes.OK, codes.InvalidArgument, codes.AlreadyExists, codes.OK}
+	if len(resp.GetResults()) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(resp.GetResults()))
+	}
+	for i, result := range resp.GetResults() {
+		if result.GetIndex() != int32(i) || codes.Code(result.GetErrorCode()) != want[i] {
+			t.Fatalf("result %d: expected index %d with %s, got %v", i, i, want[i], result)
+		}
+		if stored := result.GetVital() != nil; stored != (want[i] == codes.OK) {
+			t.Fatalf("result %d: vital set = %v with code %s", i, stored, want[i])
+		}
+	}
+	if first, last := resp.GetResults()[0].GetVital(), resp.GetResults()[3].GetVital(); last.GetId() >= first.GetId() {
+		t.Fatalf("expected the earlier reading stored first, got ids %d then %d", first.GetId(), last.GetId())
+	}
+	if resp.GetResults()[1].GetErrorMessage() == "" {
+		t.Fatalf("expected a message for the rejected reading")
+	}
+}
+
+func TestStreamIngestVitals(t *testing.T) {
+	client := startServer(t, app.NewService(app.NewInMemoryStore()), app.NewPubSub())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	now := time.Now()
+
+	stream, err := client.StreamIngestVitals(ctx)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	for i := range 2 {
+		if err := stream.Send(reading("patient-1", now.Add(time.Duration(i-2)*time.Minute), "")); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if resp.GetAcceptedCount() != 2 || resp.GetRejectedCount() != 0 || len(resp.GetResults()) != 2 {
+		t.Fatalf("expected both readings accepted, got %v", resp)
+	}
+}
+
+func TestStreamIngestVitalsCapsReadings(t *testing.T) {
+	client := startServer(t, app.NewService(app.NewInMemoryStore()), app.NewPubSub())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	now := time.Now()
+
+	stream, err := client.StreamIngestVitals(ctx)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	for range app.MaxIngestBatch + 1 {
+		// The server may reject the stream before every reading is sent.
+		if err := stream.Send(reading("patient-1", now.Add(-time.Minute), "")); err != nil {
+			break
+		}
+	}
+	if _, err := stream.CloseAndRecv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected INVALID_ARGUMENT past %d readings, got %v", app.MaxIngestBatch, err)
+	}
+}
@@ -3,77 +3,222 @@ package api
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"cadence-vitals-interview/internal/app"
 	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 type Server struct {
 	vitalsv1.UnimplementedVitalsServiceServer
 	service *app.Service
+	events  *app.PubSub
 }
 
-func NewServer(service *app.Service) *Server {
-	return &Server{service: service}
+func NewServer(service *app.Service, events *app.PubSub) *Server {
+	return &Server{service: service, events: events}
 }
 
 func (s *Server) IngestVital(ctx context.Context, req *vitalsv1.IngestVitalRequest) (*vitalsv1.IngestVitalResponse, error) {
 	if req == nil {
 		return nil, status.Error(codes.InvalidArgument, "request is required")
 	}
-	takenAt := req.GetTakenAt()
-	if takenAt <= 0 {
+	if req.GetTakenAt() <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "taken_at is required")
 	}
-	vital, err := s.service.IngestVital(ctx, req.GetPatientId(), req.GetSystolic(), req.GetDiastolic(), time.Unix(takenAt, 0).UTC())
+	vital, err := s.service.IngestVital(ctx, fromProtoIngestVitalRequest(req, clientID(ctx)))
 	if err != nil {
-		if errors.Is(err, app.ErrInvalidVital) {
-			return nil, status.Error(codes.InvalidArgument, err.Error())
-		}
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 	return &vitalsv1.IngestVitalResponse{Vital: toProtoVital(vital)}, nil
 }
 
 func (s *Server) ListAlerts(ctx context.Context, req *vitalsv1.ListAlertsRequest) (*vitalsv1.ListAlertsResponse, error) {
-	var patientID string
-	if req != nil {
-		patientID = req.GetPatientId()
-	}
-	alerts, err := s.service.ListAlerts(ctx, patientID)
+	page, err := s.service.ListAlerts(ctx, app.ListAlertsRequest{
+		PatientID: req.GetPatientId(),
+		Statuses:  fromProtoAlertFilter(req.GetStates(), req.GetStatuses()),
+		TakenAt:   fromProtoTimeRange(req.GetTakenAfter(), req.GetTakenBefore()),
+		Order:     fromProtoSortOrder(req.GetOrder()),
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 	resp := &vitalsv1.ListAlertsResponse{
-		Alerts: make([]*vitalsv1.Alert, 0, len(alerts)),
+		Alerts:        make([]*vitalsv1.Alert, 0, len(page.Alerts)),
+		NextPageToken: page.NextPageToken,
 	}
-	for _, alert := range alerts {
+	for _, alert := range page.Alerts {
 		resp.Alerts = append(resp.Alerts, toProtoAlert(alert))
 	}
 	return resp, nil
 }
 
 func (s *Server) ListVitals(ctx context.Context, req *vitalsv1.ListVitalsRequest) (*vitalsv1.ListVitalsResponse, error) {
-	var patientID string
-	if req != nil {
-		patientID = req.GetPatientId()
-	}
-	vitals, err := s.service.ListVitals(ctx, patientID)
+	page, err := s.service.ListVitals(ctx, app.ListVitalsRequest{
+		PatientID: req.GetPatientId(),
+		TakenAt:   fromProtoTimeRange(req.GetTakenAfter(), req.GetTakenBefore()),
+		Order:     fromProtoSortOrder(req.GetOrder()),
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, statusError(err)
 	}
 	resp := &vitalsv1.ListVitalsResponse{
-		Vitals: make([]*vitalsv1.Vital, 0, len(vitals)),
+		Vitals:        make([]*vitalsv1.Vital, 0, len(page.Vitals)),
+		NextPageToken: page.NextPageToken,
 	}
-	for _, vital := range vitals {
+	for _, vital := range page.Vitals {
 		resp.Vitals = append(resp.Vitals, toProtoVital(vital))
 	}
 	return resp, nil
 }
 
+func (s *Server) GetAlert(ctx context.Context, req *vitalsv1.GetAlertRequest) (*vitalsv1.GetAlertResponse, error) {
+	alert, history, err := s.service.GetAlert(ctx, req.GetAlertId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &vitalsv1.GetAlertResponse{
+		Alert:   toProtoAlert(alert),
+		History: make([]*vitalsv1.AlertHistoryEntry, 0, len(history)),
+	}
+	for _, entry := range history {
+		resp.History = append(resp.History, toProtoAlertHistoryEntry(entry))
+	}
+	return resp, nil
+}
+
+func (s *Server) AcknowledgeAlert(ctx context.Context, req *vitalsv1.AcknowledgeAlertRequest) (*vitalsv1.AcknowledgeAlertResponse, error) {
+	alert, err := s.service.AcknowledgeAlert(ctx, app.AcknowledgeAlertRequest{
+		AlertID:   req.GetAlertId(),
+		Clinician: req.GetClinician(),
+		Note:      req.GetNote(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &vitalsv1.AcknowledgeAlertResponse{Alert: toProtoAlert(alert)}, nil
+}
+
+func (s *Server) ResolveAlert(ctx context.Context, req *vitalsv1.ResolveAlertRequest) (*vitalsv1.ResolveAlertResponse, error) {
+	alert, err := s.service.ResolveAlert(ctx, app.ResolveAlertRequest{
+		AlertID:   req.GetAlertId(),
+		Clinician: req.GetClinician(),
+		Reason:    app.ResolutionReason(req.GetReason()),
+		Note:      req.GetNote(),
+	})
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &vitalsv1.ResolveAlertResponse{Alert: toProtoAlert(alert)}, nil
+}
+
+func (s *Server) SetPatientThreshold(ctx context.Context, req *vitalsv1.SetPatientThresholdRequest) (*vitalsv1.SetPatientThresholdResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+	threshold, err := s.service.SetPatientThreshold(ctx, req.GetPatientId(), fromProtoThresholds(req.GetThresholds()), req.GetSetBy(), req.GetReason())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &vitalsv1.SetPatientThresholdResponse{Threshold: toProtoPatientThreshold(threshold)}, nil
+}
+
+func (s *Server) GetPatientThreshold(ctx context.Context, req *vitalsv1.GetPatientThresholdRequest) (*vitalsv1.GetPatientThresholdResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+	threshold, history, err := s.service.GetPatientThreshold(ctx, req.GetPatientId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &vitalsv1.GetPatientThresholdResponse{
+		Threshold: toProtoPatientThreshold(threshold),
+		History:   make([]*vitalsv1.PatientThreshold, 0, len(history)),
+	}
+	for _, version := range history {
+		resp.History = append(resp.History, toProtoPatientThreshold(version))
+	}
+	return resp, nil
+}
+
+func (s *Server) ListPatientThresholds(ctx context.Context, _ *vitalsv1.ListPatientThresholdsRequest) (*vitalsv1.ListPatientThresholdsResponse, error) {
+	thresholds, err := s.service.ListPatientThresholds(ctx)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &vitalsv1.ListPatientThresholdsResponse{
+		Thresholds: make([]*vitalsv1.PatientThreshold, 0, len(thresholds)),
+	}
+	for _, threshold := range thresholds {
+		resp.Thresholds = append(resp.Thresholds, toProtoPatientThreshold(threshold))
+	}
+	return resp, nil
+}
+
+func (s *Server) DeletePatientThreshold(ctx context.Context, req *vitalsv1.DeletePatientThresholdRequest) (*vitalsv1.DeletePatientThresholdResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "request is required")
+	}
+	threshold, err := s.service.DeletePatientThreshold(ctx, req.GetPatientId(), req.GetDeletedBy())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &vitalsv1.DeletePatientThresholdResponse{Threshold: toProtoPatientThreshold(threshold)}, nil
+}
+
+// statusError maps domain errors onto gRPC status codes.
+func statusError(err error) error {
+	var invalid *app.ValidationError
+	if errors.As(err, &invalid) {
+		return validationStatus(invalid)
+	}
+	switch {
+	case errors.Is(err, app.ErrInvalidVital), errors.Is(err, app.ErrInvalidThreshold), errors.Is(err, app.ErrInvalidQuery),
+		errors.Is(err, app.ErrInvalidAlertAction):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, app.ErrThresholdNotFound), errors.Is(err, app.ErrAlertNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, app.ErrInvalidAlertTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, app.ErrAlertConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, app.ErrIdempotencyKeyReused):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, app.ErrResumeTokenExpired):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// validationStatus reports every violated field as a google.rpc.BadRequest
+// detail alongside the INVALID_ARGUMENT status.
+func validationStatus(err *app.ValidationError) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+	detail := &errdetails.BadRequest{}
+	for _, v := range err.Violations {
+		detail.FieldViolations = append(detail.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	if withDetails, detailErr := st.WithDetails(detail); detailErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
 func toProtoVital(vital app.Vital) *vitalsv1.Vital {
 	return &vitalsv1.Vital{
 		Id:         vital.ID,
@@ -82,7 +227,22 @@ func toProtoVital(vital app.Vital) *vitalsv1.Vital {
 		Diastolic:  vital.Diastolic,
 		TakenAt:    vital.TakenAt.Unix(),
 		ReceivedAt: vital.ReceivedAt.Unix(),
+		Kind:       toProtoVitalKind(vital.Kind),
+		Value:      vital.Value,
+		Unit:       vital.Kind.Unit(),
+
+		QualityFlags: toProtoQualityFlags(vital.Flags),
+	}
+}
+
+func toProtoQualityFlags(flags []app.QualityFlag) []vitalsv1.QualityFlag {
+	var out []vitalsv1.QualityFlag
+	for _, flag := range flags {
+		if v, ok := vitalsv1.QualityFlag_value["QUALITY_FLAG_"+string(flag)]; ok {
+			out = append(out, vitalsv1.QualityFlag(v))
+		}
 	}
+	return out
 }
 
 func toProtoAlert(alert app.Alert) *vitalsv1.Alert {
@@ -95,22 +255,235 @@ func toProtoAlert(alert app.Alert) *vitalsv1.Alert {
 			Diastolic:  alert.Diastolic,
 			TakenAt:    alert.TakenAt.Unix(),
 			ReceivedAt: alert.ReceivedAt.Unix(),
+			Kind:       toProtoVitalKind(alert.Kind),
+			Value:      alert.Value,
+			Unit:       alert.Kind.Unit(),
 		},
 		Reason:    alert.Reason,
 		CreatedAt: alert.Created.Unix(),
 		Status:    toProtoAlertStatus(alert.Status),
+		State:     toProtoAlertState(alert.Status),
+		Severity:  toProtoSeverity(alert.Severity),
+
+		ThresholdVersion: alert.ThresholdVersion,
+		Acknowledgement:  toProtoAlertAction(alert.Acknowledgement),
+		Resolution:       toProtoAlertAction(alert.Resolution),
+		ResolutionReason: vitalsv1.ResolutionReason(alert.ResolutionReason),
+	}
+}
+
+var protoAlertHistoryTypes = map[app.AlertHistoryType]vitalsv1.AlertHistoryType{
+	app.AlertHistoryCreated:               vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_CREATED,
+	app.AlertHistoryVitalAttached:         vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_VITAL_ATTACHED,
+	app.AlertHistoryStatusChanged:         vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_STATUS_CHANGED,
+	app.AlertHistoryAcknowledged:          vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_ACKNOWLEDGED,
+	app.AlertHistoryResolved:              vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_RESOLVED,
+	app.AlertHistoryEscalated:             vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_ESCALATED,
+	app.AlertHistoryNotificationQueued:    vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_QUEUED,
+	app.AlertHistoryNotificationSent:      vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_SENT,
+	app.AlertHistoryNotificationCancelled: vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_CANCELLED,
+	app.AlertHistoryNotificationFailed:    vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_FAILED,
+	app.AlertHistoryNotificationThrottled: vitalsv1.AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_THROTTLED,
+}
+
+func toProtoAlertHistoryEntry(entry app.AlertHistoryEntry) *vitalsv1.AlertHistoryEntry {
+	out := &vitalsv1.AlertHistoryEntry{
+		Id:               entry.ID,
+		Type:             protoAlertHistoryTypes[entry.Type],
+		At:               entry.At.Unix(),
+		VitalId:          entry.VitalID,
+		MessageId:        entry.MessageID,
+		Actor:            entry.Actor,
+		Note:             entry.Note,
+		ResolutionReason: vitalsv1.ResolutionReason(entry.ResolutionReason),
+	}
+	if entry.RecordsStatus() {
+		out.FromState = toProtoAlertState(entry.FromStatus)
+		out.State = toProtoAlertState(entry.Status)
+	}
+	if entry.MessageID != 0 || entry.Type == app.AlertHistoryEscalated {
+		out.Recipient = toProtoRecipient(entry.Recipient)
+	}
+	return out
+}
+
+func toProtoRecipient(r app.Recipient) vitalsv1.Recipient {
+	switch r {
+	case app.RecipientPatient:
+		return vitalsv1.Recipient_RECIPIENT_PATIENT
+	case app.RecipientOnCallNurse:
+		return vitalsv1.Recipient_RECIPIENT_ON_CALL_NURSE
+	case app.RecipientPhysician:
+		return vitalsv1.Recipient_RECIPIENT_PHYSICIAN
+	default:
+		return vitalsv1.Recipient_RECIPIENT_UNSPECIFIED
+	}
+}
+
+func toProtoAlertAction(action app.AlertAction) *vitalsv1.AlertAction {
+	if action.By == "" {
+		return nil
+	}
+	return &vitalsv1.AlertAction{Clinician: action.By, At: action.At.Unix(), Note: action.Note}
+}
+
+func toProtoPatientThreshold(threshold app.PatientThreshold) *vitalsv1.PatientThreshold {
+	return &vitalsv1.PatientThreshold{
+		PatientId: threshold.PatientID,
+		Thresholds: &vitalsv1.Thresholds{
+			MaxSystolic:  threshold.Thresholds.MaxSystolic,
+			MaxDiastolic: threshold.Thresholds.MaxDiastolic,
+			MinSystolic:  threshold.Thresholds.MinSystolic,
+		},
+		Version: threshold.Version,
+		SetBy:   threshold.SetBy,
+		SetAt:   threshold.SetAt.Unix(),
+		Reason:  threshold.Reason,
+		Deleted: threshold.Deleted,
+	}
+}
+
+// clientIDMetadata names the caller; ingestion scopes idempotency keys to it.
+const clientIDMetadata = "x-client-id"
+
+func clientID(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, clientIDMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func fromProtoIngestVitalRequest(req *vitalsv1.IngestVitalRequest, clientID string) app.IngestVitalRequest {
+	var takenAt time.Time
+	if t := req.GetTakenAt(); t > 0 {
+		takenAt = time.Unix(t, 0).UTC()
+	}
+	return app.IngestVitalRequest{
+		PatientID:      req.GetPatientId(),
+		Kind:           fromProtoVitalKind(req.GetKind()),
+		Systolic:       req.GetSystolic(),
+		Diastolic:      req.GetDiastolic(),
+		Value:          req.GetValue(),
+		Unit:           req.GetUnit(),
+		TakenAt:        takenAt,
+		IdempotencyKey: req.GetIdempotencyKey(),
+		ClientID:       clientID,
 	}
 }
 
+func toProtoVitalKind(kind app.VitalKind) vitalsv1.VitalKind {
+	switch kind {
+	case app.VitalKindPulse:
+		return vitalsv1.VitalKind_VITAL_KIND_PULSE
+	case app.VitalKindSpO2:
+		return vitalsv1.VitalKind_VITAL_KIND_SPO2
+	case app.VitalKindWeight:
+		return vitalsv1.VitalKind_VITAL_KIND_WEIGHT
+	case app.VitalKindGlucose:
+		return vitalsv1.VitalKind_VITAL_KIND_GLUCOSE
+	default:
+		return vitalsv1.VitalKind_VITAL_KIND_BLOOD_PRESSURE
+	}
+}
+
+// fromProtoVitalKind passes unknown kinds through as invalid app kinds so
+// ingestion rejects them rather than misreading them as blood pressure.
+func fromProtoVitalKind(kind vitalsv1.VitalKind) app.VitalKind {
+	switch kind {
+	case vitalsv1.VitalKind_VITAL_KIND_UNSPECIFIED, vitalsv1.VitalKind_VITAL_KIND_BLOOD_PRESSURE:
+		return app.VitalKindBloodPressure
+	case vitalsv1.VitalKind_VITAL_KIND_PULSE:
+		return app.VitalKindPulse
+	case vitalsv1.VitalKind_VITAL_KIND_SPO2:
+		return app.VitalKindSpO2
+	case vitalsv1.VitalKind_VITAL_KIND_WEIGHT:
+		return app.VitalKindWeight
+	case vitalsv1.VitalKind_VITAL_KIND_GLUCOSE:
+		return app.VitalKindGlucose
+	default:
+		return app.VitalKind(-1)
+	}
+}
+
+func fromProtoThresholds(t *vitalsv1.Thresholds) app.Thresholds {
+	return app.Thresholds{
+		MaxSystolic:  t.GetMaxSystolic(),
+		MaxDiastolic: t.GetMaxDiastolic(),
+		MinSystolic:  t.GetMinSystolic(),
+	}
+}
+
+// protoAlertStates maps every app.AlertStatus to its AlertState.
+var protoAlertStates = map[app.AlertStatus]vitalsv1.AlertState{
+	app.AlertStatusActive:            vitalsv1.AlertState_ALERT_STATE_ACTIVE,
+	app.AlertStatusAutoResolved:      vitalsv1.AlertState_ALERT_STATE_AUTO_RESOLVED,
+	app.AlertStatusResolvedByRetake:  vitalsv1.AlertState_ALERT_STATE_RESOLVED_BY_RETAKE,
+	app.AlertStatusConfirmedAbnormal: vitalsv1.AlertState_ALERT_STATE_CONFIRMED_ABNORMAL,
+	app.AlertStatusAcknowledged:      vitalsv1.AlertState_ALERT_STATE_ACKNOWLEDGED,
+	app.AlertStatusResolved:          vitalsv1.AlertState_ALERT_STATE_RESOLVED,
+}
+
+// legacyAlertStatuses maps every app.AlertStatus to the coarse AlertStatus
+// that clients predating AlertState understand.
+var legacyAlertStatuses = map[app.AlertStatus]vitalsv1.AlertStatus{
+	app.AlertStatusActive:            vitalsv1.AlertStatus_ALERT_STATUS_ACTIVE,
+	app.AlertStatusAutoResolved:      vitalsv1.AlertStatus_ALERT_STATUS_AUTO_RESOLVED,
+	app.AlertStatusResolvedByRetake:  vitalsv1.AlertStatus_ALERT_STATUS_RESOLVED,
+	app.AlertStatusConfirmedAbnormal: vitalsv1.AlertStatus_ALERT_STATUS_ACTIVE,
+	app.AlertStatusAcknowledged:      vitalsv1.AlertStatus_ALERT_STATUS_ACKNOWLEDGED,
+	app.AlertStatusResolved:          vitalsv1.AlertStatus_ALERT_STATUS_RESOLVED,
+}
+
+func toProtoAlertState(status app.AlertStatus) vitalsv1.AlertState {
+	return protoAlertStates[status]
+}
+
 func toProtoAlertStatus(status app.AlertStatus) vitalsv1.AlertStatus {
-	switch status {
-	case app.AlertStatusAutoResolved:
-		return vitalsv1.AlertStatus_ALERT_STATUS_AUTO_RESOLVED
-	case app.AlertStatusResolvedByRetake:
-		return vitalsv1.AlertStatus_ALERT_STATUS_RESOLVED // Map to RESOLVED for now
-	case app.AlertStatusConfirmedAbnormal:
-		return vitalsv1.AlertStatus_ALERT_STATUS_ACTIVE // Map to ACTIVE for now
+	return legacyAlertStatuses[status]
+}
+
+// fromProtoAlertFilter returns the statuses a ListAlertsRequest keeps: those
+// named in states, plus every status that collapses into one named in the
+// legacy statuses.
+func fromProtoAlertFilter(states []vitalsv1.AlertState, statuses []vitalsv1.AlertStatus) []app.AlertStatus {
+	var out []app.AlertStatus
+	for _, status := range app.AlertStatuses() {
+		if slices.Contains(states, protoAlertStates[status]) || slices.Contains(statuses, legacyAlertStatuses[status]) {
+			out = append(out, status)
+		}
+	}
+	return out
+}
+
+func fromProtoSortOrder(order vitalsv1.SortOrder) app.SortOrder {
+	if order == vitalsv1.SortOrder_SORT_ORDER_DESC {
+		return app.SortDescending
+	}
+	return app.SortAscending
+}
+
+func fromProtoTimeRange(after, before int64) app.TimeRange {
+	var r app.TimeRange
+	if after > 0 {
+		r.After = time.Unix(after, 0).UTC()
+	}
+	if before > 0 {
+		r.Before = time.Unix(before, 0).UTC()
+	}
+	return r
+}
+
+func toProtoSeverity(severity app.Severity) vitalsv1.Severity {
+	switch severity {
+	case app.SeverityLow:
+		return vitalsv1.Severity_SEVERITY_LOW
+	case app.SeverityModerate:
+		return vitalsv1.Severity_SEVERITY_MODERATE
+	case app.SeverityHigh:
+		return vitalsv1.Severity_SEVERITY_HIGH
+	case app.SeverityCritical:
+		return vitalsv1.Severity_SEVERITY_CRITICAL
 	default:
-		return vitalsv1.AlertStatus_ALERT_STATUS_ACTIVE
+		return vitalsv1.Severity_SEVERITY_UNSPECIFIED
 	}
 }
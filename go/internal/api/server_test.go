@@ -0,0 +1,50 @@
+package api
+
+import (
+	"slices"
+	"testing"
+
+	"cadence-vitals-interview/internal/app"
+	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
+)
+
+func TestAlertStatesRoundTrip(t *testing.T) {
+	seen := map[vitalsv1.AlertState]app.AlertStatus{}
+	for _, status := range app.AlertStatuses() {
+		state := toProtoAlertState(status)
+		if state == vitalsv1.AlertState_ALERT_STATE_UNSPECIFIED {
+			t.Fatalf("%v has no AlertState", status)
+		}
+		if other, ok := seen[state]; ok {
+			t.Fatalf("%v and %v both map to %v", other, status, state)
+		}
+		seen[state] = status
+		if got := fromProtoAlertFilter([]vitalsv1.AlertState{state}, nil); !slices.Equal(got, []app.AlertStatus{status}) {
+			t.Fatalf("filtering by %v kept %v, want only %v", state, got, status)
+		}
+	}
+	for value := range vitalsv1.AlertState_name {
+		state := vitalsv1.AlertState(value)
+		if _, ok := seen[state]; !ok && state != vitalsv1.AlertState_ALERT_STATE_UNSPECIFIED {
+			t.Fatalf("%v is not the state of any app.AlertStatus", state)
+		}
+	}
+}
+
+func TestLegacyAlertStatusesStillMatch(t *testing.T) {
+	for _, status := range app.AlertStatuses() {
+		legacy, ok := legacyAlertStatuses[status]
+		if !ok {
+			t.Fatalf("%v has no legacy AlertStatus", status)
+		}
+		// Clients filtering by the coarse status they were sent must still
+		// find the alert.
+		if got := fromProtoAlertFilter(nil, []vitalsv1.AlertStatus{legacy}); !slices.Contains(got, status) {
+			t.Fatalf("filtering by %v kept %v, which misses %v", legacy, got, status)
+		}
+	}
+	got := fromProtoAlertFilter(nil, []vitalsv1.AlertStatus{vitalsv1.AlertStatus_ALERT_STATUS_ACTIVE})
+	if want := []app.AlertStatus{app.AlertStatusActive, app.AlertStatusConfirmedAbnormal}; !slices.Equal(got, want) {
+		t.Fatalf("filtering by ALERT_STATUS_ACTIVE kept %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"strings"
+
+	"cadence-vitals-interview/internal/app"
+	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchBuffer is how far a watcher may fall behind before it is
+// disconnected; it then resumes from its last token.
+const watchBuffer = 256
+
+func (s *Server) WatchAlerts(req *vitalsv1.WatchAlertsRequest, stream grpc.ServerStreamingServer[vitalsv1.WatchEvent]) error {
+	types := []app.EventType{app.EventTypeAlertCreated, app.EventTypeAlertStatusChanged}
+	if req.GetIncludeVitals() {
+		types = append(types, app.EventTypeVitalReceived)
+	}
+	return s.watch(stream, req.GetResumeToken(), app.SubscribeOptions{
+		Types:     types,
+		PatientID: strings.TrimSpace(req.GetPatientId()),
+	})
+}
+
+func (s *Server) WatchVitals(req *vitalsv1.WatchVitalsRequest, stream grpc.ServerStreamingServer[vitalsv1.WatchEvent]) error {
+	return s.watch(stream, req.GetResumeToken(), app.SubscribeOptions{
+		Types:     []app.EventType{app.EventTypeVitalReceived},
+		PatientID: strings.TrimSpace(req.GetPatientId()),
+	})
+}
+
+func (s *Server) watch(stream grpc.ServerStreamingServer[vitalsv1.WatchEvent], token string, opts app.SubscribeOptions) error {
+	opts.Buffer = watchBuffer
+	opts.Policy = app.SlowConsumerDisconnect
+	backlog, events, cancel, err := s.events.SubscribeSince(token, opts)
+	if err != nil {
+		return statusError(err)
+	}
+	defer cancel()
+
+	for _, event := range backlog {
+		if err := stream.Send(s.toProtoWatchEvent(event)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "watch stream closed; reconnect with the last resume_token")
+			}
+			if err := stream.Send(s.toProtoWatchEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) toProtoWatchEvent(event app.Event) *vitalsv1.WatchEvent {
+	out := &vitalsv1.WatchEvent{ResumeToken: s.events.ResumeToken(event)}
+	switch event.Type {
+	case app.EventTypeVitalReceived:
+		out.Type = vitalsv1.WatchEventType_WATCH_EVENT_TYPE_VITAL_RECEIVED
+		out.Vital = toProtoVital(event.Vital)
+	case app.EventTypeAlertCreated:
+		out.Type = vitalsv1.WatchEventType_WATCH_EVENT_TYPE_ALERT_CREATED
+		out.Alert = toProtoAlert(event.Alert)
+	case app.EventTypeAlertStatusChanged:
+		out.Type = vitalsv1.WatchEventType_WATCH_EVENT_TYPE_ALERT_STATUS_CHANGED
+		out.Alert = toProtoAlert(event.Alert)
+		out.PreviousStatus = toProtoAlertStatus(event.PreviousStatus)
+		out.PreviousState = toProtoAlertState(event.PreviousStatus)
+	}
+	return out
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"cadence-vitals-interview/internal/app"
+	vitalsv1 "cadence-vitals-interview/proto/vitals/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// startServer serves the API over an in-memory connection and returns a
+// client for it.
+func startServer(t *testing.T, service *app.Service, events *app.PubSub, opts ...grpc.DialOption) vitalsv1.VitalsServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	vitalsv1.RegisterVitalsServiceServer(srv, NewServer(service, events))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	opts = append(opts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient("passthrough:///bufnet", opts...)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return vitalsv1.NewVitalsServiceClient(conn)
+}
+
+// publishAll publishes events and returns them as stamped, so tests can
+// build resume tokens for them.
+func publishAll(t *testing.T, events *app.PubSub, batch ...app.Event) []app.Event {
+	t.Helper()
+	ch, cancel := events.Subscribe(len(batch))
+	defer cancel()
+	stamped := make([]app.Event, 0, len(batch))
+	for _, event := range batch {
+		if err := events.Publish(context.Background(), event); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		stamped = append(stamped, <-ch)
+	}
+	return stamped
+}
+
+func receiveN(t *testing.T, stream grpc.ServerStreamingClient[vitalsv1.WatchEvent], n int) []*vitalsv1.WatchEvent {
+	t.Helper()
+	var got []*vitalsv1.WatchEvent
+	for range n {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("recv after %d events: %v", len(got), err)
+		}
+		got = append(got, event)
+	}
+	return got
+}
+
+func TestWatchAlertsResumesAndFiltersByPatient(t *testing.T) {
+	events := app.NewPubSub()
+	client := startServer(t, app.NewService(app.NewInMemoryStore()), events)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	published := publishAll(t, events,
+		app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: 1, PatientID: "patient-1"}},
+		app.Event{Type: app.EventTypeAlertCreated, Alert: app.Alert{ID: 1, PatientID: "patient-1", Status: app.AlertStatusActive}},
+		app.Event{Type: app.EventTypeAlertCreated, Alert: app.Alert{ID: 2, PatientID: "patient-2", Status: app.AlertStatusActive}},
+		app.Event{Type: app.EventTypeAlertStatusChanged, Alert: app.Alert{ID: 1, PatientID: "patient-1", Status: app.AlertStatusAutoResolved}, PreviousStatus: app.AlertStatusActive},
+	)
+	start := events.ResumeToken(published[0])
+
+	stream, err := client.WatchAlerts(ctx, &vitalsv1.WatchAlertsRequest{ResumeToken: start})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	got := receiveN(t, stream, 3)
+	for i, event := range got {
+		want := published[i+1]
+		if event.GetAlert().GetId() != want.Alert.ID || event.GetResumeToken() != events.ResumeToken(want) {
+			t.Fatalf("event %d: expected alert %d with token %q, got %v", i, want.Alert.ID, events.ResumeToken(want), event)
+		}
+	}
+	if got[2].GetType() != vitalsv1.WatchEventType_WATCH_EVENT_TYPE_ALERT_STATUS_CHANGED ||
+		got[2].GetPreviousState() != vitalsv1.AlertState_ALERT_STATE_ACTIVE {
+		t.Fatalf("expected a status change from ACTIVE, got %v", got[2])
+	}
+
+	// Resuming from a streamed token picks up right after that event.
+	resumed, err := client.WatchAlerts(ctx, &vitalsv1.WatchAlertsRequest{ResumeToken: got[0].GetResumeToken()})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if next := receiveN(t, resumed, 1)[0]; next.GetAlert().GetId() != 2 {
+		t.Fatalf("expected to resume at alert 2, got %v", next)
+	}
+
+	filtered, err := client.WatchAlerts(ctx, &vitalsv1.WatchAlertsRequest{ResumeToken: start, PatientId: "patient-2", IncludeVitals: true})
+	if err != nil {
+		t.Fatalf("watch patient-2: %v", err)
+	}
+	if only := receiveN(t, filtered, 1)[0]; only.GetAlert().GetVital().GetPatientId() != "patient-2" {
+		t.Fatalf("expected only patient-2's alert, got %v", only)
+	}
+	// Live events are filtered the same way.
+	publishAll(t, events,
+		app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: 2, PatientID: "patient-1"}},
+		app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: 3, PatientID: "patient-2"}},
+	)
+	if live := receiveN(t, filtered, 1)[0]; live.GetVital().GetId() != 3 {
+		t.Fatalf("expected patient-2's vital, got %v", live)
+	}
+}
+
+func TestWatchVitalsFiltersByPatient(t *testing.T) {
+	events := app.NewPubSub()
+	client := startServer(t, app.NewService(app.NewInMemoryStore()), events)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	published := publishAll(t, events,
+		app.Event{Type: app.EventTypeAlertCreated, Alert: app.Alert{ID: 1, PatientID: "patient-1"}},
+		app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: 1, PatientID: "patient-2"}},
+		app.Event{Type: app.EventTypeAlertCreated, Alert: app.Alert{ID: 2, PatientID: "patient-1"}},
+		app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: 2, PatientID: "patient-1"}},
+	)
+	stream, err := client.WatchVitals(ctx, &vitalsv1.WatchVitalsRequest{ResumeToken: events.ResumeToken(published[0]), PatientId: "patient-1"})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	got := receiveN(t, stream, 1)[0]
+	if got.GetType() != vitalsv1.WatchEventType_WATCH_EVENT_TYPE_VITAL_RECEIVED || got.GetVital().GetId() != 2 {
+		t.Fatalf("expected only patient-1's vital, got %v", got)
+	}
+}
+
+func TestWatchRejectsExpiredAndMalformedTokens(t *testing.T) {
+	events := app.NewPubSub(app.WithHistory(2))
+	client := startServer(t, app.NewService(app.NewInMemoryStore()), events)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var batch []app.Event
+	for i := range 5 {
+		batch = append(batch, app.Event{Type: app.EventTypeAlertCreated, Alert: app.Alert{ID: int64(i + 1), PatientID: "patient-1"}})
+	}
+	published := publishAll(t, events, batch...)
+
+	for token, want := range map[string]codes.Code{
+		events.ResumeToken(published[0]): codes.OutOfRange,
+		"another-run.3":                  codes.OutOfRange,
+		"not-a-token":                    codes.InvalidArgument,
+	} {
+		stream, err := client.WatchAlerts(ctx, &vitalsv1.WatchAlertsRequest{ResumeToken: token})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		if status.Code(err) != want {
+			t.Fatalf("token %q: expected %s, got %v", token, want, err)
+		}
+	}
+}
+
+func TestWatchDisconnectsSlowConsumerWithUnavailable(t *testing.T) {
+	events := app.NewPubSub()
+	// A fixed, small flow-control window makes the server's sends stall
+	// while the client isn't reading, so the watcher falls behind.
+	client := startServer(t, app.NewService(app.NewInMemoryStore()), events,
+		grpc.WithInitialWindowSize(64<<10), grpc.WithInitialConnWindowSize(64<<10))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	first := publishAll(t, events, app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: 1, PatientID: "patient-1"}})
+	marker := publishAll(t, events, app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: 2, PatientID: "patient-1"}})
+	stream, err := client.WatchVitals(ctx, &vitalsv1.WatchVitalsRequest{ResumeToken: events.ResumeToken(first[0])})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	// Receiving the backlog proves the watcher is subscribed.
+	if got := receiveN(t, stream, 1)[0]; got.GetResumeToken() != events.ResumeToken(marker[0]) {
+		t.Fatalf("expected the backlogged vital, got %v", got)
+	}
+
+	bulky := strings.Repeat("x", 8<<10)
+	const flood = 2 * watchBuffer
+	for i := range flood {
+		event := app.Event{Type: app.EventTypeVitalReceived, Vital: app.Vital{ID: int64(i + 3), PatientID: bulky}}
+		if err := events.Publish(ctx, event); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	received := 0
+	for {
+		_, err := stream.Recv()
+		if err != nil {
+			if status.Code(err) != codes.Unavailable {
+				t.Fatalf("expected UNAVAILABLE after falling behind, got %v", err)
+			}
+			break
+		}
+		received++
+	}
+	if received >= flood {
+		t.Fatalf("expected the watcher cut off before the end, got all %d events", received)
+	}
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidAlertAction = errors.New("invalid alert action")
+	// ErrAlertClosed means a message can't be requeued because its alert no
+	// longer needs it.
+	ErrAlertClosed = errors.New("alert is closed")
+)
+
+// ResolutionReason is why a clinician closed an alert. The values match
+// the proto enum.
+type ResolutionReason int32
+
+const (
+	ResolutionUnspecified      ResolutionReason = 0
+	ResolutionTreated          ResolutionReason = 1
+	ResolutionFalseAlarm       ResolutionReason = 2
+	ResolutionMeasurementError ResolutionReason = 3
+	ResolutionDuplicate        ResolutionReason = 4
+	ResolutionOther            ResolutionReason = 5
+)
+
+var resolutionReasons = []ResolutionReason{
+	ResolutionTreated,
+	ResolutionFalseAlarm,
+	ResolutionMeasurementError,
+	ResolutionDuplicate,
+	ResolutionOther,
+}
+
+func (r ResolutionReason) String() string {
+	switch r {
+	case ResolutionTreated:
+		return "TREATED"
+	case ResolutionFalseAlarm:
+		return "FALSE_ALARM"
+	case ResolutionMeasurementError:
+		return "MEASUREMENT_ERROR"
+	case ResolutionDuplicate:
+		return "DUPLICATE"
+	case ResolutionOther:
+		return "OTHER"
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+// ParseResolutionReason accepts the names String returns, case-insensitively.
+func ParseResolutionReason(name string) (ResolutionReason, bool) {
+	for _, reason := range resolutionReasons {
+		if strings.EqualFold(name, reason.String()) {
+			return reason, true
+		}
+	}
+	return ResolutionUnspecified, false
+}
+
+type AcknowledgeAlertRequest struct {
+	AlertID   int64
+	Clinician string
+	Note      string
+}
+
+type ResolveAlertRequest struct {
+	AlertID   int64
+	Clinician string
+	Reason    ResolutionReason
+	Note      string
+}
+
+// AcknowledgeAlert records that a clinician has seen an open or confirmed
+// alert. Acknowledged alerts stay open to retakes.
+func (s *Service) AcknowledgeAlert(ctx context.Context, req AcknowledgeAlertRequest) (Alert, error) {
+	clinician := strings.TrimSpace(req.Clinician)
+	if clinician == "" {
+		return Alert{}, fmt.Errorf("%w: clinician is required", ErrInvalidAlertAction)
+	}
+	return s.actOnAlert(ctx, req.AlertID, func(alert *Alert) (AlertHistoryEntry, error) {
+		now := s.clock.Now().UTC()
+		from := alert.Status
+		if err := alert.Transition(AlertStatusAcknowledged, 0, now); err != nil {
+			return AlertHistoryEntry{}, err
+		}
+		alert.Acknowledgement = AlertAction{By: clinician, At: now, Note: strings.TrimSpace(req.Note)}
+		entry := statusChange(AlertHistoryAcknowledged, *alert, from)
+		entry.Actor, entry.Note = clinician, alert.Acknowledgement.Note
+		return entry, nil
+	})
+}
+
+// ResolveAlert closes an alert by hand.
+func (s *Service) ResolveAlert(ctx context.Context, req ResolveAlertRequest) (Alert, error) {
+	clinician := strings.TrimSpace(req.Clinician)
+	if clinician == "" {
+		return Alert{}, fmt.Errorf("%w: clinician is required", ErrInvalidAlertAction)
+	}
+	if !slices.Contains(resolutionReasons, req.Reason) {
+		return Alert{}, fmt.Errorf("%w: a resolution reason is required", ErrInvalidAlertAction)
+	}
+	alert, err := s.actOnAlert(ctx, req.AlertID, func(alert *Alert) (AlertHistoryEntry, error) {
+		now := s.clock.Now().UTC()
+		from := alert.Status
+		if err := alert.Transition(AlertStatusResolved, 0, now); err != nil {
+			return AlertHistoryEntry{}, err
+		}
+		alert.Resolution = AlertAction{By: clinician, At: now, Note: strings.TrimSpace(req.Note)}
+		alert.ResolutionReason = req.Reason
+		entry := statusChange(AlertHistoryResolved, *alert, from)
+		entry.Actor, entry.Note, entry.ResolutionReason = clinician, alert.Resolution.Note, req.Reason
+		return entry, nil
+	})
+	if err == nil && s.messages != nil {
+		s.messages.CancelByAlert(alert.ID)
+	}
+	return alert, err
+}
+
+// actOnAlert applies change to the stored alert and publishes the status
+// change.
+func (s *Service) actOnAlert(ctx context.Context, id int64, change func(*Alert) (AlertHistoryEntry, error)) (Alert, error) {
+	stored, prev, err := modifyAlert(ctx, s.store, id, change)
+	if err != nil {
+		return Alert{}, err
+	}
+	if s.events != nil {
+		event := Event{Type: EventTypeAlertStatusChanged, Alert: stored, PreviousStatus: prev}
+		if err := s.events.Publish(ctx, event); err != nil {
+			log.Printf("failed to publish %s for alert %d: %v", event.Type, stored.ID, err)
+		}
+	}
+	return stored, nil
+}
+
+// RequeueMessage requeues a dead-lettered message, as MessageQueue.Requeue
+// does, unless its alert no longer needs it: a retake request once the alert
+// stops waiting on a retake, or a care team page once the alert stops
+// escalating.
+func (s *Service) RequeueMessage(ctx context.Context, id int64) (Message, error) {
+	if s.messages == nil {
+		return Message{}, fmt.Errorf("%w: %d", ErrMessageNotFound, id)
+	}
+	msg, ok := s.messages.GetMessage(id)
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %d", ErrMessageNotFound, id)
+	}
+	if msg.AlertID != 0 {
+		alert, err := s.store.GetAlert(ctx, msg.AlertID)
+		if err != nil {
+			return Message{}, err
+		}
+		needed := escalates(alert)
+		if msg.Recipient == RecipientPatient {
+			needed = alert.Status.IsOpen()
+		}
+		if !needed {
+			return Message{}, fmt.Errorf("%w: alert %d is %s", ErrAlertClosed, alert.ID, alert.Status)
+		}
+	}
+	return s.messages.Requeue(id)
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServiceAcknowledgeThenResolveAlert(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	defer pubsub.Close()
+	clock := newFakeClock(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	queue := NewMessageQueue(time.Hour, time.Hour)
+	service := NewService(store, WithAlertEvents(pubsub), WithMessageQueue(queue), WithServiceClock(clock))
+	events, cancel := pubsub.Subscribe(4)
+	defer cancel()
+
+	ctx := context.Background()
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	msg, _ := queue.Enqueue(Message{PatientID: "patient-1", AlertID: alert.ID, Content: "Please retake"})
+
+	acked, err := service.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{AlertID: alert.ID, Clinician: " dr-lee ", Note: "calling patient"})
+	if err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+	if acked.Status != AlertStatusAcknowledged || acked.Acknowledgement.By != "dr-lee" || !acked.Acknowledgement.At.Equal(clock.Now()) {
+		t.Fatalf("unexpected acknowledged alert: %+v", acked)
+	}
+	if got := receive(t, events); got.Type != EventTypeAlertStatusChanged || got.PreviousStatus != AlertStatusActive || got.Alert.Status != AlertStatusAcknowledged {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+
+	clock.Advance(10 * time.Minute)
+	resolved, err := service.ResolveAlert(ctx, ResolveAlertRequest{AlertID: alert.ID, Clinician: "dr-lee", Reason: ResolutionTreated})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if resolved.Status != AlertStatusResolved || resolved.ResolutionReason != ResolutionTreated || resolved.Acknowledgement.By != "dr-lee" {
+		t.Fatalf("unexpected resolved alert: %+v", resolved)
+	}
+	if got := receive(t, events); got.PreviousStatus != AlertStatusAcknowledged || got.Alert.Status != AlertStatusResolved {
+		t.Fatalf("unexpected event: %+v", got)
+	}
+	if m, _ := queue.GetMessage(msg.ID); m.Status != MessageStatusCancelled {
+		t.Fatalf("expected the retake request to be cancelled, got %s", m.Status)
+	}
+
+	if _, err := service.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{AlertID: alert.ID, Clinician: "dr-lee"}); !errors.Is(err, ErrInvalidAlertTransition) {
+		t.Fatalf("expected invalid transition on a resolved alert, got %v", err)
+	}
+}
+
+func TestServiceAlertActionsValidateInput(t *testing.T) {
+	store := NewInMemoryStore()
+	service := NewService(store)
+	ctx := context.Background()
+
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusAutoResolved})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+
+	if _, err := service.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{AlertID: alert.ID}); !errors.Is(err, ErrInvalidAlertAction) {
+		t.Fatalf("expected missing clinician error, got %v", err)
+	}
+	if _, err := service.ResolveAlert(ctx, ResolveAlertRequest{AlertID: alert.ID, Clinician: "dr-lee"}); !errors.Is(err, ErrInvalidAlertAction) {
+		t.Fatalf("expected missing reason error, got %v", err)
+	}
+	if _, err := service.ResolveAlert(ctx, ResolveAlertRequest{AlertID: 99, Clinician: "dr-lee", Reason: ResolutionOther}); !errors.Is(err, ErrAlertNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if _, err := service.ResolveAlert(ctx, ResolveAlertRequest{AlertID: alert.ID, Clinician: "dr-lee", Reason: ResolutionFalseAlarm}); !errors.Is(err, ErrInvalidAlertTransition) {
+		t.Fatalf("expected invalid transition from auto-resolved, got %v", err)
+	}
+}
+
+// conflictingStore lets another writer update the alert between the
+// service's read and write, once.
+type conflictingStore struct {
+	Store
+	raced bool
+}
+
+func (s *conflictingStore) UpdateAlert(ctx context.Context, alert Alert, history ...AlertHistoryEntry) (Alert, error) {
+	if !s.raced {
+		s.raced = true
+		current, err := s.Store.GetAlert(ctx, alert.ID)
+		if err != nil {
+			return Alert{}, err
+		}
+		current.ReadingCount++
+		if _, err := s.Store.UpdateAlert(ctx, current); err != nil {
+			return Alert{}, err
+		}
+	}
+	return s.Store.UpdateAlert(ctx, alert, history...)
+}
+
+func TestServiceAlertActionsRetryOnConflict(t *testing.T) {
+	store := &conflictingStore{Store: NewInMemoryStore()}
+	service := NewService(store)
+	ctx := context.Background()
+
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive, ReadingCount: 1})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	acked, err := service.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{AlertID: alert.ID, Clinician: "dr-lee"})
+	if err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+	if acked.Status != AlertStatusAcknowledged || acked.ReadingCount != 2 || acked.Revision != 3 {
+		t.Fatalf("expected the acknowledgement on top of the concurrent update, got %+v", acked)
+	}
+}
+
+func TestServiceRequeueMessageRefusesClosedAlert(t *testing.T) {
+	store := NewInMemoryStore()
+	sender := MessageSenderFunc(func(ctx context.Context, msg Message) (Delivery, error) {
+		return Delivery{}, errors.New("gateway unavailable")
+	})
+	queue := NewMessageQueue(0, 0, WithMessageSender(sender), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	service := NewService(store, WithMessageQueue(queue))
+	ctx := context.Background()
+
+	deadLetter := func(status AlertStatus, recipient Recipient) Message {
+		alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-" + status.String(), Status: status})
+		if err != nil {
+			t.Fatalf("add alert: %v", err)
+		}
+		queue.Enqueue(Message{PatientID: alert.PatientID, Recipient: recipient, AlertID: alert.ID, Content: "retake"})
+		msg := queue.ProcessNext(ctx)
+		if msg == nil || msg.Status != MessageStatusFailed {
+			t.Fatalf("expected a dead letter, got %+v", msg)
+		}
+		return *msg
+	}
+	open := deadLetter(AlertStatusActive, RecipientPatient)
+	closed := deadLetter(AlertStatusAutoResolved, RecipientPatient)
+	// A confirmed alert has had its retake but still escalates.
+	page := deadLetter(AlertStatusConfirmedAbnormal, RecipientOnCallNurse)
+	retake := deadLetter(AlertStatusConfirmedAbnormal, RecipientPatient)
+
+	for _, msg := range []Message{open, page} {
+		if got, err := service.RequeueMessage(ctx, msg.ID); err != nil || got.Status != MessageStatusQueued {
+			t.Fatalf("expected message %d to %s to be requeued, got %+v (%v)", msg.ID, msg.Recipient, got, err)
+		}
+	}
+	for _, msg := range []Message{closed, retake} {
+		if _, err := service.RequeueMessage(ctx, msg.ID); !errors.Is(err, ErrAlertClosed) {
+			t.Fatalf("expected message %d to %s to be refused, got %v", msg.ID, msg.Recipient, err)
+		}
+	}
+	if _, err := service.RequeueMessage(ctx, 99); !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("expected unknown message error, got %v", err)
+	}
+}
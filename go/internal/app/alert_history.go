@@ -0,0 +1,133 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// AlertHistoryType is what happened to an alert in one history entry.
+type AlertHistoryType string
+
+const (
+	AlertHistoryCreated       AlertHistoryType = "CREATED"
+	AlertHistoryVitalAttached AlertHistoryType = "VITAL_ATTACHED"
+	// AlertHistoryStatusChanged is a status change caused by a reading; the
+	// care team's changes are ACKNOWLEDGED and RESOLVED.
+	AlertHistoryStatusChanged         AlertHistoryType = "STATUS_CHANGED"
+	AlertHistoryAcknowledged          AlertHistoryType = "ACKNOWLEDGED"
+	AlertHistoryResolved              AlertHistoryType = "RESOLVED"
+	AlertHistoryEscalated             AlertHistoryType = "ESCALATED"
+	AlertHistoryNotificationQueued    AlertHistoryType = "NOTIFICATION_QUEUED"
+	AlertHistoryNotificationSent      AlertHistoryType = "NOTIFICATION_SENT"
+	AlertHistoryNotificationCancelled AlertHistoryType = "NOTIFICATION_CANCELLED"
+	// AlertHistoryNotificationFailed is a notification dead-lettered after
+	// its last attempt failed.
+	AlertHistoryNotificationFailed AlertHistoryType = "NOTIFICATION_FAILED"
+	// AlertHistoryNotificationThrottled is a retake request held back by
+	// the patient's notification cooldown and scheduled for its end.
+	AlertHistoryNotificationThrottled AlertHistoryType = "NOTIFICATION_THROTTLED"
+)
+
+// AlertHistoryEntry is one event in an alert's append-only timeline. Fields
+// that don't apply to Type are left zero.
+type AlertHistoryEntry struct {
+	ID      int64
+	AlertID int64
+	Type    AlertHistoryType
+	At      time.Time
+	// FromStatus and Status are the alert's status before and after the
+	// entry.
+	FromStatus AlertStatus
+	Status     AlertStatus
+	// VitalID is the reading that created, joined or settled the alert.
+	VitalID   int64
+	MessageID int64
+	Recipient Recipient
+	// Actor and Note are the clinician and their note for ACKNOWLEDGED and
+	// RESOLVED.
+	Actor string
+	// Note is the clinician's note, or why a notification failed.
+	Note             string
+	ResolutionReason ResolutionReason
+}
+
+// RecordsStatus reports whether the entry's FromStatus and Status are set.
+// Notification entries don't record the alert's status.
+func (e AlertHistoryEntry) RecordsStatus() bool {
+	_, notification := notificationHistoryTypes[e.Type]
+	return !notification
+}
+
+var notificationHistoryTypes = map[AlertHistoryType]struct{}{
+	AlertHistoryNotificationQueued:    {},
+	AlertHistoryNotificationSent:      {},
+	AlertHistoryNotificationCancelled: {},
+	AlertHistoryNotificationFailed:    {},
+	AlertHistoryNotificationThrottled: {},
+}
+
+// statusChange is the history entry for moving alert from its status
+// before the change, from, to its current one.
+func statusChange(typ AlertHistoryType, alert Alert, from AlertStatus) AlertHistoryEntry {
+	return AlertHistoryEntry{
+		Type:       typ,
+		At:         alert.StatusChangedAt,
+		FromStatus: from,
+		Status:     alert.Status,
+		VitalID:    alert.StatusVitalID,
+	}
+}
+
+var messageHistoryTypes = map[MessageStatus]AlertHistoryType{
+	MessageStatusQueued:    AlertHistoryNotificationQueued,
+	MessageStatusSent:      AlertHistoryNotificationSent,
+	MessageStatusCancelled: AlertHistoryNotificationCancelled,
+	MessageStatusFailed:    AlertHistoryNotificationFailed,
+}
+
+// RecordMessageHistory returns a MessageListener that adds alert messages
+// being queued, sent, cancelled and dead-lettered to their alert's history.
+// Register it with MessageQueue.AddListener.
+func RecordMessageHistory(store Store) MessageListener {
+	return func(msg Message) {
+		typ, ok := messageHistoryTypes[msg.Status]
+		if !ok || msg.AlertID == 0 {
+			return
+		}
+		entry := AlertHistoryEntry{AlertID: msg.AlertID, Type: typ, At: msg.QueuedAt, MessageID: msg.ID, Recipient: msg.Recipient}
+		switch msg.Status {
+		case MessageStatusQueued:
+			if msg.Attempts > 0 {
+				// Waiting to retry, not newly queued.
+				return
+			}
+			if !msg.ScheduledFor.IsZero() {
+				entry.Note = "scheduled for " + msg.ScheduledFor.UTC().Format(time.RFC3339)
+			}
+		case MessageStatusSent:
+			entry.At = msg.SentAt
+		case MessageStatusCancelled:
+			entry.At = msg.CancelledAt
+		case MessageStatusFailed:
+			entry.At = msg.FailedAt
+			entry.Note = msg.LastError
+		}
+		if err := store.AppendAlertHistory(context.Background(), entry); err != nil {
+			log.Printf("failed to record %s for alert %d: %v", typ, msg.AlertID, err)
+		}
+	}
+}
+
+// GetAlert returns an alert with its timeline, oldest entry first.
+func (s *Service) GetAlert(ctx context.Context, id int64) (Alert, []AlertHistoryEntry, error) {
+	alert, err := s.store.GetAlert(ctx, id)
+	if err != nil {
+		return Alert{}, nil, err
+	}
+	history, err := s.store.AlertHistory(ctx, id)
+	if err != nil {
+		return Alert{}, nil, err
+	}
+	return alert, history, nil
+}
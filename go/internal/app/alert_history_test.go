@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAlertTimelineRecordsLifecycle(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	queue := NewMessageQueue(0, 0)
+	queue.AddListener(RecordMessageHistory(store))
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+	service := NewService(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	alert := waitForAlertStatus(t, store, AlertStatusActive)
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.Status != MessageStatusSent {
+		t.Fatalf("expected notification to be sent, got %+v", msg)
+	}
+	publishVital(t, ctx, pubsub, Vital{ID: 2, PatientID: "patient-1", Systolic: 118, Diastolic: 76, TakenAt: time.Now().UTC()})
+	waitForAlertStatus(t, store, AlertStatusResolvedByRetake)
+
+	_, history, err := service.GetAlert(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("get alert: %v", err)
+	}
+	var types []AlertHistoryType
+	for _, entry := range history {
+		types = append(types, entry.Type)
+	}
+	want := []AlertHistoryType{AlertHistoryCreated, AlertHistoryNotificationQueued, AlertHistoryNotificationSent, AlertHistoryStatusChanged}
+	if !slices.Equal(types, want) {
+		t.Fatalf("expected timeline %v, got %v", want, types)
+	}
+	if history[0].VitalID != 1 || history[1].MessageID != alert.MessageID || history[1].Recipient != RecipientPatient {
+		t.Fatalf("unexpected creation and notification entries: %+v", history[:2])
+	}
+	if settled := history[3]; settled.FromStatus != AlertStatusActive || settled.Status != AlertStatusResolvedByRetake || settled.VitalID != 2 {
+		t.Fatalf("expected the retake to be recorded as settling the alert, got %+v", settled)
+	}
+}
+
+func TestAlertTimelineRecordsDeadLetteredNotification(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx := context.Background()
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1"})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	sender := MessageSenderFunc(func(context.Context, Message) (Delivery, error) {
+		return Delivery{}, errors.New("no route to patient")
+	})
+	queue := NewMessageQueue(0, 0, WithMessageSender(sender), WithRetryPolicy(RetryPolicy{MaxAttempts: 2}))
+	queue.AddListener(RecordMessageHistory(store))
+
+	msg, _ := queue.Enqueue(Message{PatientID: "patient-1", AlertID: alert.ID})
+	queue.ProcessNext(ctx)
+	queue.ProcessNext(ctx)
+	queue.Requeue(msg.ID)
+
+	history, err := store.AlertHistory(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("alert history: %v", err)
+	}
+	var types []AlertHistoryType
+	for _, entry := range history {
+		types = append(types, entry.Type)
+	}
+	// The retry in between isn't a new notification.
+	want := []AlertHistoryType{AlertHistoryNotificationQueued, AlertHistoryNotificationFailed, AlertHistoryNotificationQueued}
+	if !slices.Equal(types, want) {
+		t.Fatalf("expected timeline %v, got %v", want, types)
+	}
+	if history[1].Note != "no route to patient" || history[1].MessageID != msg.ID {
+		t.Fatalf("expected the failure to record its error, got %+v", history[1])
+	}
+}
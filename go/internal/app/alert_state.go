@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidAlertTransition = errors.New("invalid alert status transition")
+
+var alertTransitions = map[AlertStatus][]AlertStatus{
+	AlertStatusActive: {
+		AlertStatusAutoResolved,
+		AlertStatusResolvedByRetake,
+		AlertStatusConfirmedAbnormal,
+		AlertStatusAcknowledged,
+		AlertStatusResolved,
+	},
+	AlertStatusAcknowledged: {
+		AlertStatusAutoResolved,
+		AlertStatusResolvedByRetake,
+		AlertStatusConfirmedAbnormal,
+		AlertStatusResolved,
+	},
+	// A confirmed abnormal reading is what the care team follows up on.
+	AlertStatusConfirmedAbnormal: {
+		AlertStatusAcknowledged,
+		AlertStatusResolved,
+	},
+}
+
+func (s AlertStatus) String() string {
+	switch s {
+	case AlertStatusActive:
+		return "ACTIVE"
+	case AlertStatusAutoResolved:
+		return "AUTO_RESOLVED"
+	case AlertStatusResolvedByRetake:
+		return "RESOLVED_BY_RETAKE"
+	case AlertStatusConfirmedAbnormal:
+		return "CONFIRMED_ABNORMAL"
+	case AlertStatusAcknowledged:
+		return "ACKNOWLEDGED"
+	case AlertStatusResolved:
+		return "RESOLVED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+var alertStatuses = []AlertStatus{
+	AlertStatusActive,
+	AlertStatusAutoResolved,
+	AlertStatusResolvedByRetake,
+	AlertStatusConfirmedAbnormal,
+	AlertStatusAcknowledged,
+	AlertStatusResolved,
+}
+
+// AlertStatuses returns every AlertStatus, in numeric order.
+func AlertStatuses() []AlertStatus {
+	return slices.Clone(alertStatuses)
+}
+
+// ParseAlertStatus accepts the names String returns, case-insensitively.
+func ParseAlertStatus(name string) (AlertStatus, bool) {
+	for _, status := range alertStatuses {
+		if strings.EqualFold(name, status.String()) {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+func (s AlertStatus) CanTransitionTo(next AlertStatus) bool {
+	for _, allowed := range alertTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether the alert is still waiting on a retake reading.
+func (s AlertStatus) IsOpen() bool {
+	return s == AlertStatusActive || s == AlertStatusAcknowledged
+}
+
+// Transition moves the alert to next, recording when it happened and which
+// vital triggered it. Clinician actions pass a zero vitalID and keep the
+// last reading's. Illegal transitions leave the alert untouched.
+func (a *Alert) Transition(next AlertStatus, vitalID int64, at time.Time) error {
+	if !a.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidAlertTransition, a.Status, next)
+	}
+	a.Status = next
+	a.StatusChangedAt = at.UTC()
+	if vitalID != 0 {
+		a.StatusVitalID = vitalID
+	}
+	return nil
+}
+
+// maxAlertUpdateRetries bounds how often modifyAlert reapplies a change when
+// another writer keeps updating the same alert.
+const maxAlertUpdateRetries = 3
+
+// modifyAlert applies change to the stored alert and records the history
+// entry it returns. If another writer, such as a clinician or the
+// escalator, updates the alert in between, change is reapplied to the fresh
+// copy so neither update is lost. A change that returns a zero entry records
+// no history. It returns the stored alert and its status before the change.
+func modifyAlert(ctx context.Context, store Store, id int64, change func(*Alert) (AlertHistoryEntry, error)) (Alert, AlertStatus, error) {
+	for attempt := 1; ; attempt++ {
+		alert, err := store.GetAlert(ctx, id)
+		if err != nil {
+			return Alert{}, 0, err
+		}
+		prev := alert.Status
+		entry, err := change(&alert)
+		if err != nil {
+			return Alert{}, 0, err
+		}
+		var history []AlertHistoryEntry
+		if entry.Type != "" {
+			history = append(history, entry)
+		}
+		stored, err := store.UpdateAlert(ctx, alert, history...)
+		if errors.Is(err, ErrAlertConflict) && attempt < maxAlertUpdateRetries {
+			continue
+		}
+		if err != nil {
+			return Alert{}, 0, err
+		}
+		return stored, prev, nil
+	}
+}
+
+// linkMessage makes msgID the alert's latest notification. The message's own
+// history is recorded by RecordMessageHistory.
+func linkMessage(ctx context.Context, store Store, alertID, msgID int64) (Alert, error) {
+	alert, _, err := modifyAlert(ctx, store, alertID, func(a *Alert) (AlertHistoryEntry, error) {
+		a.MessageID = msgID
+		return AlertHistoryEntry{}, nil
+	})
+	return alert, err
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlertTransitionRecordsTrigger(t *testing.T) {
+	alert := Alert{ID: 1, Status: AlertStatusActive}
+	at := time.Now()
+
+	if err := alert.Transition(AlertStatusAutoResolved, 42, at); err != nil {
+		t.Fatalf("transition failed: %v", err)
+	}
+	if alert.Status != AlertStatusAutoResolved {
+		t.Fatalf("unexpected status: %v", alert.Status)
+	}
+	if alert.StatusVitalID != 42 {
+		t.Fatalf("unexpected status vital id: %d", alert.StatusVitalID)
+	}
+	if !alert.StatusChangedAt.Equal(at) {
+		t.Fatalf("unexpected status changed at: %v", alert.StatusChangedAt)
+	}
+}
+
+func TestAlertTransitionRejectsIllegalMoves(t *testing.T) {
+	cases := []struct {
+		from AlertStatus
+		to   AlertStatus
+	}{
+		{AlertStatusActive, AlertStatusActive},
+		{AlertStatusAutoResolved, AlertStatusActive},
+		{AlertStatusAutoResolved, AlertStatusResolvedByRetake},
+		{AlertStatusResolvedByRetake, AlertStatusConfirmedAbnormal},
+		{AlertStatusConfirmedAbnormal, AlertStatusResolvedByRetake},
+		{AlertStatusAcknowledged, AlertStatusAcknowledged},
+		{AlertStatusAutoResolved, AlertStatusAcknowledged},
+		{AlertStatusResolved, AlertStatusAcknowledged},
+		{AlertStatusResolved, AlertStatusResolved},
+	}
+	for _, tc := range cases {
+		alert := Alert{Status: tc.from}
+		err := alert.Transition(tc.to, 1, time.Now())
+		if !errors.Is(err, ErrInvalidAlertTransition) {
+			t.Fatalf("%s -> %s: expected invalid transition, got %v", tc.from, tc.to, err)
+		}
+		if alert.Status != tc.from || alert.StatusVitalID != 0 {
+			t.Fatalf("%s -> %s: alert modified on rejected transition", tc.from, tc.to)
+		}
+	}
+}
+
+func TestAlertStatusesListsEveryStatus(t *testing.T) {
+	statuses := AlertStatuses()
+	for i, status := range statuses {
+		if status != AlertStatus(i) {
+			t.Fatalf("AlertStatuses()[%d] = %v, want statuses in numeric order", i, status)
+		}
+		if parsed, ok := ParseAlertStatus(status.String()); !ok || parsed != status {
+			t.Fatalf("%v does not parse back from its name", status)
+		}
+	}
+	// A status added after the last one must be added to AlertStatuses too.
+	if next := AlertStatus(len(statuses)); next.String() != "UNKNOWN" {
+		t.Fatalf("AlertStatuses() is missing %v", next)
+	}
+}
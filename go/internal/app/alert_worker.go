@@ -2,26 +2,73 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 )
 
+// alertWorkerActor is who the timeline credits with alerts the worker
+// closes on its own.
+const alertWorkerActor = "alert-worker"
+
 type AlertWorker struct {
 	sub          <-chan Event
 	cancel       func()
 	store        Store
+	events       Publisher
 	messageQueue *MessageQueue
+	rules        RuleSet
+	dedup        DedupPolicy
+	quality      QualityPolicy
+	composer     *MessageComposer
+	clock        Clock
+}
+
+type AlertWorkerOption func(*AlertWorker)
+
+func WithRuleSet(rules RuleSet) AlertWorkerOption {
+	return func(w *AlertWorker) { w.rules = rules }
+}
+
+func WithDedupPolicy(policy DedupPolicy) AlertWorkerOption {
+	return func(w *AlertWorker) { w.dedup = policy }
+}
+
+// WithMessageComposer sets how retake requests are worded.
+func WithMessageComposer(composer *MessageComposer) AlertWorkerOption {
+	return func(w *AlertWorker) { w.composer = composer }
+}
+
+// WithQualityPolicy lets readings with the policy's flags alert.
+func WithQualityPolicy(policy QualityPolicy) AlertWorkerOption {
+	return func(w *AlertWorker) { w.quality = policy }
 }
 
-func NewAlertWorker(pubsub *PubSub, store Store, buffer int, messageQueue *MessageQueue) *AlertWorker {
-	sub, cancel := pubsub.Subscribe(buffer)
-	return &AlertWorker{
+func WithWorkerClock(clock Clock) AlertWorkerOption {
+	return func(w *AlertWorker) { w.clock = clock }
+}
+
+func NewAlertWorker(pubsub *PubSub, store Store, buffer int, messageQueue *MessageQueue, opts ...AlertWorkerOption) *AlertWorker {
+	sub, cancel := pubsub.SubscribeWith(SubscribeOptions{
+		Buffer: buffer,
+		Types:  []EventType{EventTypeVitalReceived},
+	})
+	w := &AlertWorker{
 		sub:          sub,
 		cancel:       cancel,
 		store:        store,
+		events:       pubsub,
 		messageQueue: messageQueue,
+		rules:        DefaultRuleSet(),
+		dedup:        DefaultDedupPolicy(),
+		composer:     defaultMessageComposer(),
+		clock:        SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(w)
 	}
+	return w
 }
 
 func (w *AlertWorker) Run(ctx context.Context) {
@@ -43,32 +90,381 @@ func (w *AlertWorker) handleEvent(ctx context.Context, event Event) {
 	if event.Type != EventTypeVitalReceived {
 		return
 	}
-	if !IsAbnormal(event.Vital) {
+	if !w.quality.Evaluates(event.Vital) {
+		log.Printf("[Alert] %s: vital %d not evaluated, flagged %v", event.Vital.PatientID, event.Vital.ID, event.Vital.Flags)
 		return
 	}
 
-	reason := AlertReason(event.Vital)
+	// Only the patient's alerts raised at or before the reading can have
+	// handled it or be settled by it.
+	alerts, err := w.store.QueryAlerts(ctx, AlertQuery{
+		PatientID: event.Vital.PatientID,
+		TakenAt:   TimeRange{Before: event.Vital.TakenAt.Add(time.Nanosecond)},
+	})
+	if err != nil {
+		// Skip the reading rather than risk opening a duplicate alert
+		// and asking the patient to retake again.
+		log.Printf("alert worker failed to list alerts for %s: %v", event.Vital.PatientID, err)
+		return
+	}
+	// Events come from the outbox at least once; a vital that already
+	// opened, joined or settled an alert has been handled.
+	if alreadyApplied(alerts, event.Vital) {
+		return
+	}
+
+	history, err := w.history(ctx, event.Vital)
+	if err != nil {
+		log.Printf("alert worker failed to load history for %s: %v", event.Vital.PatientID, err)
+	}
+	rules, thresholdVersion := w.rulesFor(ctx, event.Vital.PatientID)
+	findings := rules.Alerting(rules.Evaluate(event.Vital, history))
+	abnormal := len(findings) > 0
+	absorbed, stale := w.applyToOpenAlerts(ctx, alerts, event.Vital, abnormal)
+	if !abnormal || absorbed {
+		return
+	}
+
+	now := w.clock.Now()
+	reason := FindingsReason(findings)
 	alert := Alert{
-		VitalID:    event.Vital.ID,
-		PatientID:  event.Vital.PatientID,
-		Systolic:   event.Vital.Systolic,
-		Diastolic:  event.Vital.Diastolic,
-		TakenAt:    event.Vital.TakenAt,
-		ReceivedAt: event.Vital.ReceivedAt,
-		Reason:     reason,
-		Status:     AlertStatusActive,
-		Created:    time.Now().UTC(),
-	}
-
-	if _, err := w.store.AddAlert(ctx, alert); err != nil {
+		VitalID:      event.Vital.ID,
+		PatientID:    event.Vital.PatientID,
+		Kind:         event.Vital.Kind,
+		Systolic:     event.Vital.Systolic,
+		Diastolic:    event.Vital.Diastolic,
+		Value:        event.Vital.Value,
+		TakenAt:      event.Vital.TakenAt,
+		ReceivedAt:   event.Vital.ReceivedAt,
+		Reason:       reason,
+		Severity:     MaxSeverity(findings),
+		Status:       AlertStatusActive,
+		Created:      now,
+		VitalIDs:     []int64{event.Vital.ID},
+		ReadingCount: 1,
+		LastSeenAt:   now,
+
+		ThresholdVersion: thresholdVersion,
+	}
+
+	created := AlertHistoryEntry{Type: AlertHistoryCreated, At: now, Status: AlertStatusActive, VitalID: event.Vital.ID}
+	stored, err := w.store.AddAlert(ctx, alert, created)
+	if err != nil {
 		log.Printf("alert worker failed to store alert: %v", err)
 		return
 	}
 
 	log.Printf("[Alert] %s: %s", event.Vital.PatientID, reason)
 
+	for _, old := range stale {
+		w.supersede(ctx, old, stored)
+	}
+
+	if w.messageQueue != nil {
+		stored = w.requestRetake(ctx, stored)
+	}
+	w.publish(ctx, Event{Type: EventTypeAlertCreated, Alert: stored})
+}
+
+// requestRetake queues the patient's retake request for alert and links it
+// to the alert, returning the alert as stored. A request the patient's
+// cooldown would drop is scheduled for when it ends instead.
+func (w *AlertWorker) requestRetake(ctx context.Context, alert Alert) Alert {
+	data := alertTemplateData(alert)
+	request := Message{
+		PatientID: alert.PatientID,
+		AlertID:   alert.ID,
+		Subject:   w.composer.ComposeSubject(NotificationRetakeRequest, RecipientPatient, data),
+		Content:   w.composer.Compose(NotificationRetakeRequest, RecipientPatient, data),
+		Priority:  PriorityForSeverity(alert.Severity),
+	}
+	msg, err := w.messageQueue.Enqueue(request)
+	if errors.Is(err, ErrNotificationThrottled) {
+		// Hold the request until the cooldown ends rather than drop
+		// it: the alert needs a retake to settle.
+		request.ScheduledFor = w.messageQueue.CooldownUntil(request.PatientID)
+		w.recordHistory(ctx, AlertHistoryEntry{
+			AlertID: alert.ID,
+			Type:    AlertHistoryNotificationThrottled,
+			At:      w.clock.Now(),
+			Note:    "patient cooldown until " + request.ScheduledFor.UTC().Format(time.RFC3339),
+		})
+		log.Printf("[Alert] %s: notification for alert %d held by cooldown until %s", alert.PatientID, alert.ID, request.ScheduledFor.Format(time.RFC3339))
+		msg, err = w.messageQueue.Enqueue(request)
+	}
+	if err != nil {
+		log.Printf("alert worker failed to queue notification for alert %d: %v", alert.ID, err)
+		return alert
+	}
+	// Enqueue only moves the request's time, including one held by the
+	// cooldown, if it falls in quiet hours.
+	if msg.ScheduledFor.After(request.ScheduledFor) {
+		log.Printf("[Alert] %s: notification for alert %d deferred to %s by quiet hours", alert.PatientID, alert.ID, msg.ScheduledFor.Format(time.RFC3339))
+	}
+	updated, err := linkMessage(ctx, w.store, alert.ID, msg.ID)
+	if err != nil {
+		log.Printf("alert worker failed to link message to alert %d: %v", alert.ID, err)
+		return alert
+	}
+	return updated
+}
+
+// ResendRetakeRequests queues the retake request again for each open alert
+// whose request was neither sent nor is in the message queue, as happens to
+// requests still waiting when the server stops, since the queue is kept in
+// memory. It returns how many it queued; call it before Run.
+func (w *AlertWorker) ResendRetakeRequests(ctx context.Context) (int, error) {
+	if w.messageQueue == nil {
+		return 0, nil
+	}
+	alerts, err := w.store.QueryAlerts(ctx, AlertQuery{Statuses: []AlertStatus{AlertStatusActive, AlertStatusAcknowledged}})
+	if err != nil {
+		return 0, fmt.Errorf("list open alerts: %w", err)
+	}
+	resent := 0
+	for _, alert := range alerts {
+		if _, ok := retakeRequest(w.messageQueue, alert); ok {
+			continue
+		}
+		_, sent, err := requestSentAt(ctx, w.store, alert.ID)
+		if err != nil {
+			return resent, err
+		}
+		if sent {
+			continue
+		}
+		w.requestRetake(ctx, alert)
+		log.Printf("[Alert] %s: retake request for alert %d queued again", alert.PatientID, alert.ID)
+		resent++
+	}
+	return resent, nil
+}
+
+func (w *AlertWorker) recordHistory(ctx context.Context, entry AlertHistoryEntry) {
+	if err := w.store.AppendAlertHistory(ctx, entry); err != nil {
+		log.Printf("alert worker failed to record %s for alert %d: %v", entry.Type, entry.AlertID, err)
+	}
+}
+
+// supersede closes old, an open alert that fell outside the dedup window,
+// as a duplicate of current, the alert its new reading opened, so the
+// patient is left with one open alert of the kind.
+func (w *AlertWorker) supersede(ctx context.Context, old, current Alert) {
+	now := w.clock.Now().UTC()
+	note := fmt.Sprintf("superseded by alert %d", current.ID)
+	closed, prev, err := modifyAlert(ctx, w.store, old.ID, func(a *Alert) (AlertHistoryEntry, error) {
+		from := a.Status
+		if err := a.Transition(AlertStatusResolved, 0, now); err != nil {
+			return AlertHistoryEntry{}, err
+		}
+		a.Resolution = AlertAction{By: alertWorkerActor, At: now, Note: note}
+		a.ResolutionReason = ResolutionDuplicate
+		entry := statusChange(AlertHistoryResolved, *a, from)
+		entry.Actor, entry.Note, entry.ResolutionReason = alertWorkerActor, note, ResolutionDuplicate
+		return entry, nil
+	})
+	if err != nil {
+		// A retake or clinician may have closed it meanwhile.
+		log.Printf("alert worker failed to supersede alert %d: %v", old.ID, err)
+		return
+	}
+	if w.messageQueue != nil {
+		w.messageQueue.CancelByAlert(old.ID)
+	}
+	w.publish(ctx, Event{Type: EventTypeAlertStatusChanged, Alert: closed, PreviousStatus: prev})
+	log.Printf("[Alert] %s: alert %d %s", old.PatientID, old.ID, note)
+}
+
+func (w *AlertWorker) publish(ctx context.Context, event Event) {
+	if err := w.events.Publish(ctx, event); err != nil && ctx.Err() == nil {
+		log.Printf("alert worker failed to publish %s for alert %d: %v", event.Type, event.Alert.ID, err)
+	}
+}
+
+// applyToOpenAlerts treats vital as a follow-up reading for the patient's
+// open alerts of the same kind. Retakes move an alert to its terminal
+// state; abnormal readings that arrive before the patient was notified are
+// folded into the open alert per the dedup policy, and those that arrive
+// after the retake confirmed it join the CONFIRMED_ABNORMAL alert the care
+// team is already following up on. It reports whether the
+// reading was absorbed by an existing alert, in which case no new alert is
+// needed, and otherwise returns the open alerts the reading fell outside
+// the dedup window of, which the new alert supersedes.
+func (w *AlertWorker) applyToOpenAlerts(ctx context.Context, alerts []Alert, vital Vital, abnormal bool) (absorbed bool, stale []Alert) {
+	for _, alert := range alerts {
+		if alert.PatientID != vital.PatientID || alert.Kind != vital.Kind {
+			continue
+		}
+		if vital.TakenAt.Before(alert.TakenAt) {
+			continue
+		}
+		if alert.Status == AlertStatusConfirmedAbnormal {
+			if abnormal && !absorbed && w.attach(ctx, alert, vital) {
+				absorbed = true
+			}
+			continue
+		}
+		if !alert.Status.IsOpen() {
+			continue
+		}
+
+		// A normal reading makes the pending "please retake" pointless.
+		// Cancel first so the sent check below sees the message's final
+		// state rather than racing the message worker.
+		if !abnormal && w.messageQueue != nil {
+			w.messageQueue.CancelByAlert(alert.ID)
+		}
+
+		now := w.clock.Now()
+		notified := w.notificationSent(ctx, alert)
+		var next AlertStatus
+		switch {
+		case !abnormal && notified:
+			next = AlertStatusResolvedByRetake
+		case !abnormal:
+			next = AlertStatusAutoResolved
+		case notified:
+			next = AlertStatusConfirmedAbnormal
+		default:
+			if w.dedup.Expired(alert, now) {
+				stale = append(stale, alert)
+				continue
+			}
+			if absorbed || !w.dedup.CanAttach(alert, now) {
+				continue
+			}
+			absorbed = w.attach(ctx, alert, vital)
+			continue
+		}
+
+		// A clinician may have resolved the alert since it was listed, in
+		// which case the transition fails and their decision stands.
+		settled, prev, err := modifyAlert(ctx, w.store, alert.ID, func(a *Alert) (AlertHistoryEntry, error) {
+			from := a.Status
+			if err := a.Transition(next, vital.ID, now); err != nil {
+				return AlertHistoryEntry{}, err
+			}
+			return statusChange(AlertHistoryStatusChanged, *a, from), nil
+		})
+		if err != nil {
+			log.Printf("alert worker failed to transition alert %d: %v", alert.ID, err)
+			continue
+		}
+		if next == AlertStatusConfirmedAbnormal {
+			absorbed = true
+		}
+		w.publish(ctx, Event{Type: EventTypeAlertStatusChanged, Alert: settled, PreviousStatus: prev})
+		log.Printf("[Alert] %s: alert %d %s -> %s (vital %d)", vital.PatientID, alert.ID, prev, next, vital.ID)
+	}
+	if absorbed {
+		stale = nil
+	}
+	return absorbed, stale
+}
+
+// attach adds vital to alert's readings without changing its status and
+// reports whether it did.
+func (w *AlertWorker) attach(ctx context.Context, alert Alert, vital Vital) bool {
+	now := w.clock.Now()
+	attached, _, err := modifyAlert(ctx, w.store, alert.ID, func(a *Alert) (AlertHistoryEntry, error) {
+		a.Attach(vital, now)
+		return AlertHistoryEntry{Type: AlertHistoryVitalAttached, At: now, FromStatus: a.Status, Status: a.Status, VitalID: vital.ID}, nil
+	})
+	if err != nil {
+		log.Printf("alert worker failed to attach vital %d to alert %d: %v", vital.ID, alert.ID, err)
+		return false
+	}
+	log.Printf("[Alert] %s: vital %d attached to alert %d (%d readings)", vital.PatientID, vital.ID, alert.ID, attached.ReadingCount)
+	return true
+}
+
+func alreadyApplied(alerts []Alert, vital Vital) bool {
+	if vital.ID == 0 {
+		return false
+	}
+	for _, alert := range alerts {
+		if alert.PatientID == vital.PatientID && (alert.HasVital(vital.ID) || alert.StatusVitalID == vital.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesFor returns the rule set to evaluate for a patient, with their
+// threshold override applied, and the override version (zero if none).
+func (w *AlertWorker) rulesFor(ctx context.Context, patientID string) (RuleSet, int64) {
+	override, err := w.store.GetPatientThreshold(ctx, patientID)
+	if err != nil {
+		if !errors.Is(err, ErrThresholdNotFound) {
+			log.Printf("alert worker failed to load thresholds for %s: %v", patientID, err)
+		}
+		return w.rules, 0
+	}
+	return w.rules.ForThresholds(override.Thresholds), override.Version
+}
+
+// history returns the patient's readings of vital's kind taken up to it,
+// oldest first.
+func (w *AlertWorker) history(ctx context.Context, vital Vital) ([]Vital, error) {
+	vitals, err := w.store.QueryVitals(ctx, VitalQuery{
+		PatientID: vital.PatientID,
+		// Before is exclusive; keep readings taken at the same instant.
+		TakenAt: TimeRange{Before: vital.TakenAt.Add(time.Nanosecond)},
+		Order:   SortAscending,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var history []Vital
+	for _, v := range vitals {
+		if v.Kind == vital.Kind && v.ID != vital.ID {
+			history = append(history, v)
+		}
+	}
+	return history, nil
+}
+
+// notificationSent reports whether the patient's retake request for alert
+// went out: from the queue for a request it still holds, and otherwise from
+// the alert's history, which outlives the queue across restarts.
+func (w *AlertWorker) notificationSent(ctx context.Context, alert Alert) bool {
 	if w.messageQueue != nil {
-		content := fmt.Sprintf("Alert: %s. Please retake your vitals.", reason)
-		w.messageQueue.Enqueue(event.Vital.PatientID, content)
+		if msg, ok := retakeRequest(w.messageQueue, alert); ok {
+			return msg.Status == MessageStatusSent
+		}
+	}
+	_, sent, err := requestSentAt(ctx, w.store, alert.ID)
+	if err != nil {
+		log.Printf("alert worker failed to load history for alert %d: %v", alert.ID, err)
+	}
+	return sent
+}
+
+// retakeRequest returns the queued retake request alert links to. Message
+// IDs restart with the queue, so a message that isn't the patient's request
+// for this alert is ignored.
+func retakeRequest(queue *MessageQueue, alert Alert) (Message, bool) {
+	if alert.MessageID == 0 {
+		return Message{}, false
+	}
+	msg, ok := queue.GetMessage(alert.MessageID)
+	if !ok || msg.AlertID != alert.ID || msg.Recipient != RecipientPatient {
+		return Message{}, false
+	}
+	return msg, true
+}
+
+// requestSentAt reports when the alert's history records a retake request
+// being sent to the patient.
+func requestSentAt(ctx context.Context, store Store, alertID int64) (time.Time, bool, error) {
+	history, err := store.AlertHistory(ctx, alertID)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	for _, entry := range history {
+		if entry.Type == AlertHistoryNotificationSent && entry.Recipient == RecipientPatient {
+			return entry.At, true, nil
+		}
 	}
+	return time.Time{}, false, nil
 }
@@ -2,6 +2,8 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -65,6 +67,411 @@ func TestAlertWorkerCreatesAlertForAbnormalVitals(t *testing.T) {
 	}
 }
 
+func TestAlertWorkerAutoResolvesBeforeNotificationSent(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	waitForAlertStatus(t, store, AlertStatusActive)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 2, PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()})
+	alert := waitForAlertStatus(t, store, AlertStatusAutoResolved)
+	if alert.StatusVitalID != 2 {
+		t.Fatalf("unexpected status vital id: %d", alert.StatusVitalID)
+	}
+	if alert.StatusChangedAt.IsZero() {
+		t.Fatal("expected status change timestamp")
+	}
+
+	msg, ok := queue.GetMessage(alert.MessageID)
+	if !ok {
+		t.Fatalf("expected message %d linked to alert", alert.MessageID)
+	}
+	if msg.AlertID != alert.ID || msg.Status != MessageStatusCancelled {
+		t.Fatalf("expected queued notification to be cancelled, got %+v", msg)
+	}
+	if next := queue.ProcessNext(ctx); next != nil {
+		t.Fatalf("expected nothing left to send, got %+v", next)
+	}
+}
+
+func TestAlertWorkerResolvesByRetakeAfterNotificationSent(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	waitForAlertStatus(t, store, AlertStatusActive)
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.Status != MessageStatusSent {
+		t.Fatalf("expected notification to be sent, got %+v", msg)
+	}
+
+	publishVital(t, ctx, pubsub, Vital{ID: 2, PatientID: "patient-1", Systolic: 118, Diastolic: 76, TakenAt: time.Now().UTC()})
+	alert := waitForAlertStatus(t, store, AlertStatusResolvedByRetake)
+	if alert.StatusVitalID != 2 {
+		t.Fatalf("unexpected status vital id: %d", alert.StatusVitalID)
+	}
+}
+
+func TestAlertWorkerSettlesAcknowledgedAlertOnRetake(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+	service := NewService(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	alert := waitForAlertStatus(t, store, AlertStatusActive)
+	waitFor(t, 500*time.Millisecond, func() bool {
+		current, err := store.GetAlert(ctx, alert.ID)
+		return err == nil && current.MessageID != 0
+	})
+	if _, err := service.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{AlertID: alert.ID, Clinician: "dr-lee"}); err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+
+	publishVital(t, ctx, pubsub, Vital{ID: 2, PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()})
+	settled := waitForAlertStatus(t, store, AlertStatusAutoResolved)
+	if settled.Acknowledgement.By != "dr-lee" || settled.StatusVitalID != 2 {
+		t.Fatalf("expected the retake to settle the acknowledged alert, got %+v", settled)
+	}
+}
+
+func TestAlertWorkerConfirmsAbnormalRetake(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	waitForAlertStatus(t, store, AlertStatusActive)
+	queue.ProcessNext(ctx)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 2, PatientID: "patient-1", Systolic: 195, Diastolic: 125, TakenAt: time.Now().UTC()})
+	waitForAlertStatus(t, store, AlertStatusConfirmedAbnormal)
+
+	alerts, err := store.ListAlerts(ctx)
+	if err != nil {
+		t.Fatalf("list alerts failed: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected confirmed retake not to open a new alert, got %d alerts", len(alerts))
+	}
+	if got := len(queue.ListMessages()); got != 1 {
+		t.Fatalf("expected 1 message, got %d", got)
+	}
+
+	// Further abnormal readings join the confirmed alert the care team is
+	// following up on rather than asking the patient to retake again.
+	publishVital(t, ctx, pubsub, Vital{ID: 3, PatientID: "patient-1", Systolic: 200, Diastolic: 128, TakenAt: time.Now().UTC()})
+	waitFor(t, time.Second, func() bool {
+		alert, err := store.GetAlert(ctx, alerts[0].ID)
+		return err == nil && alert.HasVital(3)
+	})
+	alerts, _ = store.ListAlerts(ctx)
+	if len(alerts) != 1 || alerts[0].Status != AlertStatusConfirmedAbnormal || alerts[0].ReadingCount != 2 {
+		t.Fatalf("expected the reading attached to the confirmed alert, got %+v", alerts)
+	}
+	if got := len(queue.ListMessages()); got != 1 {
+		t.Fatalf("expected no further message, got %d", got)
+	}
+	history, err := store.AlertHistory(ctx, alerts[0].ID)
+	if err != nil || history[len(history)-1].Type != AlertHistoryVitalAttached || history[len(history)-1].VitalID != 3 {
+		t.Fatalf("expected a vital-attached history entry, got %+v (%v)", history, err)
+	}
+}
+
+func TestAlertWorkerSettlesAlertsFromHistoryAfterRestart(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Both alerts link message 1 from before the restart. Only patient-2's
+	// history says it was sent; the queue's new message 1 is unrelated.
+	taken := time.Now().Add(-time.Hour).UTC()
+	unsent, _ := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive, TakenAt: taken, MessageID: 1})
+	sent, _ := store.AddAlert(ctx, Alert{PatientID: "patient-2", Status: AlertStatusActive, TakenAt: taken, MessageID: 1})
+	store.AppendAlertHistory(ctx, AlertHistoryEntry{AlertID: sent.ID, Type: AlertHistoryNotificationSent, At: taken, MessageID: 1, Recipient: RecipientPatient})
+	queue.Enqueue(Message{PatientID: "patient-9", AlertID: 99, Content: "retake"})
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.ID != 1 || msg.Status != MessageStatusSent {
+		t.Fatalf("expected unrelated message 1 sent, got %+v", msg)
+	}
+
+	go worker.Run(ctx)
+	publishVital(t, ctx, pubsub, Vital{ID: 1, PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()})
+	publishVital(t, ctx, pubsub, Vital{ID: 2, PatientID: "patient-2", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()})
+	waitFor(t, time.Second, func() bool {
+		a, _ := store.GetAlert(ctx, unsent.ID)
+		b, _ := store.GetAlert(ctx, sent.ID)
+		return a.Status == AlertStatusAutoResolved && b.Status == AlertStatusResolvedByRetake
+	})
+}
+
+func TestAlertWorkerResendsRetakeRequestsMissingFromQueue(t *testing.T) {
+	store := NewInMemoryStore()
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(NewPubSub(), store, 8, queue)
+	ctx := context.Background()
+
+	pending, _ := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive, Severity: SeverityHigh, MessageID: 4})
+	sent, _ := store.AddAlert(ctx, Alert{PatientID: "patient-2", Status: AlertStatusAcknowledged, MessageID: 5})
+	store.AppendAlertHistory(ctx, AlertHistoryEntry{AlertID: sent.ID, Type: AlertHistoryNotificationSent, At: time.Now(), MessageID: 5, Recipient: RecipientPatient})
+	store.AddAlert(ctx, Alert{PatientID: "patient-3", Status: AlertStatusConfirmedAbnormal, MessageID: 6})
+
+	if n, err := worker.ResendRetakeRequests(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one request queued again, got %d (%v)", n, err)
+	}
+	messages := queue.ListMessages()
+	if len(messages) != 1 || messages[0].AlertID != pending.ID || messages[0].Recipient != RecipientPatient {
+		t.Fatalf("expected a retake request for alert %d, got %+v", pending.ID, messages)
+	}
+	if got, _ := store.GetAlert(ctx, pending.ID); got.MessageID != messages[0].ID {
+		t.Fatalf("expected the alert linked to message %d, got %d", messages[0].ID, got.MessageID)
+	}
+	// Once queued, a request isn't queued again.
+	if n, err := worker.ResendRetakeRequests(ctx); err != nil || n != 0 {
+		t.Fatalf("expected nothing more to resend, got %d (%v)", n, err)
+	}
+}
+
+func TestAlertWorkerFoldsRepeatedAbnormalReadingsIntoOpenAlert(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	for i := int64(1); i <= 5; i++ {
+		publishVital(t, ctx, pubsub, Vital{ID: i, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	}
+
+	waitFor(t, 500*time.Millisecond, func() bool {
+		alerts, err := store.ListAlerts(ctx)
+		return err == nil && len(alerts) == 1 && alerts[0].ReadingCount == 5
+	})
+
+	alerts, _ := store.ListAlerts(ctx)
+	if got := alerts[0].VitalIDs; len(got) != 5 || got[0] != 1 || got[4] != 5 {
+		t.Fatalf("unexpected attached vital ids: %v", got)
+	}
+	if got := len(queue.ListMessages()); got != 1 {
+		t.Fatalf("expected a single notification, got %d", got)
+	}
+}
+
+func TestAlertWorkerDedupWindowUsesClock(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	clock := newFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	worker := NewAlertWorker(pubsub, store, 8, nil,
+		WithDedupPolicy(DedupPolicy{Enabled: true, Window: 10 * time.Minute}),
+		WithWorkerClock(clock),
+	)
+
+	ctx := context.Background()
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: clock.Now()}})
+	clock.Advance(9 * time.Minute)
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 2, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: clock.Now()}})
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 1 || alerts[0].ReadingCount != 2 {
+		t.Fatalf("expected reading inside window to attach, got %+v", alerts)
+	}
+	if !alerts[0].LastSeenAt.Equal(clock.Now()) {
+		t.Fatalf("unexpected last seen: %v", alerts[0].LastSeenAt)
+	}
+
+	clock.Advance(11 * time.Minute)
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 3, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: clock.Now()}})
+
+	alerts, _ = store.ListAlerts(ctx)
+	if len(alerts) != 2 {
+		t.Fatalf("expected reading outside window to open a new alert, got %d alerts", len(alerts))
+	}
+	// The new alert supersedes the stale one rather than leaving it open.
+	old, _ := store.GetAlert(ctx, alerts[0].ID)
+	if old.Status != AlertStatusResolved || old.ResolutionReason != ResolutionDuplicate || old.Resolution.Note != fmt.Sprintf("superseded by alert %d", alerts[1].ID) {
+		t.Fatalf("expected the stale alert closed as a duplicate, got %+v", old)
+	}
+	if current, _ := store.GetAlert(ctx, alerts[1].ID); current.Status != AlertStatusActive {
+		t.Fatalf("expected the new alert active, got %s", current.Status)
+	}
+}
+
+func TestAlertWorkerSchedulesThrottledNotification(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	clock := newFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	queue := NewMessageQueue(0, 0, WithPatientCooldown(10*time.Minute), WithQueueClock(clock))
+	worker := NewAlertWorker(pubsub, store, 8, queue, WithWorkerClock(clock))
+	ctx := context.Background()
+
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: clock.Now()}})
+	clock.Advance(time.Minute)
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 2, PatientID: "patient-1", Kind: VitalKindSpO2, Value: 85, TakenAt: clock.Now()}})
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 2 {
+		t.Fatalf("expected an alert per kind, got %+v", alerts)
+	}
+	msg, ok := queue.GetMessage(alerts[1].MessageID)
+	if !ok || !msg.ScheduledFor.Equal(time.Date(2025, 1, 1, 9, 10, 0, 0, time.UTC)) {
+		t.Fatalf("expected the second retake request scheduled for the end of the cooldown, got %+v", msg)
+	}
+	history, _ := store.AlertHistory(ctx, alerts[1].ID)
+	if len(history) < 2 || history[1].Type != AlertHistoryNotificationThrottled || history[1].Note != "patient cooldown until 2025-01-01T09:10:00Z" {
+		t.Fatalf("expected the throttling in the timeline, got %+v", history)
+	}
+}
+
+func TestAlertWorkerLinksMessageDespiteConcurrentUpdate(t *testing.T) {
+	store := &conflictingStore{Store: NewInMemoryStore()}
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(NewPubSub(), store, 8, queue)
+	ctx := context.Background()
+
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()}})
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 1 || !store.raced {
+		t.Fatalf("expected one alert updated concurrently, got %+v", alerts)
+	}
+	if alerts[0].MessageID == 0 || alerts[0].ReadingCount != 2 {
+		t.Fatalf("expected the message link on top of the concurrent update, got %+v", alerts[0])
+	}
+	if _, ok := queue.GetMessage(alerts[0].MessageID); !ok {
+		t.Fatalf("expected the linked message %d to be queued", alerts[0].MessageID)
+	}
+}
+
+type unlistableStore struct {
+	Store
+	fail bool
+}
+
+func (s *unlistableStore) QueryAlerts(ctx context.Context, q AlertQuery) ([]Alert, error) {
+	if s.fail {
+		return nil, errors.New("database is locked")
+	}
+	return s.Store.QueryAlerts(ctx, q)
+}
+
+func TestAlertWorkerSkipsReadingWhenAlertsCantBeListed(t *testing.T) {
+	store := &unlistableStore{Store: NewInMemoryStore()}
+	queue := NewMessageQueue(0, 0)
+	worker := NewAlertWorker(NewPubSub(), store, 8, queue)
+	ctx := context.Background()
+	vital := Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()}
+
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: vital})
+	// A redelivery while the store can't list alerts must not duplicate it.
+	store.fail = true
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: vital})
+	vital.ID = 2
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: vital})
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 1 {
+		t.Fatalf("expected one alert, got %d", len(alerts))
+	}
+	if got := len(queue.ListMessages()); got != 1 {
+		t.Fatalf("expected one retake request, got %d", got)
+	}
+}
+
+func TestAlertWorkerDedupDisabledCreatesAlertPerReading(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	worker := NewAlertWorker(pubsub, store, 8, nil, WithDedupPolicy(DedupPolicy{}))
+
+	ctx := context.Background()
+	for i := int64(1); i <= 3; i++ {
+		worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: i, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()}})
+	}
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 3 {
+		t.Fatalf("expected 3 alerts with dedup disabled, got %d", len(alerts))
+	}
+}
+
+func TestAlertWorkerIgnoresRedeliveredVital(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	worker := NewAlertWorker(pubsub, store, 8, nil)
+
+	ctx := context.Background()
+	event := Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()}}
+	worker.handleEvent(ctx, event)
+	worker.handleEvent(ctx, event)
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 1 || alerts[0].ReadingCount != 1 {
+		t.Fatalf("expected redelivery to be a no-op, got %+v", alerts)
+	}
+
+	// Once the alert is settled, neither the reading that opened it nor the
+	// one that resolved it may act again.
+	retake := Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 2, PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}}
+	worker.handleEvent(ctx, retake)
+	worker.handleEvent(ctx, event)
+	worker.handleEvent(ctx, retake)
+
+	alerts, _ = store.ListAlerts(ctx)
+	if len(alerts) != 1 || alerts[0].Status != AlertStatusAutoResolved {
+		t.Fatalf("expected a single resolved alert after redelivery, got %+v", alerts)
+	}
+}
+
+func publishVital(t *testing.T, ctx context.Context, pubsub *PubSub, vital Vital) {
+	t.Helper()
+	if vital.ReceivedAt.IsZero() {
+		vital.ReceivedAt = time.Now().UTC()
+	}
+	if err := pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: vital}); err != nil {
+		t.Fatalf("publish vital %d: %v", vital.ID, err)
+	}
+}
+
+func waitForAlertStatus(t *testing.T, store Store, status AlertStatus) Alert {
+	t.Helper()
+	var found Alert
+	waitFor(t, 500*time.Millisecond, func() bool {
+		alerts, err := store.ListAlerts(context.Background())
+		if err != nil || len(alerts) == 0 {
+			return false
+		}
+		found = alerts[0]
+		return found.Status == status
+	})
+	return found
+}
+
 func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
 	deadline := time.After(timeout)
 	ticker := time.NewTicker(10 * time.Millisecond)
@@ -81,3 +488,72 @@ func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
 		}
 	}
 }
+
+func TestAlertWorkerPublishesAlertEvents(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	worker := NewAlertWorker(pubsub, store, 8, nil)
+	events, cancel := pubsub.SubscribeWith(SubscribeOptions{
+		Buffer: 8,
+		Types:  []EventType{EventTypeAlertCreated, EventTypeAlertStatusChanged},
+	})
+	defer cancel()
+
+	ctx := context.Background()
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()}})
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 2, PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}})
+
+	created := receive(t, events)
+	if created.Type != EventTypeAlertCreated || created.Alert.VitalID != 1 || created.Alert.Status != AlertStatusActive {
+		t.Fatalf("unexpected created event: %+v", created)
+	}
+	changed := receive(t, events)
+	if changed.Type != EventTypeAlertStatusChanged || changed.Alert.ID != created.Alert.ID ||
+		changed.PreviousStatus != AlertStatusActive || changed.Alert.Status != AlertStatusAutoResolved {
+		t.Fatalf("unexpected status event: %+v", changed)
+	}
+}
+
+func TestAlertWorkerKeepsKindsApart(t *testing.T) {
+	store := NewInMemoryStore()
+	worker := NewAlertWorker(NewPubSub(), store, 8, nil)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: now}})
+	// A normal pulse is not a retake of the blood pressure alert.
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 2, PatientID: "patient-1", Kind: VitalKindPulse, Value: 72, TakenAt: now.Add(time.Minute)}})
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 3, PatientID: "patient-1", Kind: VitalKindSpO2, Value: 85, TakenAt: now.Add(2 * time.Minute)}})
+
+	alerts, err := store.ListAlerts(ctx)
+	if err != nil {
+		t.Fatalf("list alerts: %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("expected separate blood pressure and SpO2 alerts, got %+v", alerts)
+	}
+	if alerts[0].Kind != VitalKindBloodPressure || alerts[0].Status != AlertStatusActive {
+		t.Fatalf("expected the blood pressure alert to stay open, got %+v", alerts[0])
+	}
+	if alerts[1].Kind != VitalKindSpO2 || alerts[1].Value != 85 || alerts[1].VitalID != 3 {
+		t.Fatalf("unexpected SpO2 alert: %+v", alerts[1])
+	}
+}
+
+func TestAlertWorkerSkipsFlaggedReadingsUnlessPolicyAllows(t *testing.T) {
+	ctx := context.Background()
+	flagged := Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 185, TakenAt: time.Now().UTC(), Flags: []QualityFlag{QualityNarrowPulsePressure}}
+
+	store := NewInMemoryStore()
+	NewAlertWorker(NewPubSub(), store, 8, nil).handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: flagged})
+	if alerts, _ := store.ListAlerts(ctx); len(alerts) != 0 {
+		t.Fatalf("expected the flagged reading not to alert, got %+v", alerts)
+	}
+
+	store = NewInMemoryStore()
+	policy := QualityPolicy{AlertOn: []QualityFlag{QualityNarrowPulsePressure}}
+	NewAlertWorker(NewPubSub(), store, 8, nil, WithQualityPolicy(policy)).handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: flagged})
+	if alerts, _ := store.ListAlerts(ctx); len(alerts) != 1 {
+		t.Fatalf("expected the opted-in flag to alert, got %+v", alerts)
+	}
+}
@@ -0,0 +1,15 @@
+package app
+
+import "time"
+
+// Clock abstracts the current time so time-based policies can be tested
+// deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now().UTC() }
+
+func SystemClock() Clock { return systemClock{} }
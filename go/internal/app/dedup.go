@@ -0,0 +1,56 @@
+package app
+
+import "time"
+
+// DedupPolicy controls how repeated abnormal readings are folded into a
+// patient's open alert instead of each opening a new one.
+type DedupPolicy struct {
+	Enabled bool
+	// Window bounds how long after an alert last saw a reading it may still
+	// absorb new ones. Zero means no limit.
+	Window time.Duration
+}
+
+func DefaultDedupPolicy() DedupPolicy {
+	return DedupPolicy{Enabled: true, Window: 24 * time.Hour}
+}
+
+func (p DedupPolicy) CanAttach(alert Alert, now time.Time) bool {
+	if !p.Enabled || !alert.Status.IsOpen() {
+		return false
+	}
+	if p.Window <= 0 {
+		return true
+	}
+	lastSeen := alert.LastSeenAt
+	if lastSeen.IsZero() {
+		lastSeen = alert.Created
+	}
+	return now.Sub(lastSeen) <= p.Window
+}
+
+// Expired reports whether alert, though open, last saw a reading longer
+// than the window ago, so a new abnormal reading opens a fresh alert that
+// supersedes it.
+func (p DedupPolicy) Expired(alert Alert, now time.Time) bool {
+	return p.Enabled && alert.Status.IsOpen() && !p.CanAttach(alert, now)
+}
+
+// Attach records vital as another abnormal reading on the alert.
+func (a *Alert) Attach(vital Vital, at time.Time) {
+	a.VitalIDs = append(a.VitalIDs, vital.ID)
+	a.ReadingCount++
+	a.LastSeenAt = at.UTC()
+}
+
+func (a Alert) HasVital(vitalID int64) bool {
+	if a.VitalID == vitalID {
+		return true
+	}
+	for _, id := range a.VitalIDs {
+		if id == vitalID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const defaultEscalationInterval = 30 * time.Second
+
+// EscalationTier notifies Recipient once an alert has gone unanswered for
+// After since the previous step: the patient's retake request going out,
+// for the first tier, or the previous tier's notification.
+type EscalationTier struct {
+	Recipient Recipient
+	After     time.Duration
+}
+
+// EscalationPolicy lists the care team tiers an unanswered alert escalates
+// through, in order, after the patient's retake request. An empty policy
+// never escalates.
+type EscalationPolicy struct {
+	Tiers []EscalationTier
+}
+
+func DefaultEscalationPolicy() EscalationPolicy {
+	return EscalationPolicy{Tiers: []EscalationTier{
+		{Recipient: RecipientOnCallNurse, After: 15 * time.Minute},
+		{Recipient: RecipientPhysician, After: 15 * time.Minute},
+	}}
+}
+
+// EscalationStep records one escalation notification on its alert.
+type EscalationStep struct {
+	// Tier counts from 1, the policy's first tier.
+	Tier      int
+	Recipient Recipient
+	At        time.Time
+	MessageID int64
+}
+
+// escalates reports whether nobody has responded to alert. Acknowledging or
+// closing an alert stops its escalation for good, even if a later reading
+// moves an acknowledged alert on to CONFIRMED_ABNORMAL.
+func escalates(alert Alert) bool {
+	if !alert.Acknowledgement.At.IsZero() {
+		return false
+	}
+	return alert.Status == AlertStatusActive || alert.Status == AlertStatusConfirmedAbnormal
+}
+
+// Escalator notifies the care team about alerts nobody has answered. It
+// checks on every Tick against its clock, so tests can drive it with a fake
+// clock instead of waiting.
+type Escalator struct {
+	store    Store
+	queue    *MessageQueue
+	policy   EscalationPolicy
+	composer *MessageComposer
+	clock    Clock
+	interval time.Duration
+}
+
+type EscalatorOption func(*Escalator)
+
+func WithEscalationPolicy(policy EscalationPolicy) EscalatorOption {
+	return func(e *Escalator) { e.policy = policy }
+}
+
+// WithEscalatorComposer sets how escalation messages are worded.
+func WithEscalatorComposer(composer *MessageComposer) EscalatorOption {
+	return func(e *Escalator) { e.composer = composer }
+}
+
+func WithEscalatorClock(clock Clock) EscalatorOption {
+	return func(e *Escalator) { e.clock = clock }
+}
+
+// WithEscalationInterval sets how often Run checks for alerts due to
+// escalate.
+func WithEscalationInterval(d time.Duration) EscalatorOption {
+	return func(e *Escalator) { e.interval = d }
+}
+
+func NewEscalator(store Store, queue *MessageQueue, opts ...EscalatorOption) *Escalator {
+	e := &Escalator{
+		store:    store,
+		queue:    queue,
+		policy:   DefaultEscalationPolicy(),
+		composer: defaultMessageComposer(),
+		clock:    SystemClock(),
+		interval: defaultEscalationInterval,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+func (e *Escalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Tick(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("escalator: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick escalates every alert whose next tier is due and returns how many it
+// escalated. An alert moves at most one tier per Tick.
+func (e *Escalator) Tick(ctx context.Context) (int, error) {
+	if len(e.policy.Tiers) == 0 {
+		return 0, nil
+	}
+	alerts, err := e.store.ListAlerts(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list alerts: %w", err)
+	}
+	now := e.clock.Now()
+	escalated := 0
+	for _, alert := range alerts {
+		tier, due := e.nextTier(ctx, alert, now)
+		if !due {
+			continue
+		}
+		err := e.escalate(ctx, alert, tier, now)
+		switch {
+		case err == nil:
+			escalated++
+		case errors.Is(err, ErrAlertConflict):
+			// The alert changed since it was listed, perhaps acknowledged;
+			// the next Tick looks at it afresh.
+		case ctx.Err() != nil:
+			return escalated, ctx.Err()
+		default:
+			log.Printf("escalator: alert %d: %v", alert.ID, err)
+		}
+	}
+	return escalated, nil
+}
+
+func (e *Escalator) nextTier(ctx context.Context, alert Alert, now time.Time) (int, bool) {
+	tier := len(alert.Escalations)
+	if !escalates(alert) || tier >= len(e.policy.Tiers) {
+		return 0, false
+	}
+	since := e.patientNotifiedAt(ctx, alert)
+	if tier > 0 {
+		since = alert.Escalations[tier-1].At
+	}
+	return tier, !now.Before(since.Add(e.policy.Tiers[tier].After))
+}
+
+// patientNotifiedAt is when the alert's retake request was sent or, until
+// then, when it is due, so quiet hours or a cooldown that defer the patient's
+// message defer the care team's page with it. A request the queue no longer
+// holds is found in the alert's history; failing both, it is when the alert
+// was raised.
+func (e *Escalator) patientNotifiedAt(ctx context.Context, alert Alert) time.Time {
+	since := alert.Created
+	if msg, ok := retakeRequest(e.queue, alert); ok {
+		for _, t := range []time.Time{msg.ScheduledFor, msg.SentAt} {
+			if t.After(since) {
+				since = t
+			}
+		}
+		return since
+	}
+	sentAt, sent, err := requestSentAt(ctx, e.store, alert.ID)
+	if err != nil {
+		log.Printf("escalator: alert %d: %v", alert.ID, err)
+	}
+	if sent && sentAt.After(since) {
+		since = sentAt
+	}
+	return since
+}
+
+// escalate queues the tier's notification and then records the step, with
+// its message, on the alert, so a step is never recorded for a message that
+// wasn't queued. If the alert was answered or escalated meanwhile, the
+// message is cancelled instead.
+func (e *Escalator) escalate(ctx context.Context, alert Alert, tier int, now time.Time) error {
+	recipient := e.policy.Tiers[tier].Recipient
+	data := alertTemplateData(alert)
+	data.Tier = tier + 1
+	msg, err := e.queue.Enqueue(Message{
+		PatientID: alert.PatientID,
+		Recipient: recipient,
+		AlertID:   alert.ID,
+		Subject:   e.composer.ComposeSubject(NotificationEscalation, recipient, data),
+		Content:   e.composer.Compose(NotificationEscalation, recipient, data),
+		Priority:  PriorityForSeverity(alert.Severity),
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue escalation: %w", err)
+	}
+
+	_, _, err = modifyAlert(ctx, e.store, alert.ID, func(a *Alert) (AlertHistoryEntry, error) {
+		if !escalates(*a) || len(a.Escalations) != tier {
+			return AlertHistoryEntry{}, ErrAlertConflict
+		}
+		a.Escalations = append(a.Escalations, EscalationStep{Tier: tier + 1, Recipient: recipient, At: now, MessageID: msg.ID})
+		return AlertHistoryEntry{Type: AlertHistoryEscalated, At: now, FromStatus: a.Status, Status: a.Status, MessageID: msg.ID, Recipient: recipient}, nil
+	})
+	if err != nil {
+		e.queue.Cancel(msg.ID)
+		return err
+	}
+	log.Printf("[Escalation] %s: alert %d escalated to %s (tier %d)", alert.PatientID, alert.ID, recipient, tier+1)
+	return nil
+}
@@ -0,0 +1,216 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEscalatorWalksTiersWhileAlertIsUnanswered(t *testing.T) {
+	store := NewInMemoryStore()
+	clock := newFakeClock(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	// The patient's cooldown must not hold back care team messages.
+	queue := NewMessageQueue(0, 0, WithPatientCooldown(time.Hour), WithQueueClock(clock))
+	escalator := NewEscalator(store, queue, WithEscalatorClock(clock), WithEscalationPolicy(EscalationPolicy{Tiers: []EscalationTier{
+		{Recipient: RecipientOnCallNurse, After: 15 * time.Minute},
+		{Recipient: RecipientPhysician, After: 10 * time.Minute},
+	}}))
+
+	ctx := context.Background()
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive, Created: clock.Now()})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	if _, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: alert.ID, Content: "Please retake"}); err != nil {
+		t.Fatalf("enqueue retake: %v", err)
+	}
+
+	steps := []struct {
+		advance time.Duration
+		want    int
+	}{
+		{14 * time.Minute, 0},
+		{time.Minute, 1}, // nurse, 15m after the alert
+		{0, 0},
+		{9 * time.Minute, 0},
+		{time.Minute, 1}, // physician, 10m after the nurse
+		{time.Hour, 0},   // no tiers left
+	}
+	for i, step := range steps {
+		clock.Advance(step.advance)
+		if got, err := escalator.Tick(ctx); err != nil || got != step.want {
+			t.Fatalf("step %d: expected %d escalations, got %d (%v)", i, step.want, got, err)
+		}
+	}
+
+	got, err := store.GetAlert(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("get alert: %v", err)
+	}
+	if len(got.Escalations) != 2 {
+		t.Fatalf("expected two escalation steps, got %+v", got.Escalations)
+	}
+	start := alert.Created
+	for i, want := range []EscalationStep{
+		{Tier: 1, Recipient: RecipientOnCallNurse, At: start.Add(15 * time.Minute)},
+		{Tier: 2, Recipient: RecipientPhysician, At: start.Add(25 * time.Minute)},
+	} {
+		step := got.Escalations[i]
+		if step.Tier != want.Tier || step.Recipient != want.Recipient || !step.At.Equal(want.At) {
+			t.Fatalf("step %d: expected %+v, got %+v", i, want, step)
+		}
+		msg, ok := queue.GetMessage(step.MessageID)
+		if !ok || msg.Recipient != want.Recipient || msg.AlertID != alert.ID {
+			t.Fatalf("step %d: expected a linked message to %s, got %+v", i, want.Recipient, msg)
+		}
+	}
+}
+
+func TestEscalatorWaitsForDeferredRetakeRequest(t *testing.T) {
+	store := NewInMemoryStore()
+	clock := newFakeClock(time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC))
+	hours, _ := ParseQuietHours("22:00-07:00", time.UTC)
+	queue := NewMessageQueue(0, 0, WithQueueClock(clock), WithQuietHours(fixedQuietHours(hours)),
+		WithMessageSender(MessageSenderFunc(func(context.Context, Message) (Delivery, error) { return Delivery{}, nil })))
+	escalator := NewEscalator(store, queue, WithEscalatorClock(clock))
+
+	ctx := context.Background()
+	request, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1, Content: "Please retake"})
+	if err != nil || request.ScheduledFor.IsZero() {
+		t.Fatalf("expected the retake request deferred by quiet hours, got %+v (%v)", request, err)
+	}
+	if _, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive, Created: clock.Now(), MessageID: request.ID}); err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+
+	clock.Advance(time.Hour)
+	if got, _ := escalator.Tick(ctx); got != 0 {
+		t.Fatalf("expected no escalation before the patient is messaged, got %d", got)
+	}
+	// The request goes out at 07:05, a little after quiet hours end.
+	clock.Advance(request.ScheduledFor.Add(5 * time.Minute).Sub(clock.Now()))
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.ID != request.ID {
+		t.Fatalf("expected the retake request sent, got %+v", msg)
+	}
+	clock.Advance(14 * time.Minute)
+	if got, _ := escalator.Tick(ctx); got != 0 {
+		t.Fatalf("expected no escalation 14m after the request, got %d", got)
+	}
+	clock.Advance(time.Minute)
+	if got, _ := escalator.Tick(ctx); got != 1 {
+		t.Fatalf("expected the nurse paged 15m after the request, got %d", got)
+	}
+}
+
+func TestEscalatorStopsOnceAlertIsAnswered(t *testing.T) {
+	store := NewInMemoryStore()
+	clock := newFakeClock(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	queue := NewMessageQueue(0, 0, WithQueueClock(clock))
+	escalator := NewEscalator(store, queue, WithEscalatorClock(clock))
+	service := NewService(store, WithServiceClock(clock))
+
+	ctx := context.Background()
+	add := func(status AlertStatus) Alert {
+		alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: status, Created: clock.Now()})
+		if err != nil {
+			t.Fatalf("add alert: %v", err)
+		}
+		return alert
+	}
+	unanswered := add(AlertStatusActive)
+	confirmed := add(AlertStatusConfirmedAbnormal)
+	add(AlertStatusAutoResolved)
+	add(AlertStatusResolvedByRetake)
+
+	clock.Advance(15 * time.Minute)
+	if got, err := escalator.Tick(ctx); err != nil || got != 2 {
+		t.Fatalf("expected the two open alerts to escalate, got %d (%v)", got, err)
+	}
+
+	if _, err := service.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{AlertID: unanswered.ID, Clinician: "nurse-kim"}); err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+	clock.Advance(15 * time.Minute)
+	if got, err := escalator.Tick(ctx); err != nil || got != 1 {
+		t.Fatalf("expected only the confirmed alert to escalate, got %d (%v)", got, err)
+	}
+
+	// An abnormal retake confirms the acknowledged alert; the care team
+	// already has it, so it stays out of escalation.
+	if _, _, err := modifyAlert(ctx, store, unanswered.ID, func(a *Alert) (AlertHistoryEntry, error) {
+		return AlertHistoryEntry{}, a.Transition(AlertStatusConfirmedAbnormal, 9, clock.Now())
+	}); err != nil {
+		t.Fatalf("confirm acknowledged alert: %v", err)
+	}
+	clock.Advance(15 * time.Minute)
+	if got, err := escalator.Tick(ctx); err != nil || got != 0 {
+		t.Fatalf("expected the acknowledged alert not to escalate again, got %d (%v)", got, err)
+	}
+	for id, want := range map[int64]int{unanswered.ID: 1, confirmed.ID: 2} {
+		alert, err := store.GetAlert(ctx, id)
+		if err != nil || len(alert.Escalations) != want {
+			t.Fatalf("alert %d: expected %d escalations, got %+v (%v)", id, want, alert.Escalations, err)
+		}
+	}
+}
+
+func TestEscalatorCancelsMessageForAlertAnsweredMeanwhile(t *testing.T) {
+	store := NewInMemoryStore()
+	clock := newFakeClock(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	queue := NewMessageQueue(0, 0, WithQueueClock(clock))
+	escalator := NewEscalator(store, queue, WithEscalatorClock(clock))
+	service := NewService(store, WithServiceClock(clock))
+	ctx := context.Background()
+
+	listed, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive, Created: clock.Now()})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	// A clinician acknowledges the alert after the escalator listed it.
+	if _, err := service.AcknowledgeAlert(ctx, AcknowledgeAlertRequest{AlertID: listed.ID, Clinician: "nurse-kim"}); err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+	clock.Advance(15 * time.Minute)
+	if err := escalator.escalate(ctx, listed, 0, clock.Now()); !errors.Is(err, ErrAlertConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	alert, _ := store.GetAlert(ctx, listed.ID)
+	if len(alert.Escalations) != 0 {
+		t.Fatalf("expected no escalation step, got %+v", alert.Escalations)
+	}
+	messages := queue.ListMessages()
+	if len(messages) != 1 || messages[0].Status != MessageStatusCancelled {
+		t.Fatalf("expected the escalation message to be cancelled, got %+v", messages)
+	}
+}
+
+func TestEscalatorRecordsStepDespiteConcurrentUpdate(t *testing.T) {
+	store := &conflictingStore{Store: NewInMemoryStore()}
+	clock := newFakeClock(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC))
+	queue := NewMessageQueue(0, 0, WithQueueClock(clock))
+	escalator := NewEscalator(store, queue, WithEscalatorClock(clock))
+	ctx := context.Background()
+
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive, Created: clock.Now(), ReadingCount: 1})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	clock.Advance(15 * time.Minute)
+	if got, err := escalator.Tick(ctx); err != nil || got != 1 {
+		t.Fatalf("expected one escalation, got %d (%v)", got, err)
+	}
+
+	alert, _ = store.GetAlert(ctx, alert.ID)
+	if !store.raced || alert.ReadingCount != 2 || len(alert.Escalations) != 1 {
+		t.Fatalf("expected the step on top of the concurrent update, got %+v", alert)
+	}
+	if msg, ok := queue.GetMessage(alert.Escalations[0].MessageID); !ok || msg.Status != MessageStatusQueued {
+		t.Fatalf("expected the step's message to stay queued, got %+v", msg)
+	}
+	history, _ := store.AlertHistory(ctx, alert.ID)
+	if n := len(history); n == 0 || history[n-1].Type != AlertHistoryEscalated || history[n-1].MessageID != alert.Escalations[0].MessageID {
+		t.Fatalf("expected the escalation in the timeline, got %+v", history)
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIdempotencyKeyReused means a key was presented again with a different
+// payload than the request that first used it.
+var ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+
+const (
+	DefaultIdempotencyRetention = 24 * time.Hour
+
+	maxIdempotencyKeyLen = 255
+)
+
+// IdempotencyKey ties a client-supplied key to the vital it created. Keys
+// are scoped to the patient and the client that sent them, so two clients
+// can't collide on the same key. A key is honoured until ExpiresAt; after
+// that it may be reused freely.
+type IdempotencyKey struct {
+	PatientID   string
+	ClientID    string
+	Key         string
+	Fingerprint string
+	VitalID     int64
+	ExpiresAt   time.Time
+}
+
+// scope is the key's identity in its patient and client's namespace.
+func (k IdempotencyKey) scope() string {
+	return k.PatientID + "\x00" + k.ClientID + "\x00" + k.Key
+}
+
+func (k IdempotencyKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.After(now)
+}
+
+// vitalFingerprint identifies the payload a key was first used with, so a
+// retry can be told apart from a different reading under the same key.
+func vitalFingerprint(v Vital) string {
+	fp := fmt.Sprintf("%s|%d|%d|%d", v.PatientID, v.Systolic, v.Diastolic, v.TakenAt.UnixNano())
+	if v.Kind != VitalKindBloodPressure {
+		// Blood pressure keeps the original format so keys stored before
+		// kinds existed still match their retries.
+		fp += fmt.Sprintf("|%d|%g", v.Kind, v.Value)
+	}
+	return fp
+}
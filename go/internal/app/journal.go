@@ -0,0 +1,387 @@
+package app
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const (
+	journalFile  = "journal.log"
+	snapshotFile = "snapshot.json"
+
+	// journalHeaderSize is the length and CRC32 that precede every payload.
+	journalHeaderSize = 8
+	// maxJournalRecord guards replay against a garbage length prefix.
+	maxJournalRecord = 16 << 20
+
+	defaultSnapshotEvery = 1000
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrJournalCorrupt is returned when a damaged record is followed by more of
+// the journal, so it can't be a write torn by a crash.
+var ErrJournalCorrupt = errors.New("journal is corrupt")
+
+type journalOp string
+
+const (
+	opAddVital      journalOp = "add_vital"
+	opAddAlert      journalOp = "add_alert"
+	opUpdateAlert   journalOp = "update_alert"
+	opSetThreshold  journalOp = "set_threshold"
+	opMarkDelivered journalOp = "mark_delivered"
+	opAppendHistory journalOp = "append_history"
+)
+
+// journalRecord is one committed mutation. LSN increases monotonically
+// across snapshots so replay can skip records a snapshot already covers.
+type journalRecord struct {
+	LSN       int64             `json:"lsn"`
+	Op        journalOp         `json:"op"`
+	Vital     *Vital            `json:"vital,omitempty"`
+	Key       *IdempotencyKey   `json:"idempotency_key,omitempty"`
+	Outbox    *OutboxEvent      `json:"outbox,omitempty"`
+	EventID   int64             `json:"event_id,omitempty"`
+	Alert     *Alert            `json:"alert,omitempty"`
+	Threshold *PatientThreshold `json:"threshold,omitempty"`
+	// History is appended with add_alert, update_alert and append_history.
+	History []AlertHistoryEntry `json:"history,omitempty"`
+}
+
+// storeSnapshot is the full InMemoryStore state as of LSN.
+type storeSnapshot struct {
+	LSN        int64                         `json:"lsn"`
+	VitalSeq   int64                         `json:"vital_seq"`
+	AlertSeq   int64                         `json:"alert_seq"`
+	Vitals     []Vital                       `json:"vitals"`
+	Alerts     []Alert                       `json:"alerts"`
+	Thresholds map[string][]PatientThreshold `json:"thresholds"`
+	Keys       map[string]IdempotencyKey     `json:"idempotency_keys"`
+	OutboxSeq  int64                         `json:"outbox_seq"`
+	Outbox     []OutboxEvent                 `json:"outbox"`
+	HistorySeq int64                         `json:"history_seq"`
+	History    map[int64][]AlertHistoryEntry `json:"history"`
+}
+
+type JournalOptions struct {
+	// Dir holds the journal and snapshot files; it is created if missing.
+	Dir string
+	// SnapshotEvery compacts the journal after this many records. Zero uses
+	// the default.
+	SnapshotEvery int
+	// NoSync skips fsync after each append. Faster, but a crash can lose
+	// the most recent writes.
+	NoSync bool
+}
+
+// OpenJournaledStore returns an InMemoryStore that appends every write to a
+// checksummed journal in opts.Dir and periodically compacts it into a
+// snapshot. Existing state is rebuilt from snapshot plus journal; a torn
+// final record is truncated rather than failing startup, but damage before
+// it fails with ErrJournalCorrupt and leaves the journal as it was.
+func OpenJournaledStore(opts JournalOptions) (*InMemoryStore, error) {
+	if opts.SnapshotEvery <= 0 {
+		opts.SnapshotEvery = defaultSnapshotEvery
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create journal dir: %w", err)
+	}
+
+	s := NewInMemoryStore()
+	snap, err := readSnapshot(filepath.Join(opts.Dir, snapshotFile))
+	if err != nil {
+		return nil, err
+	}
+	s.restoreLocked(snap)
+
+	f, err := os.OpenFile(filepath.Join(opts.Dir, journalFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open journal: %w", err)
+	}
+	lsn, records, err := replayJournal(f, snap.LSN, s.applyLocked)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	s.journal = &journal{
+		dir:           opts.Dir,
+		file:          f,
+		lsn:           max(lsn, snap.LSN),
+		sinceSnapshot: records,
+		snapshotEvery: opts.SnapshotEvery,
+		sync:          !opts.NoSync,
+	}
+	return s, nil
+}
+
+func (s *InMemoryStore) snapshotLocked() storeSnapshot {
+	return storeSnapshot{
+		VitalSeq:   s.vitalSeq,
+		AlertSeq:   s.alertSeq,
+		Vitals:     s.vitals,
+		Alerts:     s.alerts,
+		Thresholds: s.thresholds,
+		Keys:       s.keys,
+		OutboxSeq:  s.outboxSeq,
+		Outbox:     s.outbox,
+		HistorySeq: s.historySeq,
+		History:    s.history,
+	}
+}
+
+func (s *InMemoryStore) restoreLocked(snap storeSnapshot) {
+	s.vitalSeq = snap.VitalSeq
+	s.alertSeq = snap.AlertSeq
+	s.vitals = snap.Vitals
+	s.alerts = snap.Alerts
+	if snap.Thresholds != nil {
+		s.thresholds = snap.Thresholds
+	}
+	s.outboxSeq = snap.OutboxSeq
+	s.outbox = snap.Outbox
+	s.historySeq = snap.HistorySeq
+	if snap.History != nil {
+		s.history = snap.History
+	}
+	if snap.Keys != nil {
+		// Snapshots taken before keys were scoped name them by key alone.
+		patients := make(map[int64]string, len(s.vitals))
+		for _, v := range s.vitals {
+			patients[v.ID] = v.PatientID
+		}
+		for _, key := range snap.Keys {
+			if key.PatientID == "" {
+				key.PatientID = patients[key.VitalID]
+			}
+			s.keys[key.scope()] = key
+		}
+	}
+}
+
+type journal struct {
+	dir           string
+	file          *os.File
+	lsn           int64
+	sinceSnapshot int
+	snapshotEvery int
+	sync          bool
+}
+
+func (j *journal) append(rec *journalRecord) error {
+	rec.LSN = j.lsn + 1
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode journal record: %w", err)
+	}
+	buf := make([]byte, journalHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.Checksum(payload, crcTable))
+	copy(buf[journalHeaderSize:], payload)
+
+	if _, err := j.file.Write(buf); err != nil {
+		return fmt.Errorf("append journal: %w", err)
+	}
+	if j.sync {
+		if err := j.file.Sync(); err != nil {
+			return fmt.Errorf("sync journal: %w", err)
+		}
+	}
+	j.lsn = rec.LSN
+	j.sinceSnapshot++
+	return nil
+}
+
+func (j *journal) snapshotDue() bool {
+	return j.sinceSnapshot >= j.snapshotEvery
+}
+
+// snapshot writes state atomically and then truncates the journal. If we
+// crash between the two, replay skips the journal records the snapshot
+// already covers by LSN.
+func (j *journal) snapshot(state storeSnapshot) error {
+	state.LSN = j.lsn
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode snapshot: %w", err)
+	}
+
+	path := filepath.Join(j.dir, snapshotFile)
+	tmp := path + ".tmp"
+	if err := writeFileSync(tmp, data); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("install snapshot: %w", err)
+	}
+	// The rename isn't durable until the directory is synced; truncating
+	// the journal before then could lose both.
+	if err := syncDir(j.dir); err != nil {
+		return err
+	}
+
+	if err := j.file.Truncate(0); err != nil {
+		return fmt.Errorf("truncate journal: %w", err)
+	}
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind journal: %w", err)
+	}
+	j.sinceSnapshot = 0
+	return nil
+}
+
+func (j *journal) close() {
+	j.file.Close()
+}
+
+func readSnapshot(path string) (storeSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return storeSnapshot{}, nil
+	}
+	if err != nil {
+		return storeSnapshot{}, fmt.Errorf("read snapshot: %w", err)
+	}
+	var snap storeSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return storeSnapshot{}, fmt.Errorf("decode snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+// replayJournal applies every intact record after afterLSN and leaves f
+// positioned for appending. Only the final record can be torn by a crash
+// mid-append; it is truncated away. A damaged record with more of the
+// journal after it is corruption, and replay fails without truncating.
+func replayJournal(f *os.File, afterLSN int64, apply func(journalRecord)) (int64, int, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("stat journal: %w", err)
+	}
+	r := bufio.NewReader(f)
+	var (
+		offset  int64
+		lsn     int64
+		records int
+		header  [journalHeaderSize]byte
+	)
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Printf("journal: torn record header at offset %d, truncating", offset)
+			}
+			break
+		}
+		size := binary.LittleEndian.Uint32(header[0:4])
+		sum := binary.LittleEndian.Uint32(header[4:8])
+		end := offset + journalHeaderSize + int64(size)
+		if size == 0 || size > maxJournalRecord {
+			// A crash can leave the tail zero-filled; anything else is a
+			// damaged length.
+			if zeros, err := allZero(r); err != nil {
+				return 0, 0, fmt.Errorf("read journal: %w", err)
+			} else if !zeros || !isZero(header[:]) {
+				return 0, 0, fmt.Errorf("%w: bad record length %d at offset %d", ErrJournalCorrupt, size, offset)
+			}
+			log.Printf("journal: zero-filled tail at offset %d, truncating", offset)
+			break
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			log.Printf("journal: torn record at offset %d, truncating", offset)
+			break
+		}
+		var rec journalRecord
+		if crc32.Checksum(payload, crcTable) != sum {
+			err = errors.New("checksum mismatch")
+		} else {
+			err = json.Unmarshal(payload, &rec)
+		}
+		if err != nil {
+			if end < info.Size() {
+				return 0, 0, fmt.Errorf("%w: record at offset %d: %v", ErrJournalCorrupt, offset, err)
+			}
+			log.Printf("journal: damaged final record at offset %d, truncating: %v", offset, err)
+			break
+		}
+
+		offset = end
+		lsn = rec.LSN
+		if rec.LSN <= afterLSN {
+			continue
+		}
+		apply(rec)
+		records++
+	}
+
+	if err := f.Truncate(offset); err != nil {
+		return 0, 0, fmt.Errorf("truncate journal: %w", err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, 0, fmt.Errorf("seek journal: %w", err)
+	}
+	return lsn, records, nil
+}
+
+// allZero reports whether the rest of r is zero bytes.
+func allZero(r io.Reader) (bool, error) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if !isZero(buf[:n]) {
+			return false, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("sync %s: %w", dir, err)
+	}
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("sync %s: %w", path, err)
+	}
+	return f.Close()
+}
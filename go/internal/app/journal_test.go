@@ -0,0 +1,281 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournaledStoreConformance(t *testing.T) {
+	runStoreConformance(t, func(t *testing.T) Store {
+		store, err := OpenJournaledStore(JournalOptions{Dir: t.TempDir(), NoSync: true})
+		if err != nil {
+			t.Fatalf("open journaled store: %v", err)
+		}
+		return store
+	})
+}
+
+func TestJournaledStoreReplaysAfterCrash(t *testing.T) {
+	for _, every := range []int{1000, 2} {
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		store := openJournaled(t, dir, every)
+		seedJournaledStore(t, ctx, store)
+		// No Close: simulate a crash so recovery has to replay the journal.
+
+		recovered := openJournaled(t, dir, every)
+		assertSeededState(t, ctx, recovered)
+
+		vital, err := recovered.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()})
+		if err != nil {
+			t.Fatalf("add vital after recovery: %v", err)
+		}
+		if vital.ID != 3 {
+			t.Fatalf("snapshotEvery=%d: expected vital sequence to resume at 3, got %d", every, vital.ID)
+		}
+		alert, err := recovered.AddAlert(ctx, Alert{PatientID: "patient-1"})
+		if err != nil {
+			t.Fatalf("add alert after recovery: %v", err)
+		}
+		if alert.ID != 2 {
+			t.Fatalf("snapshotEvery=%d: expected alert sequence to resume at 2, got %d", every, alert.ID)
+		}
+	}
+}
+
+func TestJournaledStoreKeepsIdempotencyKeys(t *testing.T) {
+	for _, closeFirst := range []bool{false, true} {
+		dir := t.TempDir()
+		ctx := context.Background()
+
+		store := openJournaled(t, dir, 1000)
+		vital := Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}
+		key := IdempotencyKey{Key: "key-1", Fingerprint: vitalFingerprint(vital), ExpiresAt: time.Now().Add(time.Hour)}
+		first, _, err := store.AddVitalIdempotent(ctx, vital, key)
+		if err != nil {
+			t.Fatalf("add keyed vital: %v", err)
+		}
+		if closeFirst {
+			store.Close()
+		}
+
+		recovered := openJournaled(t, dir, 1000)
+		again, replayed, err := recovered.AddVitalIdempotent(ctx, vital, key)
+		if err != nil || !replayed || again.ID != first.ID {
+			t.Fatalf("close=%v: expected key to survive restart, got %+v replayed=%v err=%v", closeFirst, again, replayed, err)
+		}
+	}
+}
+
+func TestJournaledStoreKeepsPendingOutboxEvents(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store := openJournaled(t, dir, 1000)
+	seedJournaledStore(t, ctx, store)
+	pending, _ := store.ListPendingEvents(ctx, 0)
+	if err := store.MarkEventDelivered(ctx, pending[0].ID); err != nil {
+		t.Fatalf("mark delivered: %v", err)
+	}
+
+	recovered := openJournaled(t, dir, 1000)
+	remaining, err := recovered.ListPendingEvents(ctx, 0)
+	if err != nil || len(remaining) != 1 || remaining[0].ID != pending[1].ID {
+		t.Fatalf("expected the undelivered event to survive restart, got %+v (%v)", remaining, err)
+	}
+}
+
+func TestJournaledStoreRestoresFromSnapshotOnClose(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store := openJournaled(t, dir, 1000)
+	seedJournaledStore(t, ctx, store)
+	store.Close()
+
+	if info, err := os.Stat(filepath.Join(dir, journalFile)); err != nil || info.Size() != 0 {
+		t.Fatalf("expected close to compact the journal, got %v (%v)", info, err)
+	}
+	assertSeededState(t, ctx, openJournaled(t, dir, 1000))
+}
+
+func TestJournaledStoreTruncatesCorruptTail(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store := openJournaled(t, dir, 1000)
+	seedJournaledStore(t, ctx, store)
+	if _, err := store.AddVital(ctx, Vital{PatientID: "patient-2", Systolic: 110, Diastolic: 70, TakenAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+
+	path := filepath.Join(dir, journalFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat journal: %v", err)
+	}
+	// Tear the last record in half, then append garbage after it.
+	if err := os.Truncate(path, info.Size()-10); err != nil {
+		t.Fatalf("truncate journal: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	f.Write([]byte{0xde, 0xad, 0xbe, 0xef})
+	f.Close()
+
+	recovered := openJournaled(t, dir, 1000)
+	assertSeededState(t, ctx, recovered)
+
+	vital, err := recovered.AddVital(ctx, Vital{PatientID: "patient-3", Systolic: 115, Diastolic: 75, TakenAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("add vital after truncation: %v", err)
+	}
+	if vital.ID != 3 {
+		t.Fatalf("expected torn vital's id to be reused, got %d", vital.ID)
+	}
+
+	again := openJournaled(t, dir, 1000)
+	vitals, _ := again.ListVitals(ctx)
+	if len(vitals) != 3 || vitals[2].PatientID != "patient-3" {
+		t.Fatalf("expected writes after truncation to survive, got %+v", vitals)
+	}
+}
+
+func TestJournaledStoreCorruptChecksumStopsReplay(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store := openJournaled(t, dir, 1000)
+	if _, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	if _, err := store.AddVital(ctx, Vital{PatientID: "patient-2", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+
+	path := filepath.Join(dir, journalFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	data[len(data)-2] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+
+	recovered := openJournaled(t, dir, 1000)
+	vitals, _ := recovered.ListVitals(ctx)
+	if len(vitals) != 1 || vitals[0].PatientID != "patient-1" {
+		t.Fatalf("expected only the intact record to replay, got %+v", vitals)
+	}
+}
+
+func TestJournaledStoreRejectsCorruptionBeforeTail(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store := openJournaled(t, dir, 1000)
+	seedJournaledStore(t, ctx, store)
+
+	path := filepath.Join(dir, journalFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read journal: %v", err)
+	}
+	// Damage the first record's payload; valid records follow it.
+	data[journalHeaderSize+2] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("write journal: %v", err)
+	}
+
+	_, err = OpenJournaledStore(JournalOptions{Dir: dir, NoSync: true})
+	if !errors.Is(err, ErrJournalCorrupt) {
+		t.Fatalf("expected ErrJournalCorrupt, got %v", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(after, data) {
+		t.Fatalf("expected the corrupt journal to be left intact (%v)", err)
+	}
+}
+
+func TestJournaledStoreTruncatesZeroFilledTail(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store := openJournaled(t, dir, 1000)
+	seedJournaledStore(t, ctx, store)
+
+	f, err := os.OpenFile(filepath.Join(dir, journalFile), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+	f.Write(make([]byte, 64))
+	f.Close()
+
+	assertSeededState(t, ctx, openJournaled(t, dir, 1000))
+}
+
+func openJournaled(t *testing.T, dir string, snapshotEvery int) *InMemoryStore {
+	t.Helper()
+	store, err := OpenJournaledStore(JournalOptions{Dir: dir, SnapshotEvery: snapshotEvery, NoSync: true})
+	if err != nil {
+		t.Fatalf("open journaled store: %v", err)
+	}
+	return store
+}
+
+func seedJournaledStore(t *testing.T, ctx context.Context, store *InMemoryStore) {
+	t.Helper()
+	first, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	if _, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", VitalID: first.ID, VitalIDs: []int64{first.ID}, Status: AlertStatusActive},
+		AlertHistoryEntry{Type: AlertHistoryCreated, VitalID: first.ID})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	if err := alert.Transition(AlertStatusAutoResolved, 2, time.Now()); err != nil {
+		t.Fatalf("transition: %v", err)
+	}
+	if _, err := store.UpdateAlert(ctx, alert, statusChange(AlertHistoryStatusChanged, alert, AlertStatusActive)); err != nil {
+		t.Fatalf("update alert: %v", err)
+	}
+	if _, err := store.SetPatientThreshold(ctx, PatientThreshold{PatientID: "patient-1", Thresholds: Thresholds{MaxSystolic: 150}, SetBy: "dr-smith"}); err != nil {
+		t.Fatalf("set threshold: %v", err)
+	}
+}
+
+func assertSeededState(t *testing.T, ctx context.Context, store *InMemoryStore) {
+	t.Helper()
+	vitals, err := store.ListVitals(ctx)
+	if err != nil || len(vitals) != 2 {
+		t.Fatalf("expected 2 vitals, got %+v (%v)", vitals, err)
+	}
+	alerts, err := store.ListAlerts(ctx)
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %+v (%v)", alerts, err)
+	}
+	if alerts[0].Status != AlertStatusAutoResolved || alerts[0].StatusVitalID != 2 {
+		t.Fatalf("expected alert update to be replayed, got %+v", alerts[0])
+	}
+	history, err := store.AlertHistory(ctx, alerts[0].ID)
+	if err != nil || len(history) != 2 || history[1].Type != AlertHistoryStatusChanged || history[1].ID != 2 {
+		t.Fatalf("expected alert history to be replayed, got %+v (%v)", history, err)
+	}
+	threshold, err := store.GetPatientThreshold(ctx, "patient-1")
+	if err != nil || threshold.Thresholds.MaxSystolic != 150 {
+		t.Fatalf("expected threshold to be replayed, got %+v (%v)", threshold, err)
+	}
+}
@@ -2,17 +2,30 @@ package app
 
 import (
 	"context"
-	"math/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 )
 
+var (
+	ErrNotificationThrottled = errors.New("notification throttled by patient cooldown")
+	ErrMessageNotFound       = errors.New("message not found")
+	ErrMessageNotFailed      = errors.New("message is not dead-lettered")
+)
+
 type MessageStatus int32
 
 const (
 	MessageStatusQueued     MessageStatus = 0
 	MessageStatusProcessing MessageStatus = 1
 	MessageStatusSent       MessageStatus = 2
+	MessageStatusCancelled  MessageStatus = 3
+	// MessageStatusFailed is a dead-lettered message: every attempt to send
+	// it failed. It stays put until requeued.
+	MessageStatusFailed MessageStatus = 4
 )
 
 func (s MessageStatus) String() string {
@@ -23,98 +36,533 @@ func (s MessageStatus) String() string {
 		return "PROCESSING"
 	case MessageStatusSent:
 		return "SENT"
+	case MessageStatusCancelled:
+		return "CANCELLED"
+	case MessageStatusFailed:
+		return "FAILED"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// Recipient is who a message is for. The zero value is the patient, so
+// retake requests need not set it.
+type Recipient int32
+
+const (
+	RecipientPatient     Recipient = 0
+	RecipientOnCallNurse Recipient = 1
+	RecipientPhysician   Recipient = 2
+)
+
+var recipients = []Recipient{RecipientPatient, RecipientOnCallNurse, RecipientPhysician}
+
+func (r Recipient) String() string {
+	switch r {
+	case RecipientPatient:
+		return "PATIENT"
+	case RecipientOnCallNurse:
+		return "ON_CALL_NURSE"
+	case RecipientPhysician:
+		return "PHYSICIAN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseRecipient accepts the names String returns, case-insensitively.
+func ParseRecipient(name string) (Recipient, bool) {
+	for _, r := range recipients {
+		if strings.EqualFold(name, r.String()) {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// MessagePriority decides how soon a queued message is sent relative to
+// others. The zero value is normal.
+type MessagePriority int32
+
+const (
+	MessagePriorityNormal   MessagePriority = 0
+	MessagePriorityLow      MessagePriority = 1
+	MessagePriorityHigh     MessagePriority = 2
+	MessagePriorityCritical MessagePriority = 3
+)
+
+// messagePriorities lists the priorities highest first.
+var messagePriorities = []MessagePriority{MessagePriorityCritical, MessagePriorityHigh, MessagePriorityNormal, MessagePriorityLow}
+
+// priorityWeights is how many messages of each priority are sent, while
+// every priority has some due, for each low priority one; see popDueLocked.
+var priorityWeights = map[MessagePriority]int{
+	MessagePriorityCritical: 8,
+	MessagePriorityHigh:     4,
+	MessagePriorityNormal:   2,
+	MessagePriorityLow:      1,
+}
+
+func (p MessagePriority) String() string {
+	switch p {
+	case MessagePriorityNormal:
+		return "NORMAL"
+	case MessagePriorityLow:
+		return "LOW"
+	case MessagePriorityHigh:
+		return "HIGH"
+	case MessagePriorityCritical:
+		return "CRITICAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MessagePriorities returns every priority, highest first.
+func MessagePriorities() []MessagePriority {
+	return slices.Clone(messagePriorities)
+}
+
+// PriorityForSeverity is the priority of a message about an alert of
+// severity s.
+func PriorityForSeverity(s Severity) MessagePriority {
+	switch s {
+	case SeverityCritical:
+		return MessagePriorityCritical
+	case SeverityHigh:
+		return MessagePriorityHigh
+	case SeverityLow:
+		return MessagePriorityLow
+	default:
+		return MessagePriorityNormal
+	}
+}
+
 type Message struct {
 	ID        int64
 	PatientID string
-	Content   string
-	Status    MessageStatus
-	QueuedAt  time.Time
-	SentAt    time.Time
+	// Recipient is the patient or, for escalations, the care team member
+	// to notify about PatientID.
+	Recipient Recipient
+	AlertID   int64
+	// Subject titles Content on channels that have one, such as email.
+	Subject string
+	Content string
+	// Priority orders the message against others; critical messages are
+	// also sent during the recipient's quiet hours.
+	Priority    MessagePriority
+	Status      MessageStatus
+	QueuedAt    time.Time
+	SentAt      time.Time
+	CancelledAt time.Time
+	// ScheduledFor, if set, is when the message is due; it isn't sent
+	// before then. Enqueue sets it for a non-critical message queued during
+	// quiet hours.
+	ScheduledFor time.Time
+
+	// Attempts counts sends started since the message was queued or last
+	// requeued; LastError is why the latest one failed. A message waiting
+	// to retry is QUEUED with NextAttemptAt set.
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	FailedAt      time.Time
+
+	// Channel and ProviderMessageID record how a sent message was
+	// delivered; see Delivery.
+	Channel           string
+	ProviderMessageID string
 }
 
 type MessageListener func(Message)
 
+// MessageQueue is safe for any number of goroutines to call ProcessNext at
+// once: messages for different patients are sent concurrently, while a
+// patient's messages go out one at a time, oldest first within each
+// priority.
 type MessageQueue struct {
-	mu        sync.Mutex
-	seq       int64
-	messages  []Message
-	queue     []Message
+	mu       sync.Mutex
+	seq      int64
+	messages []Message
+	// lanes holds the queued messages of each priority, in the order
+	// they're sent; credit is each lane's standing in the weighted round
+	// robin between them. sending holds the patients with a message being
+	// sent.
+	lanes     map[MessagePriority][]Message
+	credit    map[MessagePriority]int
+	sending   map[string]bool
 	listeners []MessageListener
-	minDelay  time.Duration
-	maxDelay  time.Duration
+	sender    MessageSender
+	retry     RetryPolicy
+	cooldown  time.Duration
+	quiet     QuietHoursDirectory
+	clock     Clock
 }
 
-func NewMessageQueue(minDelay, maxDelay time.Duration) *MessageQueue {
-	return &MessageQueue{
-		minDelay: minDelay,
-		maxDelay: maxDelay,
+type MessageQueueOption func(*MessageQueue)
+
+// WithPatientCooldown rejects a new message for a patient due before d has
+// passed since their last one; see CooldownUntil. Cancelled and
+// dead-lettered messages and messages to the care team don't count, and
+// care team messages are never throttled.
+func WithPatientCooldown(d time.Duration) MessageQueueOption {
+	return func(q *MessageQueue) { q.cooldown = d }
+}
+
+func WithQueueClock(clock Clock) MessageQueueOption {
+	return func(q *MessageQueue) { q.clock = clock }
+}
+
+// WithMessageSender delivers messages through sender instead of simulating
+// delivery.
+func WithMessageSender(sender MessageSender) MessageQueueOption {
+	return func(q *MessageQueue) { q.sender = sender }
+}
+
+// WithQuietHours defers non-critical messages queued during their
+// recipient's quiet hours, as reported by quiet, until the quiet hours end.
+func WithQuietHours(quiet QuietHoursDirectory) MessageQueueOption {
+	return func(q *MessageQueue) { q.quiet = quiet }
+}
+
+func WithRetryPolicy(policy RetryPolicy) MessageQueueOption {
+	return func(q *MessageQueue) { q.retry = policy }
+}
+
+// NewMessageQueue returns a queue that, unless WithMessageSender is given,
+// simulates delivery taking between minDelay and maxDelay.
+func NewMessageQueue(minDelay, maxDelay time.Duration, opts ...MessageQueueOption) *MessageQueue {
+	q := &MessageQueue{
+		sender:  simulatedSender{minDelay: minDelay, maxDelay: maxDelay},
+		lanes:   make(map[MessagePriority][]Message),
+		credit:  make(map[MessagePriority]int),
+		sending: make(map[string]bool),
+		retry:   DefaultRetryPolicy(),
+		clock:   SystemClock(),
+	}
+	for _, opt := range opts {
+		opt(q)
 	}
+	return q
 }
 
-func (q *MessageQueue) Enqueue(patientID, content string) Message {
+// Enqueue queues msg for delivery. The caller fills in the recipient, the
+// alert it belongs to, the content, the priority and optionally when it's
+// scheduled for; the queue assigns the rest, deferring the message past
+// the recipient's quiet hours unless it's critical. It returns
+// ErrNotificationThrottled while the patient is in cooldown.
+func (q *MessageQueue) Enqueue(msg Message) (Message, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	now := q.clock.Now()
+	if msg.Recipient == RecipientPatient {
+		due := now
+		if msg.ScheduledFor.After(due) {
+			due = msg.ScheduledFor
+		}
+		if due.Before(q.cooldownUntilLocked(msg.PatientID)) {
+			return Message{}, ErrNotificationThrottled
+		}
+	}
+
 	q.seq++
-	msg := Message{
-		ID:        q.seq,
-		PatientID: patientID,
-		Content:   content,
-		Status:    MessageStatusQueued,
-		QueuedAt:  time.Now().UTC(),
+	msg.ID = q.seq
+	msg.Status = MessageStatusQueued
+	msg.QueuedAt = now
+	msg.SentAt = time.Time{}
+	msg.CancelledAt = time.Time{}
+	msg.Attempts = 0
+	msg.LastError = ""
+	msg.NextAttemptAt = time.Time{}
+	msg.FailedAt = time.Time{}
+	msg.Channel = ""
+	msg.ProviderMessageID = ""
+	if msg.Priority != MessagePriorityCritical && q.quiet != nil {
+		due := now
+		if msg.ScheduledFor.After(due) {
+			due = msg.ScheduledFor
+		}
+		if until := q.quiet.QuietHours(msg.PatientID, msg.Recipient).Until(due); !until.IsZero() {
+			msg.ScheduledFor = until
+		}
 	}
 	q.messages = append(q.messages, msg)
-	q.queue = append(q.queue, msg)
+	q.lanes[msg.Priority] = append(q.lanes[msg.Priority], msg)
 	q.notifyLocked(msg)
-	return msg
+	return msg, nil
 }
 
+// CooldownUntil reports when the patient's cooldown ends: a message for
+// them scheduled for then or later isn't throttled. It returns the zero
+// time if they have no cooldown.
+func (q *MessageQueue) CooldownUntil(patientID string) time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.cooldownUntilLocked(patientID)
+}
+
+func (q *MessageQueue) cooldownUntilLocked(patientID string) time.Time {
+	if q.cooldown <= 0 {
+		return time.Time{}
+	}
+	for i := len(q.messages) - 1; i >= 0; i-- {
+		m := q.messages[i]
+		if m.PatientID != patientID || m.Recipient != RecipientPatient ||
+			m.Status == MessageStatusCancelled || m.Status == MessageStatusFailed {
+			continue
+		}
+		// A scheduled message counts from when it's due to go out.
+		last := m.QueuedAt
+		if m.ScheduledFor.After(last) {
+			last = m.ScheduledFor
+		}
+		return last.Add(q.cooldown)
+	}
+	return time.Time{}
+}
+
+// ProcessNext sends the next queued message that is due and returns it
+// with its new status: SENT, QUEUED to retry after a failure, FAILED once
+// the retry policy's attempts are used up, or CANCELLED if its alert was
+// resolved meanwhile. It returns nil when nothing is due. If ctx ends
+// mid-send the message goes back to the front of the queue and ProcessNext
+// returns nil.
+//
+// Higher priorities go first, but in a weighted round robin rather than
+// strictly, so a steady stream of critical messages can't starve low
+// priority ones: while every priority has messages due, each low priority
+// message is sent for 2 normal, 4 high and 8 critical ones. Within a
+// priority messages are sent oldest first.
+//
+// A message isn't due while another about the same patient is being sent,
+// to whichever recipient, or while an earlier one of the same priority is
+// waiting to retry, so concurrent callers keep each patient's messages in
+// order. An earlier message scheduled for later, such as one deferred by
+// quiet hours, doesn't hold back critical ones.
 func (q *MessageQueue) ProcessNext(ctx context.Context) *Message {
 	q.mu.Lock()
-	if len(q.queue) == 0 {
+	msg, ok := q.popDueLocked(q.clock.Now())
+	if !ok {
 		q.mu.Unlock()
 		return nil
 	}
-	msg := q.queue[0]
-	q.queue = q.queue[1:]
+	queued := msg
+	msg.Attempts++
+	msg.NextAttemptAt = time.Time{}
+	msg = q.setStatusLocked(msg, MessageStatusProcessing)
+	q.sending[msg.PatientID] = true
 	q.mu.Unlock()
 
-	// Mark as processing
-	msg = q.updateMessage(msg, MessageStatusProcessing, false)
 	q.notify(msg)
 
-	// Simulate delay
-	delay := q.minDelay + time.Duration(rand.Float64()*float64(q.maxDelay-q.minDelay))
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-	deadline := time.Now().Add(delay)
-
-	for time.Now().Before(deadline) {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			continue
+	delivery, err := q.sender.Send(ctx, msg)
+
+	// The alert may have been resolved while we were delivering; check
+	// under the same lock that records the outcome so a cancel can't slip
+	// in between.
+	q.mu.Lock()
+	delete(q.sending, msg.PatientID)
+	if current, ok := q.findLocked(msg.ID); ok && current.Status == MessageStatusCancelled {
+		q.mu.Unlock()
+		return &current
+	}
+	switch {
+	case err == nil:
+		msg.Channel = delivery.Channel
+		msg.ProviderMessageID = delivery.ProviderMessageID
+		msg = q.setStatusLocked(msg, MessageStatusSent)
+	case ctx.Err() != nil:
+		// Shutting down, not a delivery failure: the attempt doesn't
+		// count, and the next ProcessNext picks the message up again.
+		msg.Attempts, msg.LastError = queued.Attempts, queued.LastError
+		msg = q.setStatusLocked(msg, MessageStatusQueued)
+		q.lanes[msg.Priority] = append([]Message{msg}, q.lanes[msg.Priority]...)
+		q.mu.Unlock()
+		q.notify(msg)
+		return nil
+	default:
+		msg.LastError = err.Error()
+		if q.retry.MaxAttempts > 0 && msg.Attempts >= q.retry.MaxAttempts {
+			msg = q.setStatusLocked(msg, MessageStatusFailed)
+			break
 		}
+		msg.NextAttemptAt = q.clock.Now().Add(q.retry.backoff(msg.Attempts))
+		msg = q.setStatusLocked(msg, MessageStatusQueued)
+		q.lanes[msg.Priority] = append(q.lanes[msg.Priority], msg)
 	}
+	q.mu.Unlock()
 
-	// Mark as sent
-	msg = q.updateMessage(msg, MessageStatusSent, true)
 	q.notify(msg)
 	return &msg
 }
 
-func (q *MessageQueue) updateMessage(msg Message, status MessageStatus, sent bool) Message {
+// popDueLocked picks a lane by smooth weighted round robin among the
+// lanes with a message due, and removes and returns that message.
+func (q *MessageQueue) popDueLocked(now time.Time) (Message, bool) {
+	due := make(map[MessagePriority]int)
+	total := 0
+	for _, p := range messagePriorities {
+		if i, ok := q.dueInLaneLocked(p, now); ok {
+			due[p] = i
+			total += priorityWeights[p]
+		}
+	}
+	if len(due) == 0 {
+		return Message{}, false
+	}
+	// Every lane with work earns its weight; the richest is served and pays
+	// for the round. Lanes without work don't bank credit meanwhile.
+	picked := MessagePriority(-1)
+	for _, p := range messagePriorities {
+		if _, ok := due[p]; !ok {
+			q.credit[p] = 0
+			continue
+		}
+		q.credit[p] += priorityWeights[p]
+		if picked < 0 || q.credit[p] > q.credit[picked] {
+			picked = p
+		}
+	}
+	q.credit[picked] -= total
+
+	lane, i := q.lanes[picked], due[picked]
+	msg := lane[i]
+	q.lanes[picked] = append(lane[:i:i], lane[i+1:]...)
+	return msg, true
+}
+
+// dueInLaneLocked finds the first message in lane p that is the oldest
+// unscheduled one for its patient in the lane, isn't waiting out a retry
+// backoff, and whose patient has nothing being sent.
+func (q *MessageQueue) dueInLaneLocked(p MessagePriority, now time.Time) (int, bool) {
+	lane := q.lanes[p]
+	oldest := make(map[string]int64)
+	for _, m := range lane {
+		if m.ScheduledFor.After(now) {
+			continue
+		}
+		if id, ok := oldest[m.PatientID]; !ok || m.ID < id {
+			oldest[m.PatientID] = m.ID
+		}
+	}
+	for i, m := range lane {
+		if m.ScheduledFor.After(now) || m.NextAttemptAt.After(now) ||
+			oldest[m.PatientID] != m.ID || q.sending[m.PatientID] {
+			continue
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// Depth returns how many messages of each priority are queued, including
+// those scheduled for later or waiting to retry.
+func (q *MessageQueue) Depth() map[MessagePriority]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	depth := make(map[MessagePriority]int, len(messagePriorities))
+	for _, p := range messagePriorities {
+		depth[p] = len(q.lanes[p])
+	}
+	return depth
+}
+
+// DeadLetters returns the messages that failed every attempt, oldest first.
+func (q *MessageQueue) DeadLetters() []Message {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	if sent {
-		msg.SentAt = time.Now().UTC()
+	var result []Message
+	for _, m := range q.messages {
+		if m.Status == MessageStatusFailed {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// Requeue puts a dead-lettered message back in the queue with a fresh set
+// of attempts, as if it had just been queued. It returns ErrMessageNotFound
+// for an unknown id and ErrMessageNotFailed for a message that isn't
+// dead-lettered.
+func (q *MessageQueue) Requeue(id int64) (Message, error) {
+	q.mu.Lock()
+	msg, ok := q.findLocked(id)
+	if !ok {
+		q.mu.Unlock()
+		return Message{}, fmt.Errorf("%w: %d", ErrMessageNotFound, id)
+	}
+	if msg.Status != MessageStatusFailed {
+		q.mu.Unlock()
+		return Message{}, fmt.Errorf("%w: message %d is %s", ErrMessageNotFailed, id, msg.Status)
+	}
+	msg.Attempts = 0
+	msg.QueuedAt = q.clock.Now()
+	msg.FailedAt = time.Time{}
+	msg = q.setStatusLocked(msg, MessageStatusQueued)
+	q.lanes[msg.Priority] = append(q.lanes[msg.Priority], msg)
+	q.mu.Unlock()
+
+	q.notify(msg)
+	return msg, nil
+}
+
+// Cancel cancels one queued or in-flight message. It reports false if the
+// message is unknown or already finished.
+func (q *MessageQueue) Cancel(id int64) (Message, bool) {
+	q.mu.Lock()
+	msg, ok := q.findLocked(id)
+	if !ok || (msg.Status != MessageStatusQueued && msg.Status != MessageStatusProcessing) {
+		q.mu.Unlock()
+		return Message{}, false
+	}
+	msg = q.setStatusLocked(msg, MessageStatusCancelled)
+	q.lanes[msg.Priority] = slices.DeleteFunc(q.lanes[msg.Priority], func(m Message) bool { return m.ID == id })
+	q.mu.Unlock()
+
+	q.notify(msg)
+	return msg, true
+}
+
+// CancelByAlert cancels every queued, in-flight or dead-lettered message for
+// alertID, so none can be sent or requeued, and returns the messages it
+// cancelled. Messages that were already sent are left alone.
+func (q *MessageQueue) CancelByAlert(alertID int64) []Message {
+	q.mu.Lock()
+	var cancelled []Message
+	for _, m := range q.messages {
+		if m.AlertID != alertID {
+			continue
+		}
+		if m.Status != MessageStatusQueued && m.Status != MessageStatusProcessing && m.Status != MessageStatusFailed {
+			continue
+		}
+		cancelled = append(cancelled, q.setStatusLocked(m, MessageStatusCancelled))
+	}
+	if len(cancelled) > 0 {
+		for p, lane := range q.lanes {
+			q.lanes[p] = slices.DeleteFunc(lane, func(m Message) bool { return m.AlertID == alertID })
+		}
+	}
+	q.mu.Unlock()
+
+	for _, m := range cancelled {
+		q.notify(m)
+	}
+	return cancelled
+}
+
+func (q *MessageQueue) setStatusLocked(msg Message, status MessageStatus) Message {
+	switch status {
+	case MessageStatusSent:
+		msg.SentAt = q.clock.Now()
+	case MessageStatusCancelled:
+		msg.CancelledAt = q.clock.Now()
+		msg.NextAttemptAt = time.Time{}
+	case MessageStatusFailed:
+		msg.FailedAt = q.clock.Now()
 	}
 	msg.Status = status
 
@@ -127,6 +575,21 @@ func (q *MessageQueue) updateMessage(msg Message, status MessageStatus, sent boo
 	return msg
 }
 
+func (q *MessageQueue) findLocked(id int64) (Message, bool) {
+	for _, m := range q.messages {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return Message{}, false
+}
+
+func (q *MessageQueue) GetMessage(id int64) (Message, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.findLocked(id)
+}
+
 func (q *MessageQueue) ListMessages() []Message {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -0,0 +1,294 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMessageQueueCancelByAlertRemovesQueuedMessages(t *testing.T) {
+	queue := NewMessageQueue(0, 0)
+	queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1, Content: "first"})
+	queue.Enqueue(Message{PatientID: "patient-2", AlertID: 2, Content: "second"})
+
+	cancelled := queue.CancelByAlert(1)
+	if len(cancelled) != 1 || cancelled[0].AlertID != 1 {
+		t.Fatalf("unexpected cancelled messages: %+v", cancelled)
+	}
+	if cancelled[0].Status != MessageStatusCancelled || cancelled[0].CancelledAt.IsZero() {
+		t.Fatalf("expected cancelled status with timestamp, got %+v", cancelled[0])
+	}
+
+	msg := queue.ProcessNext(context.Background())
+	if msg == nil || msg.AlertID != 2 || msg.Status != MessageStatusSent {
+		t.Fatalf("expected only alert 2 message to be delivered, got %+v", msg)
+	}
+	if msg := queue.ProcessNext(context.Background()); msg != nil {
+		t.Fatalf("expected empty queue, got %+v", msg)
+	}
+}
+
+func TestMessageQueueCancelsInFlightMessageBeforeSent(t *testing.T) {
+	queue := NewMessageQueue(200*time.Millisecond, 200*time.Millisecond)
+	queued, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 7, Content: "retake"})
+	if err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+
+	done := make(chan *Message, 1)
+	go func() { done <- queue.ProcessNext(context.Background()) }()
+
+	waitFor(t, 500*time.Millisecond, func() bool {
+		msg, ok := queue.GetMessage(queued.ID)
+		return ok && msg.Status == MessageStatusProcessing
+	})
+	if cancelled := queue.CancelByAlert(7); len(cancelled) != 1 {
+		t.Fatalf("expected in-flight message to be cancelled, got %+v", cancelled)
+	}
+
+	select {
+	case msg := <-done:
+		if msg == nil || msg.Status != MessageStatusCancelled {
+			t.Fatalf("expected cancelled message, got %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ProcessNext")
+	}
+
+	stored, _ := queue.GetMessage(queued.ID)
+	if stored.Status != MessageStatusCancelled || !stored.SentAt.IsZero() {
+		t.Fatalf("expected message to stay cancelled, got %+v", stored)
+	}
+}
+
+func TestMessageQueueCancelByAlertIgnoresSentMessages(t *testing.T) {
+	queue := NewMessageQueue(0, 0)
+	queue.Enqueue(Message{PatientID: "patient-1", AlertID: 3, Content: "retake"})
+	queue.ProcessNext(context.Background())
+
+	if cancelled := queue.CancelByAlert(3); len(cancelled) != 0 {
+		t.Fatalf("expected sent message to be left alone, got %+v", cancelled)
+	}
+}
+
+func TestMessageQueueCancelByAlertCancelsDeadLetters(t *testing.T) {
+	sender := MessageSenderFunc(func(ctx context.Context, msg Message) (Delivery, error) {
+		return Delivery{}, errors.New("gateway unavailable")
+	})
+	queue := NewMessageQueue(0, 0, WithMessageSender(sender), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+	queued, _ := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 4, Content: "retake"})
+	if msg := queue.ProcessNext(context.Background()); msg == nil || msg.Status != MessageStatusFailed {
+		t.Fatalf("expected the message to be dead-lettered, got %+v", msg)
+	}
+
+	if cancelled := queue.CancelByAlert(4); len(cancelled) != 1 || cancelled[0].ID != queued.ID {
+		t.Fatalf("expected the dead letter to be cancelled, got %+v", cancelled)
+	}
+	if dead := queue.DeadLetters(); len(dead) != 0 {
+		t.Fatalf("expected no dead letters left, got %+v", dead)
+	}
+	if _, err := queue.Requeue(queued.ID); !errors.Is(err, ErrMessageNotFailed) {
+		t.Fatalf("expected a cancelled message not to be requeued, got %v", err)
+	}
+}
+
+func TestMessageQueueEnforcesPatientCooldown(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	queue := NewMessageQueue(0, 0, WithPatientCooldown(10*time.Minute), WithQueueClock(clock))
+
+	if _, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1}); err != nil {
+		t.Fatalf("first enqueue failed: %v", err)
+	}
+	clock.Advance(5 * time.Minute)
+	if _, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 2}); !errors.Is(err, ErrNotificationThrottled) {
+		t.Fatalf("expected throttled error inside cooldown, got %v", err)
+	}
+	if _, err := queue.Enqueue(Message{PatientID: "patient-2", AlertID: 3}); err != nil {
+		t.Fatalf("cooldown should be per patient, got %v", err)
+	}
+	until := queue.CooldownUntil("patient-1")
+	if !until.Equal(clock.Now().Add(5 * time.Minute)) {
+		t.Fatalf("unexpected cooldown end: %s", until)
+	}
+	if _, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 2, ScheduledFor: until}); err != nil {
+		t.Fatalf("expected a message scheduled for the end of the cooldown to be accepted, got %v", err)
+	}
+
+	// The scheduled message counts from when it's due.
+	clock.Advance(15 * time.Minute)
+	if _, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 4}); err != nil {
+		t.Fatalf("expected enqueue after cooldown, got %v", err)
+	}
+}
+
+func TestMessageQueueCooldownIgnoresCancelledMessages(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	queue := NewMessageQueue(0, 0, WithPatientCooldown(10*time.Minute), WithQueueClock(clock))
+
+	if _, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1}); err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+	queue.CancelByAlert(1)
+	if _, err := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 2}); err != nil {
+		t.Fatalf("cancelled message should not start a cooldown, got %v", err)
+	}
+}
+
+func TestMessageQueueRetriesWithBackoffThenDeadLetters(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	var failing atomic.Bool
+	failing.Store(true)
+	sender := MessageSenderFunc(func(ctx context.Context, msg Message) (Delivery, error) {
+		if failing.Load() {
+			return Delivery{}, errors.New("gateway unavailable")
+		}
+		return Delivery{Channel: "sms", ProviderMessageID: "SM1"}, nil
+	})
+	queue := NewMessageQueue(0, 0, WithQueueClock(clock), WithMessageSender(sender),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: 10 * time.Minute}))
+	queued, _ := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1, Content: "retake"})
+	ctx := context.Background()
+
+	msg := queue.ProcessNext(ctx)
+	if msg == nil || msg.Status != MessageStatusQueued || msg.Attempts != 1 || msg.LastError != "gateway unavailable" ||
+		!msg.NextAttemptAt.Equal(clock.Now().Add(time.Minute)) {
+		t.Fatalf("expected a retry in a minute, got %+v", msg)
+	}
+	if msg := queue.ProcessNext(ctx); msg != nil {
+		t.Fatalf("expected nothing due during backoff, got %+v", msg)
+	}
+	clock.Advance(time.Minute)
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.Attempts != 2 || !msg.NextAttemptAt.Equal(clock.Now().Add(2*time.Minute)) {
+		t.Fatalf("expected the backoff to double, got %+v", msg)
+	}
+	clock.Advance(2 * time.Minute)
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.Status != MessageStatusFailed || msg.Attempts != 3 || msg.FailedAt.IsZero() {
+		t.Fatalf("expected the message to be dead-lettered, got %+v", msg)
+	}
+	clock.Advance(time.Hour)
+	if msg := queue.ProcessNext(ctx); msg != nil {
+		t.Fatalf("expected dead letters to stay out of the queue, got %+v", msg)
+	}
+	if dead := queue.DeadLetters(); len(dead) != 1 || dead[0].ID != queued.ID {
+		t.Fatalf("unexpected dead letters: %+v", dead)
+	}
+
+	failing.Store(false)
+	requeued, err := queue.Requeue(queued.ID)
+	if err != nil || requeued.Status != MessageStatusQueued || requeued.Attempts != 0 {
+		t.Fatalf("expected requeued message, got %+v (%v)", requeued, err)
+	}
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.Status != MessageStatusSent || msg.Attempts != 1 ||
+		msg.Channel != "sms" || msg.ProviderMessageID != "SM1" {
+		t.Fatalf("expected requeued message to be sent over sms, got %+v", msg)
+	}
+	if _, err := queue.Requeue(queued.ID); !errors.Is(err, ErrMessageNotFailed) {
+		t.Fatalf("expected sent message not to be requeued, got %v", err)
+	}
+	if _, err := queue.Requeue(99); !errors.Is(err, ErrMessageNotFound) {
+		t.Fatalf("expected unknown message error, got %v", err)
+	}
+}
+
+func TestMessageQueueKeepsMessageWhenSendIsInterrupted(t *testing.T) {
+	started := make(chan struct{})
+	var interrupted atomic.Bool
+	queue := NewMessageQueue(0, 0, WithMessageSender(MessageSenderFunc(func(ctx context.Context, msg Message) (Delivery, error) {
+		if interrupted.CompareAndSwap(false, true) {
+			close(started)
+			<-ctx.Done()
+			return Delivery{}, ctx.Err()
+		}
+		return Delivery{}, nil
+	})))
+	queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1, Content: "first"})
+	queue.Enqueue(Message{PatientID: "patient-2", AlertID: 2, Content: "second"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan *Message, 1)
+	go func() { done <- queue.ProcessNext(ctx) }()
+	<-started
+	cancel()
+	select {
+	case msg := <-done:
+		if msg != nil {
+			t.Fatalf("expected no result for an interrupted send, got %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for ProcessNext")
+	}
+
+	if msg, _ := queue.GetMessage(1); msg.Status != MessageStatusQueued || msg.Attempts != 0 || msg.LastError != "" {
+		t.Fatalf("expected the interrupted send not to count as an attempt, got %+v", msg)
+	}
+	msg := queue.ProcessNext(context.Background())
+	if msg == nil || msg.AlertID != 1 || msg.Status != MessageStatusSent || msg.Attempts != 1 {
+		t.Fatalf("expected the interrupted message to be sent first, got %+v", msg)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 50: 10 * time.Second} {
+		if got := policy.backoff(attempts); got != want {
+			t.Fatalf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for range 100 {
+		if got := policy.backoff(2); got < time.Second || got > 2*time.Second {
+			t.Fatalf("jittered backoff %v outside [1s, 2s]", got)
+		}
+	}
+}
+
+func TestMessageQueueServesPrioritiesByWeight(t *testing.T) {
+	queue := NewMessageQueue(0, 0)
+	counts := map[MessagePriority]int{MessagePriorityCritical: 16, MessagePriorityHigh: 8, MessagePriorityNormal: 4, MessagePriorityLow: 2}
+	// Queue the low priority messages first so plain FIFO would send them
+	// first, and critical ones last.
+	for _, priority := range []MessagePriority{MessagePriorityLow, MessagePriorityNormal, MessagePriorityHigh, MessagePriorityCritical} {
+		for i := range counts[priority] {
+			queue.Enqueue(Message{PatientID: fmt.Sprintf("%s-%d", priority, i), Priority: priority, Content: "retake"})
+		}
+	}
+	if got := queue.Depth(); got[MessagePriorityCritical] != 16 || got[MessagePriorityLow] != 2 {
+		t.Fatalf("unexpected depth: %v", got)
+	}
+
+	sent := make(map[MessagePriority]int)
+	for i := range 15 {
+		msg := queue.ProcessNext(context.Background())
+		if i == 0 && msg.Priority != MessagePriorityCritical {
+			t.Fatalf("expected a critical message first, got %s", msg.Priority)
+		}
+		sent[msg.Priority]++
+	}
+	// One round of the 8:4:2:1 weighting: low priority still gets a turn.
+	for priority, want := range priorityWeights {
+		if sent[priority] != want {
+			t.Fatalf("expected %d %s messages in the first 15, got %v", want, priority, sent)
+		}
+	}
+	if got := queue.Depth(); got[MessagePriorityCritical] != 8 || got[MessagePriorityHigh] != 4 || got[MessagePriorityNormal] != 2 || got[MessagePriorityLow] != 1 {
+		t.Fatalf("unexpected depth after one round: %v", got)
+	}
+}
+
+func TestPriorityForSeverity(t *testing.T) {
+	for severity, want := range map[Severity]MessagePriority{
+		SeverityCritical:    MessagePriorityCritical,
+		SeverityHigh:        MessagePriorityHigh,
+		SeverityModerate:    MessagePriorityNormal,
+		SeverityLow:         MessagePriorityLow,
+		SeverityUnspecified: MessagePriorityNormal,
+	} {
+		if got := PriorityForSeverity(severity); got != want {
+			t.Errorf("PriorityForSeverity(%s) = %s, want %s", severity, got, want)
+		}
+	}
+}
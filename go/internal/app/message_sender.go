@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Delivery is how a sent message reached its recipient.
+type Delivery struct {
+	// Channel names the channel used, such as "sms" or "email".
+	Channel string
+	// ProviderMessageID is the channel provider's ID for the message, for
+	// tracing it on their side.
+	ProviderMessageID string
+}
+
+// MessageSender delivers a message. An error means it wasn't delivered and
+// the queue may retry it; a sender should return ctx.Err() if ctx ends
+// before delivery completes.
+type MessageSender interface {
+	Send(ctx context.Context, msg Message) (Delivery, error)
+}
+
+// MessageSenderFunc adapts a function to MessageSender.
+type MessageSenderFunc func(ctx context.Context, msg Message) (Delivery, error)
+
+func (f MessageSenderFunc) Send(ctx context.Context, msg Message) (Delivery, error) {
+	return f(ctx, msg)
+}
+
+// simulatedSender stands in for a real delivery channel: it takes a random
+// time between minDelay and maxDelay and never fails.
+type simulatedSender struct {
+	minDelay time.Duration
+	maxDelay time.Duration
+}
+
+func (s simulatedSender) Send(ctx context.Context, _ Message) (Delivery, error) {
+	delivery := Delivery{Channel: "simulated"}
+	delay := s.minDelay + time.Duration(rand.Float64()*float64(s.maxDelay-s.minDelay))
+	if delay <= 0 {
+		return delivery, nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return Delivery{}, ctx.Err()
+	case <-timer.C:
+		return delivery, nil
+	}
+}
+
+// RetryPolicy decides how often the queue retries a message that failed to
+// send before dead-lettering it.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 1 never retries.
+	MaxAttempts int
+	// The wait before the nth retry is InitialBackoff doubled n-1 times,
+	// capped at MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Jitter is the fraction, from 0 to 1, of each wait that is randomized
+	// so failed messages don't all retry at once.
+	Jitter float64
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 5 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Jitter:         0.2,
+	}
+}
+
+// backoff is the wait after a message's attempts-th failed attempt.
+func (p RetryPolicy) backoff(attempts int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempts && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
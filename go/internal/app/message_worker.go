@@ -3,18 +3,49 @@ package app
 import (
 	"context"
 	"log"
+	"sync"
 	"time"
 )
 
 type MessageWorker struct {
-	queue *MessageQueue
+	queue   *MessageQueue
+	workers int
 }
 
-func NewMessageWorker(queue *MessageQueue) *MessageWorker {
-	return &MessageWorker{queue: queue}
+type MessageWorkerOption func(*MessageWorker)
+
+// WithMessageWorkers sets how many messages are sent at once. The queue
+// still sends each patient's messages one at a time, in order.
+func WithMessageWorkers(n int) MessageWorkerOption {
+	return func(w *MessageWorker) { w.workers = n }
+}
+
+func NewMessageWorker(queue *MessageQueue, opts ...MessageWorkerOption) *MessageWorker {
+	w := &MessageWorker{queue: queue, workers: 1}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.workers < 1 {
+		w.workers = 1
+	}
+	return w
 }
 
+// Run sends messages from the queue until ctx ends, then waits for sends
+// in progress to stop.
 func (w *MessageWorker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range w.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *MessageWorker) work(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -30,9 +61,25 @@ func (w *MessageWorker) Run(ctx context.Context) {
 					continue
 				}
 			}
-			if msg != nil {
-				log.Printf("[Message] Sent to %s: %s", msg.PatientID, msg.Content)
+			if msg == nil {
+				continue
+			}
+			switch msg.Status {
+			case MessageStatusCancelled:
+				log.Printf("[Message] Cancelled for %s (alert %d): %s", msg.PatientID, msg.AlertID, msg.Content)
+				continue
+			case MessageStatusQueued:
+				log.Printf("[Message] Attempt %d for %s failed, retrying at %s: %s", msg.Attempts, msg.PatientID, msg.NextAttemptAt.Format(time.RFC3339), msg.LastError)
+				continue
+			case MessageStatusFailed:
+				log.Printf("[Message] Dead-lettered message %d for %s after %d attempts: %s", msg.ID, msg.PatientID, msg.Attempts, msg.LastError)
+				continue
+			}
+			if msg.Recipient != RecipientPatient {
+				log.Printf("[Message] Sent to %s about %s via %s: %s", msg.Recipient, msg.PatientID, msg.Channel, msg.Content)
+				continue
 			}
+			log.Printf("[Message] Sent to %s via %s: %s", msg.PatientID, msg.Channel, msg.Content)
 		}
 	}
 }
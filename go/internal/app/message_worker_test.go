@@ -0,0 +1,164 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMessageWorkerPoolScalesThroughput(t *testing.T) {
+	const patients = 16
+	for _, workers := range []int{1, 8} {
+		// Each send blocks until released, so the number in flight at once
+		// is exactly the number of workers.
+		started := make(chan string, patients)
+		release := make(chan struct{})
+		sender := MessageSenderFunc(func(ctx context.Context, msg Message) (Delivery, error) {
+			started <- msg.PatientID
+			select {
+			case <-release:
+				return Delivery{Channel: "test"}, nil
+			case <-ctx.Done():
+				return Delivery{}, ctx.Err()
+			}
+		})
+		queue := NewMessageQueue(0, 0, WithMessageSender(sender))
+		for i := range patients {
+			queue.Enqueue(Message{PatientID: fmt.Sprintf("patient-%d", i), AlertID: int64(i + 1), Content: "retake"})
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			NewMessageWorker(queue, WithMessageWorkers(workers)).Run(ctx)
+			close(done)
+		}()
+		for i := range workers {
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%d workers: only %d sends started", workers, i)
+			}
+		}
+		// Every worker is now blocked in a send, so no other can start.
+		select {
+		case patient := <-started:
+			t.Fatalf("%d workers: a send for %s started while all were busy", workers, patient)
+		default:
+		}
+		if got := countStatus(queue, MessageStatusProcessing); got != workers {
+			t.Fatalf("%d workers: expected %d messages being sent, got %d", workers, workers, got)
+		}
+
+		close(release)
+		waitFor(t, 5*time.Second, func() bool { return countStatus(queue, MessageStatusSent) == patients })
+		cancel()
+		<-done
+	}
+}
+
+func TestMessageQueueSendsOneMessagePerPatientAtOnce(t *testing.T) {
+	started := make(chan Message, 1)
+	release := make(chan struct{})
+	sender := MessageSenderFunc(func(ctx context.Context, msg Message) (Delivery, error) {
+		if msg.ID == 1 {
+			started <- msg
+			<-release
+		}
+		return Delivery{Channel: "test"}, nil
+	})
+	queue := NewMessageQueue(0, 0, WithMessageSender(sender))
+	ctx := context.Background()
+	queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1, Content: "retake"})
+	queue.Enqueue(Message{PatientID: "patient-1", Recipient: RecipientOnCallNurse, AlertID: 1, Content: "escalation"})
+	queue.Enqueue(Message{PatientID: "patient-2", AlertID: 2, Content: "retake"})
+
+	go queue.ProcessNext(ctx)
+	<-started
+	// The care team's message about patient-1 waits for the patient's.
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.PatientID != "patient-2" {
+		t.Fatalf("expected patient-2's message sent alongside, got %+v", msg)
+	}
+	if msg := queue.ProcessNext(ctx); msg != nil {
+		t.Fatalf("expected nothing due while patient-1 has a send in flight, got %+v", msg)
+	}
+	close(release)
+	waitFor(t, 5*time.Second, func() bool { return countStatus(queue, MessageStatusSent) == 2 })
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.Recipient != RecipientOnCallNurse || msg.Status != MessageStatusSent {
+		t.Fatalf("expected the care team message sent next, got %+v", msg)
+	}
+}
+
+func TestMessageWorkerPoolKeepsEachPatientInOrder(t *testing.T) {
+	const patients, perPatient = 10, 30
+	var (
+		mu        sync.Mutex
+		delivered = make(map[string][]string)
+		sending   = make(map[string]bool)
+		overlaps  int
+	)
+	sender := MessageSenderFunc(func(ctx context.Context, msg Message) (Delivery, error) {
+		mu.Lock()
+		if sending[msg.PatientID] {
+			overlaps++
+		}
+		sending[msg.PatientID] = true
+		mu.Unlock()
+
+		time.Sleep(time.Duration(rand.Intn(500)) * time.Microsecond)
+
+		mu.Lock()
+		defer mu.Unlock()
+		sending[msg.PatientID] = false
+		// Failures go back through the retry path, which must not let later
+		// messages overtake.
+		if rand.Intn(10) == 0 {
+			return Delivery{}, errors.New("flaky gateway")
+		}
+		delivered[msg.PatientID] = append(delivered[msg.PatientID], msg.Content)
+		return Delivery{Channel: "test"}, nil
+	})
+	queue := NewMessageQueue(0, 0, WithMessageSender(sender), WithRetryPolicy(RetryPolicy{MaxAttempts: 100}))
+	for n := range perPatient {
+		for p := range patients {
+			queue.Enqueue(Message{PatientID: fmt.Sprintf("patient-%d", p), AlertID: int64(n*patients + p + 1), Content: fmt.Sprint(n)})
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewMessageWorker(queue, WithMessageWorkers(8)).Run(ctx)
+		close(done)
+	}()
+	waitFor(t, 10*time.Second, func() bool { return countStatus(queue, MessageStatusSent) == patients*perPatient })
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if overlaps > 0 {
+		t.Fatalf("a patient's messages were sent concurrently %d times", overlaps)
+	}
+	for patient, contents := range delivered {
+		for i, content := range contents {
+			if content != fmt.Sprint(i) {
+				t.Fatalf("%s got messages out of order: %v", patient, contents)
+			}
+		}
+	}
+}
+
+func countStatus(queue *MessageQueue, status MessageStatus) int {
+	n := 0
+	for _, m := range queue.ListMessages() {
+		if m.Status == status {
+			n++
+		}
+	}
+	return n
+}
@@ -1,10 +1,8 @@
 package app
 
-import (
-	"fmt"
-	"time"
-)
+import "time"
 
+// Default hypertensive crisis limits used by DefaultRuleSet.
 const (
 	MaxSystolic  = 180
 	MaxDiastolic = 120
@@ -12,15 +10,25 @@ const (
 
 type EventType string
 
-const EventTypeVitalReceived EventType = "VITAL_RECEIVED"
+const (
+	EventTypeVitalReceived      EventType = "VITAL_RECEIVED"
+	EventTypeAlertCreated       EventType = "ALERT_CREATED"
+	EventTypeAlertStatusChanged EventType = "ALERT_STATUS_CHANGED"
+)
 
+// Vital is one reading. Blood pressure readings use Systolic and
+// Diastolic; every other kind uses Value, always in Kind.Unit().
 type Vital struct {
 	ID         int64
 	PatientID  string
+	Kind       VitalKind
 	Systolic   int32
 	Diastolic  int32
+	Value      float64
 	TakenAt    time.Time
 	ReceivedAt time.Time
+	// Flags marks a possible but implausible reading; see QualityPolicy.
+	Flags []QualityFlag
 }
 
 type AlertStatus int32
@@ -30,30 +38,75 @@ const (
 	AlertStatusAutoResolved      AlertStatus = 1
 	AlertStatusResolvedByRetake  AlertStatus = 2
 	AlertStatusConfirmedAbnormal AlertStatus = 3
+	// AlertStatusAcknowledged is an alert a clinician has seen; it stays
+	// open, so retakes still settle it.
+	AlertStatusAcknowledged AlertStatus = 4
+	// AlertStatusResolved is an alert a clinician closed by hand.
+	AlertStatusResolved AlertStatus = 5
 )
 
 type Alert struct {
 	ID         int64
 	VitalID    int64
 	PatientID  string
+	Kind       VitalKind
 	Systolic   int32
 	Diastolic  int32
+	Value      float64
 	TakenAt    time.Time
 	ReceivedAt time.Time
 	Reason     string
+	Severity   Severity
 	Status     AlertStatus
 	Created    time.Time
+	MessageID  int64
+
+	VitalIDs     []int64
+	ReadingCount int
+	LastSeenAt   time.Time
+
+	StatusChangedAt time.Time
+	StatusVitalID   int64
+
+	// ThresholdVersion is the patient override version that was in effect
+	// when the alert fired; zero means the defaults applied.
+	ThresholdVersion int64
+
+	// Care team actions; At is zero until the action is taken.
+	Acknowledgement  AlertAction
+	Resolution       AlertAction
+	ResolutionReason ResolutionReason
+
+	// Escalations are the care team notifications sent while the alert
+	// went unanswered, oldest first; see Escalator.
+	Escalations []EscalationStep
+
+	// Revision is bumped by every UpdateAlert and guards against lost
+	// updates; see Store.UpdateAlert.
+	Revision int64
+}
+
+// AlertAction records a clinician acting on an alert.
+type AlertAction struct {
+	By   string
+	At   time.Time
+	Note string
 }
 
+// Event carries a Vital for VITAL_RECEIVED and an Alert for the alert
+// types. Seq is assigned by PubSub on publish.
 type Event struct {
 	Type  EventType
 	Vital Vital
+	Alert Alert
+	// PreviousStatus is the alert's status before an ALERT_STATUS_CHANGED.
+	PreviousStatus AlertStatus
+	Seq            int64
 }
 
-func IsAbnormal(vital Vital) bool {
-	return vital.Systolic > MaxSystolic || vital.Diastolic > MaxDiastolic
-}
-
-func AlertReason(vital Vital) string {
-	return fmt.Sprintf("abnormal blood pressure %d/%d", vital.Systolic, vital.Diastolic)
+func (e Event) PatientID() string {
+	if e.Type == EventTypeVitalReceived {
+		return e.Vital.PatientID
+	}
+	return e.Alert.PatientID
 }
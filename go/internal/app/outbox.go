@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	defaultRelayInterval       = time.Second
+	defaultRelayBatch          = 100
+	defaultRelayPublishTimeout = 5 * time.Second
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// OutboxEvent is an event the store committed together with the write that
+// caused it. It stays pending until the relay has published it.
+type OutboxEvent struct {
+	ID        int64
+	Event     Event
+	CreatedAt time.Time
+}
+
+// OutboxRelay publishes pending outbox events in commit order and marks them
+// delivered. Delivery is at-least-once: an event whose publish succeeded but
+// whose mark failed is published again on the next pass.
+type OutboxRelay struct {
+	store          Store
+	pub            Publisher
+	interval       time.Duration
+	batch          int
+	publishTimeout time.Duration
+	wake           chan struct{}
+}
+
+type OutboxRelayOption func(*OutboxRelay)
+
+// WithRelayInterval sets how often the relay polls for pending events when
+// it hasn't been notified.
+func WithRelayInterval(d time.Duration) OutboxRelayOption {
+	return func(r *OutboxRelay) { r.interval = d }
+}
+
+func NewOutboxRelay(store Store, pub Publisher, opts ...OutboxRelayOption) *OutboxRelay {
+	r := &OutboxRelay{
+		store:          store,
+		pub:            pub,
+		interval:       defaultRelayInterval,
+		batch:          defaultRelayBatch,
+		publishTimeout: defaultRelayPublishTimeout,
+		wake:           make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Notify wakes the relay without waiting for its next poll. It never blocks.
+func (r *OutboxRelay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run drains events left over from before a restart, then keeps draining
+// whenever notified or the poll interval elapses.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Drain(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("outbox relay: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain publishes pending events until none are left. It stops at the first
+// failure so events are never published out of order, and reports how many
+// were delivered.
+func (r *OutboxRelay) Drain(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		pending, err := r.store.ListPendingEvents(ctx, r.batch)
+		if err != nil {
+			return delivered, err
+		}
+		if len(pending) == 0 {
+			return delivered, nil
+		}
+		for _, event := range pending {
+			if err := r.publish(ctx, event.Event); err != nil {
+				return delivered, err
+			}
+			if err := r.store.MarkEventDelivered(ctx, event.ID); err != nil {
+				return delivered, err
+			}
+			delivered++
+		}
+	}
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, r.publishTimeout)
+	defer cancel()
+	return r.pub.Publish(ctx, event)
+}
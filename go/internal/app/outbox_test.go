@@ -0,0 +1,40 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestOutboxRelayDeliversPendingEventsOnStartup(t *testing.T) {
+	store := NewInMemoryStore()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Vitals stored while nothing was relaying, e.g. before a restart.
+	for i := range 3 {
+		if _, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120 + int32(i), Diastolic: 80, TakenAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("add vital: %v", err)
+		}
+	}
+
+	pubsub := NewPubSub()
+	events, unsubscribe := pubsub.Subscribe(8)
+	defer unsubscribe()
+	go NewOutboxRelay(store, pubsub, WithRelayInterval(time.Hour)).Run(ctx)
+
+	for want := int64(1); want <= 3; want++ {
+		select {
+		case event := <-events:
+			if event.Vital.ID != want {
+				t.Fatalf("expected vital %d next, got %d", want, event.Vital.ID)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("timed out waiting for vital %d", want)
+		}
+	}
+	waitFor(t, 500*time.Millisecond, func() bool {
+		pending, err := store.ListPendingEvents(ctx, 0)
+		return err == nil && len(pending) == 0
+	})
+}
@@ -3,76 +3,287 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 var (
 	ErrPubSubClosed = errors.New("pubsub is closed")
+	// ErrResumeTokenExpired means the events after a resume token are no
+	// longer retained (or the token predates a restart); the client must
+	// re-list and watch from now.
+	ErrResumeTokenExpired = errors.New("resume token expired")
 )
 
+const defaultHistorySize = 1024
+
+// SlowConsumerPolicy decides what Publish does when a subscriber's buffer is
+// full.
+type SlowConsumerPolicy int
+
+const (
+	// SlowConsumerBlock waits for room (or the publish context). Use it for
+	// consumers that must see every event, like alerting.
+	SlowConsumerBlock SlowConsumerPolicy = iota
+	// SlowConsumerDropOldest discards the oldest buffered event to make room.
+	SlowConsumerDropOldest
+	// SlowConsumerDisconnect closes the subscriber's channel.
+	SlowConsumerDisconnect
+)
+
+func (p SlowConsumerPolicy) String() string {
+	switch p {
+	case SlowConsumerBlock:
+		return "block"
+	case SlowConsumerDropOldest:
+		return "drop-oldest"
+	case SlowConsumerDisconnect:
+		return "disconnect"
+	default:
+		return "unknown"
+	}
+}
+
+type SubscribeOptions struct {
+	Buffer int
+	// Types limits delivery to these event types; empty means all.
+	Types []EventType
+	// PatientID limits delivery to one patient's events; empty means all.
+	PatientID string
+	Policy    SlowConsumerPolicy
+}
+
 type PubSub struct {
 	mu     sync.RWMutex
 	closed bool
 	nextID int
-	subs   map[int]chan Event
+	subs   map[int]*subscriber
+
+	// epoch distinguishes this process's sequence numbers from a previous
+	// run's, so stale resume tokens are rejected rather than misread.
+	epoch       string
+	seq         int64
+	history     []Event
+	historySize int
 }
 
-func NewPubSub() *PubSub {
-	return &PubSub{
-		subs: make(map[int]chan Event),
+type PubSubOption func(*PubSub)
+
+// WithHistory sets how many recent events are kept for resuming
+// subscribers.
+func WithHistory(n int) PubSubOption {
+	return func(p *PubSub) { p.historySize = n }
+}
+
+type subscriber struct {
+	id        int
+	types     []EventType
+	patientID string
+	policy    SlowConsumerPolicy
+
+	// mu serializes delivery with close; done lets close interrupt a
+	// blocked send.
+	mu     sync.Mutex
+	ch     chan Event
+	done   chan struct{}
+	once   sync.Once
+	closed bool
+
+	// turn, for a blocking subscriber, is closed once the last event
+	// published to it has been delivered. Each publisher waits on its
+	// predecessor's turn, so concurrent publishers deliver in sequence
+	// order even though they do it outside PubSub's lock.
+	turn chan struct{}
+}
+
+// blockingDelivery is a publisher's place in a blocking subscriber's line:
+// it may deliver once wait is closed and closes done when finished.
+type blockingDelivery struct {
+	sub        *subscriber
+	wait, done chan struct{}
+}
+
+func NewPubSub(opts ...PubSubOption) *PubSub {
+	p := &PubSub{
+		subs:        make(map[int]*subscriber),
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		historySize: defaultHistorySize,
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
+// Publish stamps event with the next sequence number, records it for
+// resuming subscribers, and fans it out to every interested subscriber
+// according to its slow-consumer policy, so one slow consumer only holds up
+// publishers if it asked to block. It returns the context error if a
+// blocking delivery could not complete.
 func (p *PubSub) Publish(ctx context.Context, event Event) error {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-
+	p.mu.Lock()
 	if p.closed {
+		p.mu.Unlock()
 		return ErrPubSubClosed
 	}
+	p.seq++
+	event.Seq = p.seq
+	p.remember(event)
 
-	for _, ch := range p.subs {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case ch <- event:
+	// Non-blocking subscribers are served under the lock, so they see
+	// events in sequence order and never behind a blocked subscriber.
+	// Blocking subscribers are served after it, each publisher taking its
+	// turn in sequence order.
+	var blocking []blockingDelivery
+	var slow []*subscriber
+	for _, sub := range p.subs {
+		if !sub.wants(event) {
+			continue
 		}
+		if sub.policy == SlowConsumerBlock {
+			done := make(chan struct{})
+			blocking = append(blocking, blockingDelivery{sub: sub, wait: sub.turn, done: done})
+			sub.turn = done
+			continue
+		}
+		if disconnect, _ := sub.deliver(ctx, event); disconnect {
+			slow = append(slow, sub)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, sub := range slow {
+		log.Printf("pubsub: disconnecting slow subscriber %d", sub.id)
+		p.unsubscribe(sub.id)
+	}
+	var err error
+	for _, d := range blocking {
+		if deliverErr := d.deliver(ctx, event); deliverErr != nil && err == nil {
+			err = deliverErr
+		}
+	}
+	return err
+}
+
+func (d blockingDelivery) deliver(ctx context.Context, event Event) error {
+	select {
+	case <-d.wait:
+	case <-ctx.Done():
+		// Give up the event, but pass the turn on only after the
+		// publisher ahead is done, so later events can't overtake it.
+		go func() {
+			<-d.wait
+			close(d.done)
+		}()
+		return ctx.Err()
 	}
-	return nil
+	defer close(d.done)
+	_, err := d.sub.deliver(ctx, event)
+	return err
 }
 
+func (p *PubSub) remember(event Event) {
+	if p.historySize <= 0 {
+		return
+	}
+	if len(p.history) == p.historySize {
+		copy(p.history, p.history[1:])
+		p.history = p.history[:len(p.history)-1]
+	}
+	p.history = append(p.history, event)
+}
+
+// ResumeToken returns an opaque token for resuming a subscription right
+// after event.
+func (p *PubSub) ResumeToken(event Event) string {
+	return p.epoch + "." + strconv.FormatInt(event.Seq, 10)
+}
+
+// SubscribeSince subscribes like SubscribeWith and also returns the retained
+// events after token that match opts, oldest first. Events published after
+// the call arrive on the channel, so nothing is missed or repeated between
+// the two. An empty token subscribes from now.
+func (p *PubSub) SubscribeSince(token string, opts SubscribeOptions) ([]Event, <-chan Event, func(), error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var backlog []Event
+	if token != "" {
+		epoch, seqStr, ok := strings.Cut(token, ".")
+		after, err := strconv.ParseInt(seqStr, 10, 64)
+		if !ok || err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: malformed resume_token", ErrInvalidQuery)
+		}
+		oldest := p.seq + 1
+		if len(p.history) > 0 {
+			oldest = p.history[0].Seq
+		}
+		if epoch != p.epoch || after > p.seq || after < oldest-1 {
+			return nil, nil, nil, ErrResumeTokenExpired
+		}
+		filter := subscriber{types: opts.Types, patientID: opts.PatientID}
+		for _, event := range p.history {
+			if event.Seq > after && filter.wants(event) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+
+	ch, cancel := p.subscribeLocked(opts)
+	return backlog, ch, cancel, nil
+}
+
+// Subscribe registers a blocking subscriber for every event type.
 func (p *PubSub) Subscribe(buffer int) (<-chan Event, func()) {
+	return p.SubscribeWith(SubscribeOptions{Buffer: buffer})
+}
+
+// SubscribeWith registers a subscriber with its own buffer, filter and
+// slow-consumer policy. The returned cancel func is idempotent and closes the
+// channel.
+func (p *PubSub) SubscribeWith(opts SubscribeOptions) (<-chan Event, func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.subscribeLocked(opts)
+}
+
+func (p *PubSub) subscribeLocked(opts SubscribeOptions) (<-chan Event, func()) {
+	buffer := opts.Buffer
 	if buffer <= 0 {
 		buffer = 1
 	}
-	ch := make(chan Event, buffer)
-
-	p.mu.Lock()
-	if p.closed {
-		p.mu.Unlock()
-		close(ch)
-		return ch, func() {}
+	sub := &subscriber{
+		types:     slices.Clone(opts.Types),
+		patientID: opts.PatientID,
+		policy:    opts.Policy,
+		ch:        make(chan Event, buffer),
+		done:      make(chan struct{}),
+		turn:      make(chan struct{}),
 	}
-	if len(p.subs) > 0 {
-		p.mu.Unlock()
-		close(ch)
-		return ch, func() {}
+	close(sub.turn)
+	if p.closed {
+		sub.close()
+		return sub.ch, func() {}
 	}
-	id := p.nextID
+	sub.id = p.nextID
 	p.nextID++
-	p.subs[id] = ch
-	p.mu.Unlock()
+	p.subs[sub.id] = sub
+	return sub.ch, func() { p.unsubscribe(sub.id) }
+}
 
-	cancel := func() {
-		p.mu.Lock()
-		if existing, ok := p.subs[id]; ok {
-			delete(p.subs, id)
-			close(existing)
-		}
-		p.mu.Unlock()
+func (p *PubSub) unsubscribe(id int) {
+	p.mu.Lock()
+	sub, ok := p.subs[id]
+	delete(p.subs, id)
+	p.mu.Unlock()
+	if ok {
+		sub.close()
 	}
-
-	return ch, cancel
 }
 
 func (p *PubSub) Close() {
@@ -82,9 +293,66 @@ func (p *PubSub) Close() {
 		return
 	}
 	p.closed = true
-	for id, ch := range p.subs {
-		delete(p.subs, id)
-		close(ch)
-	}
+	subs := p.subs
+	p.subs = make(map[int]*subscriber)
 	p.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.close()
+	}
+}
+
+func (s *subscriber) wants(event Event) bool {
+	return (len(s.types) == 0 || slices.Contains(s.types, event.Type)) &&
+		(s.patientID == "" || s.patientID == event.PatientID())
+}
+
+// deliver reports whether the subscriber should be disconnected.
+func (s *subscriber) deliver(ctx context.Context, event Event) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false, nil
+	}
+
+	switch s.policy {
+	case SlowConsumerDropOldest:
+		for {
+			select {
+			case s.ch <- event:
+				return false, nil
+			default:
+			}
+			select {
+			case <-s.ch:
+			default:
+			}
+		}
+	case SlowConsumerDisconnect:
+		select {
+		case s.ch <- event:
+			return false, nil
+		default:
+			return true, nil
+		}
+	default:
+		select {
+		case s.ch <- event:
+			return false, nil
+		case <-s.done:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+func (s *subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
 }
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPubSubFansOutToEverySubscriber(t *testing.T) {
+	pubsub := NewPubSub()
+	defer pubsub.Close()
+
+	first, cancelFirst := pubsub.Subscribe(4)
+	defer cancelFirst()
+	second, cancelSecond := pubsub.Subscribe(4)
+	defer cancelSecond()
+
+	ctx := context.Background()
+	for id := int64(1); id <= 2; id++ {
+		if err := pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: id}}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	for _, ch := range []<-chan Event{first, second} {
+		for want := int64(1); want <= 2; want++ {
+			if got := receive(t, ch); got.Vital.ID != want {
+				t.Fatalf("expected vital %d, got %d", want, got.Vital.ID)
+			}
+		}
+	}
+}
+
+func TestPubSubFiltersByEventType(t *testing.T) {
+	pubsub := NewPubSub()
+	defer pubsub.Close()
+
+	const other EventType = "OTHER"
+	vitals, cancel := pubsub.SubscribeWith(SubscribeOptions{Buffer: 4, Types: []EventType{EventTypeVitalReceived}})
+	defer cancel()
+
+	ctx := context.Background()
+	pubsub.Publish(ctx, Event{Type: other})
+	pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 7}})
+
+	if got := receive(t, vitals); got.Type != EventTypeVitalReceived || got.Vital.ID != 7 {
+		t.Fatalf("expected only the vital event, got %+v", got)
+	}
+	select {
+	case got := <-vitals:
+		t.Fatalf("unexpected extra event %+v", got)
+	default:
+	}
+}
+
+func TestPubSubDropOldestKeepsNewestEvents(t *testing.T) {
+	pubsub := NewPubSub()
+	defer pubsub.Close()
+
+	slow, cancel := pubsub.SubscribeWith(SubscribeOptions{Buffer: 2, Policy: SlowConsumerDropOldest})
+	defer cancel()
+
+	ctx := context.Background()
+	for id := int64(1); id <= 5; id++ {
+		if err := pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: id}}); err != nil {
+			t.Fatalf("publish %d: %v", id, err)
+		}
+	}
+	if a, b := receive(t, slow), receive(t, slow); a.Vital.ID != 4 || b.Vital.ID != 5 {
+		t.Fatalf("expected vitals 4 and 5, got %d and %d", a.Vital.ID, b.Vital.ID)
+	}
+}
+
+func TestPubSubDisconnectsSlowConsumerWithoutStallingOthers(t *testing.T) {
+	pubsub := NewPubSub()
+	defer pubsub.Close()
+
+	slow, cancelSlow := pubsub.SubscribeWith(SubscribeOptions{Buffer: 1, Policy: SlowConsumerDisconnect})
+	defer cancelSlow()
+	fast, cancelFast := pubsub.Subscribe(8)
+	defer cancelFast()
+
+	ctx := context.Background()
+	for id := int64(1); id <= 3; id++ {
+		if err := pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: id}}); err != nil {
+			t.Fatalf("publish %d: %v", id, err)
+		}
+	}
+
+	if got := receive(t, slow); got.Vital.ID != 1 {
+		t.Fatalf("expected the buffered event before disconnect, got %d", got.Vital.ID)
+	}
+	if _, ok := <-slow; ok {
+		t.Fatal("expected slow subscriber's channel to be closed")
+	}
+	for want := int64(1); want <= 3; want++ {
+		if got := receive(t, fast); got.Vital.ID != want {
+			t.Fatalf("expected vital %d, got %d", want, got.Vital.ID)
+		}
+	}
+}
+
+func TestPubSubBlockingSubscriberHonorsContextAndCancel(t *testing.T) {
+	pubsub := NewPubSub()
+	defer pubsub.Close()
+
+	_, cancel := pubsub.Subscribe(1)
+	ctx := context.Background()
+	if err := pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer timeoutCancel()
+	if err := pubsub.Publish(timeoutCtx, Event{Type: EventTypeVitalReceived}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// Unsubscribing releases a publisher stuck on the full buffer.
+	done := make(chan error, 1)
+	go func() { done <- pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived}) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected publish to return cleanly, got %v", err)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("publish stayed blocked after unsubscribe")
+	}
+}
+
+func TestPubSubBlockingSubscriberSeesConcurrentPublishesInOrder(t *testing.T) {
+	const publishers, perPublisher = 8, 200
+	pubsub := NewPubSub()
+	defer pubsub.Close()
+	// A small buffer keeps publishers queued up behind one another.
+	ch, cancel := pubsub.Subscribe(1)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	ctx := context.Background()
+	for range publishers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perPublisher {
+				if err := pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived}); err != nil {
+					t.Errorf("publish: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	var last int64
+	for range publishers * perPublisher {
+		event := receive(t, ch)
+		if event.Seq != last+1 {
+			t.Fatalf("expected event %d next, got %d", last+1, event.Seq)
+		}
+		last = event.Seq
+	}
+	wg.Wait()
+}
+
+func receive(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed")
+		}
+		return event
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timed out waiting for event")
+	}
+	return Event{}
+}
+
+func TestPubSubSubscribeSinceReplaysMissedEvents(t *testing.T) {
+	pubsub := NewPubSub(WithHistory(3))
+	defer pubsub.Close()
+	ctx := context.Background()
+
+	var published []Event
+	_, cancel := pubsub.Subscribe(8)
+	defer cancel()
+	watch, unwatch := pubsub.SubscribeWith(SubscribeOptions{Buffer: 8, PatientID: "patient-1"})
+	for id := int64(1); id <= 2; id++ {
+		pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: id, PatientID: "patient-1"}})
+		published = append(published, receive(t, watch))
+	}
+	unwatch()
+	token := pubsub.ResumeToken(published[0])
+
+	// Missed while disconnected: one for another patient, one for ours.
+	pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 3, PatientID: "patient-2"}})
+	pubsub.Publish(ctx, Event{Type: EventTypeAlertCreated, Alert: Alert{ID: 1, PatientID: "patient-1"}})
+
+	backlog, live, stop, err := pubsub.SubscribeSince(token, SubscribeOptions{Buffer: 8, PatientID: "patient-1"})
+	if err != nil {
+		t.Fatalf("subscribe since: %v", err)
+	}
+	defer stop()
+	if len(backlog) != 2 || backlog[0].Vital.ID != 2 || backlog[1].Alert.ID != 1 {
+		t.Fatalf("unexpected backlog: %+v", backlog)
+	}
+	pubsub.Publish(ctx, Event{Type: EventTypeVitalReceived, Vital: Vital{ID: 4, PatientID: "patient-1"}})
+	if got := receive(t, live); got.Vital.ID != 4 || got.Seq != backlog[1].Seq+1 {
+		t.Fatalf("expected live event right after the backlog, got %+v", got)
+	}
+
+	// Event 1 has now been evicted from the three-event history.
+	if _, _, _, err := pubsub.SubscribeSince(token, SubscribeOptions{}); !errors.Is(err, ErrResumeTokenExpired) {
+		t.Fatalf("expected expired token, got %v", err)
+	}
+	if _, _, _, err := NewPubSub().SubscribeSince(pubsub.ResumeToken(published[1]), SubscribeOptions{}); !errors.Is(err, ErrResumeTokenExpired) {
+		t.Fatalf("expected token from another pubsub to be rejected, got %v", err)
+	}
+	if _, _, _, err := pubsub.SubscribeSince("garbage", SubscribeOptions{}); !errors.Is(err, ErrInvalidQuery) {
+		t.Fatalf("expected malformed token error, got %v", err)
+	}
+}
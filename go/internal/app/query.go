@@ -0,0 +1,184 @@
+package app
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidQuery = errors.New("invalid query")
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+type SortOrder int
+
+const (
+	SortAscending SortOrder = iota
+	SortDescending
+)
+
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "asc":
+		return SortAscending, nil
+	case "desc":
+		return SortDescending, nil
+	default:
+		return SortAscending, fmt.Errorf("%w: order must be asc or desc", ErrInvalidQuery)
+	}
+}
+
+// Cursor is a position in (TakenAt, ID) order. Queries resume strictly
+// after it in the query's sort direction.
+type Cursor struct {
+	TakenAt time.Time
+	ID      int64
+}
+
+// TimeRange is half-open: After is inclusive, Before exclusive. Zero values
+// leave that side unbounded.
+type TimeRange struct {
+	After  time.Time
+	Before time.Time
+}
+
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.After.IsZero() && t.Before(r.After) {
+		return false
+	}
+	if !r.Before.IsZero() && !t.Before(r.Before) {
+		return false
+	}
+	return true
+}
+
+type VitalQuery struct {
+	PatientID string
+	TakenAt   TimeRange
+	Order     SortOrder
+	After     *Cursor
+	// Limit caps the result; zero means no limit.
+	Limit int
+}
+
+type AlertQuery struct {
+	PatientID string
+	// Statuses keeps alerts in any of these statuses; empty means all.
+	Statuses []AlertStatus
+	TakenAt  TimeRange
+	Order    SortOrder
+	After    *Cursor
+	Limit    int
+}
+
+// afterCursor reports whether (takenAt, id) sorts strictly after c in o.
+func (o SortOrder) afterCursor(c *Cursor, takenAt time.Time, id int64) bool {
+	if c == nil {
+		return true
+	}
+	cmp := compareKey(takenAt, id, c.TakenAt, c.ID)
+	if o == SortDescending {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+func (o SortOrder) less(aTaken time.Time, aID int64, bTaken time.Time, bID int64) bool {
+	cmp := compareKey(aTaken, aID, bTaken, bID)
+	if o == SortDescending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func compareKey(aTaken time.Time, aID int64, bTaken time.Time, bID int64) int {
+	if c := aTaken.Compare(bTaken); c != 0 {
+		return c
+	}
+	switch {
+	case aID < bID:
+		return -1
+	case aID > bID:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (q VitalQuery) matches(v Vital) bool {
+	return (q.PatientID == "" || v.PatientID == q.PatientID) &&
+		q.TakenAt.Contains(v.TakenAt) &&
+		q.Order.afterCursor(q.After, v.TakenAt, v.ID)
+}
+
+func (q AlertQuery) matches(a Alert) bool {
+	return (q.PatientID == "" || a.PatientID == q.PatientID) &&
+		(len(q.Statuses) == 0 || slices.Contains(q.Statuses, a.Status)) &&
+		q.TakenAt.Contains(a.TakenAt) &&
+		q.Order.afterCursor(q.After, a.TakenAt, a.ID)
+}
+
+// pageScope identifies what a page token was issued for: the listing, its
+// filters and its order. A cursor only means something within that query,
+// so a token presented with different parameters is rejected.
+func pageScope(listing, patientID string, statuses []AlertStatus, r TimeRange, o SortOrder) string {
+	statuses = slices.Clone(statuses)
+	slices.Sort(statuses)
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s|%v|%d|%d|%d", listing, patientID, statuses, r.After.UnixNano(), r.Before.UnixNano(), o)
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// encodePageToken makes a cursor opaque to clients. The format is private;
+// callers must treat tokens as strings to hand back unchanged.
+func encodePageToken(c Cursor, scope string) string {
+	raw := strconv.FormatInt(c.TakenAt.UnixNano(), 10) + ":" + strconv.FormatInt(c.ID, 10) + ":" + scope
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token, scope string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	invalid := fmt.Errorf("%w: malformed page_token", ErrInvalidQuery)
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 {
+		return nil, invalid
+	}
+	nanos, id := parts[0], parts[1]
+	if parts[2] != scope {
+		return nil, fmt.Errorf("%w: page_token was issued for a different filter or order", ErrInvalidQuery)
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	i, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, invalid
+	}
+	return &Cursor{TakenAt: time.Unix(0, n).UTC(), ID: i}, nil
+}
+
+func pageSize(requested int) (int, error) {
+	switch {
+	case requested < 0:
+		return 0, fmt.Errorf("%w: page_size must not be negative", ErrInvalidQuery)
+	case requested == 0:
+		return DefaultPageSize, nil
+	default:
+		return min(requested, MaxPageSize), nil
+	}
+}
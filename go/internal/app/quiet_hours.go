@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QuietHours is a daily window, in a recipient's own time zone, during
+// which only critical messages are sent. Start and End are minutes after
+// local midnight; a window whose End is before its Start runs past
+// midnight. The zero value has no quiet hours.
+type QuietHours struct {
+	Location *time.Location
+	Start    int
+	End      int
+}
+
+// ParseQuietHours parses a window such as "22:00-07:00" in loc, which
+// defaults to UTC. An empty spec means no quiet hours.
+func ParseQuietHours(spec string, loc *time.Location) (QuietHours, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if strings.TrimSpace(spec) == "" {
+		return QuietHours{Location: loc}, nil
+	}
+	from, to, ok := strings.Cut(spec, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: want HH:MM-HH:MM", spec)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: %w", spec, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("quiet hours %q: %w", spec, err)
+	}
+	return QuietHours{Location: loc, Start: start, End: end}, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("bad time %q", strings.TrimSpace(s))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (h QuietHours) String() string {
+	if h.Start == h.End {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", h.Start/60, h.Start%60, h.End/60, h.End%60)
+}
+
+// Until reports when the quiet hours t falls in end, or the zero time if t
+// isn't in quiet hours.
+func (h QuietHours) Until(t time.Time) time.Time {
+	if h.Start == h.End {
+		return time.Time{}
+	}
+	loc := h.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Day()
+	switch {
+	case h.Start < h.End && minute >= h.Start && minute < h.End:
+	case h.Start > h.End && minute < h.End:
+	case h.Start > h.End && minute >= h.Start:
+		day++
+	default:
+		return time.Time{}
+	}
+	// time.Date normalizes the day and minute overflow and resolves the
+	// end against the zone's offset on that day, so DST is handled.
+	return time.Date(local.Year(), local.Month(), day, 0, h.End, 0, 0, loc)
+}
+
+// QuietHoursDirectory reports the quiet hours of a message's recipient.
+type QuietHoursDirectory interface {
+	QuietHours(patientID string, recipient Recipient) QuietHours
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fixedQuietHours QuietHours
+
+func (h fixedQuietHours) QuietHours(string, Recipient) QuietHours { return QuietHours(h) }
+
+func TestQuietHoursUntil(t *testing.T) {
+	chicago, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	overnight, err := ParseQuietHours("22:00-07:00", chicago)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for _, tc := range []struct {
+		at, want string
+	}{
+		{"2025-03-08T21:59:00-06:00", ""},
+		{"2025-03-08T22:00:00-06:00", "2025-03-09T07:00:00-05:00"}, // ends after the switch to CDT
+		{"2025-03-09T03:00:00-05:00", "2025-03-09T07:00:00-05:00"},
+		{"2025-03-09T07:00:00-05:00", ""},
+		// 04:00 UTC is 22:00 the evening before in Chicago.
+		{"2025-06-02T04:00:00Z", "2025-06-02T07:00:00-05:00"},
+	} {
+		at, _ := time.Parse(time.RFC3339, tc.at)
+		got := overnight.Until(at)
+		if tc.want == "" {
+			if !got.IsZero() {
+				t.Errorf("Until(%s) = %s, want outside quiet hours", tc.at, got)
+			}
+			continue
+		}
+		want, _ := time.Parse(time.RFC3339, tc.want)
+		if !got.Equal(want) {
+			t.Errorf("Until(%s) = %s, want %s", tc.at, got, want)
+		}
+	}
+
+	lunch, _ := ParseQuietHours("12:00-13:30", nil)
+	if got := lunch.Until(time.Date(2025, 1, 1, 12, 45, 0, 0, time.UTC)); !got.Equal(time.Date(2025, 1, 1, 13, 30, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected end of a daytime window: %s", got)
+	}
+	if _, err := ParseQuietHours("22:00", nil); err == nil {
+		t.Fatal("expected a window without an end to be rejected")
+	}
+}
+
+func TestMessageQueueDefersDuringQuietHours(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC))
+	hours, _ := ParseQuietHours("22:00-07:00", time.UTC)
+	queue := NewMessageQueue(0, 0, WithQueueClock(clock), WithQuietHours(fixedQuietHours(hours)),
+		WithMessageSender(MessageSenderFunc(func(context.Context, Message) (Delivery, error) { return Delivery{}, nil })))
+	ctx := context.Background()
+	morning := time.Date(2025, 1, 2, 7, 0, 0, 0, time.UTC)
+
+	deferred, _ := queue.Enqueue(Message{PatientID: "patient-1", AlertID: 1, Content: "retake"})
+	if !deferred.ScheduledFor.Equal(morning) {
+		t.Fatalf("expected the message scheduled for 07:00, got %+v", deferred)
+	}
+	urgent, _ := queue.Enqueue(Message{PatientID: "patient-2", AlertID: 2, Content: "retake now", Priority: MessagePriorityCritical})
+	if !urgent.ScheduledFor.IsZero() {
+		t.Fatalf("expected a critical message to skip quiet hours, got %+v", urgent)
+	}
+
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.ID != urgent.ID || msg.Status != MessageStatusSent {
+		t.Fatalf("expected the critical message sent first, got %+v", msg)
+	}
+	if msg := queue.ProcessNext(ctx); msg != nil {
+		t.Fatalf("expected nothing due before 07:00, got %+v", msg)
+	}
+	clock.Advance(morning.Sub(clock.Now()))
+	if msg := queue.ProcessNext(ctx); msg == nil || msg.ID != deferred.ID || msg.Status != MessageStatusSent {
+		t.Fatalf("expected the deferred message sent at 07:00, got %+v", msg)
+	}
+
+	// A message scheduled into quiet hours waits for them to end too.
+	scheduled, _ := queue.Enqueue(Message{PatientID: "patient-3", AlertID: 3, Content: "reminder", ScheduledFor: morning.Add(15 * time.Hour)})
+	if want := morning.Add(24 * time.Hour); !scheduled.ScheduledFor.Equal(want) {
+		t.Fatalf("expected the message moved to %s, got %s", want, scheduled.ScheduledFor)
+	}
+}
+
+func TestAlertWorkerSendsOnlyCriticalAlertsDuringQuietHours(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	clock := newFakeClock(time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC))
+	hours, _ := ParseQuietHours("22:00-07:00", time.UTC)
+	queue := NewMessageQueue(0, 0, WithQueueClock(clock), WithQuietHours(fixedQuietHours(hours)))
+	worker := NewAlertWorker(pubsub, store, 8, queue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.Run(ctx)
+
+	publishVital(t, ctx, pubsub, Vital{ID: 1, PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	publishVital(t, ctx, pubsub, Vital{ID: 2, PatientID: "patient-2", Kind: VitalKindSpO2, Value: 85, TakenAt: time.Now().UTC()})
+	waitFor(t, time.Second, func() bool { return len(queue.ListMessages()) == 2 })
+
+	for _, msg := range queue.ListMessages() {
+		switch msg.PatientID {
+		case "patient-1":
+			if msg.Priority != MessagePriorityCritical || !msg.ScheduledFor.IsZero() {
+				t.Fatalf("expected the critical alert's message to go out now, got %+v", msg)
+			}
+		case "patient-2":
+			if msg.Priority != MessagePriorityHigh || !msg.ScheduledFor.Equal(time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)) {
+				t.Fatalf("expected the high alert's message deferred to 07:00, got %+v", msg)
+			}
+		}
+	}
+}
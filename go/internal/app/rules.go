@@ -0,0 +1,354 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Severity int32
+
+const (
+	SeverityUnspecified Severity = 0
+	SeverityLow         Severity = 1
+	SeverityModerate    Severity = 2
+	SeverityHigh        Severity = 3
+	SeverityCritical    Severity = 4
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityLow:
+		return "LOW"
+	case SeverityModerate:
+		return "MODERATE"
+	case SeverityHigh:
+		return "HIGH"
+	case SeverityCritical:
+		return "CRITICAL"
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Reason   string
+}
+
+// Rule inspects a reading, optionally in light of the patient's earlier
+// readings (oldest first), and reports anything clinically notable.
+type Rule interface {
+	Name() string
+	Evaluate(vital Vital, history []Vital) []Finding
+}
+
+// RuleSet is the configured collection of rules. Findings at or above
+// AlertAt open an alert; anything lower is informational.
+type RuleSet struct {
+	Rules   []Rule
+	AlertAt Severity
+}
+
+func DefaultRuleSet() RuleSet {
+	return RuleSet{
+		Rules: []Rule{
+			HypertensiveCrisisRule{MaxSystolic: MaxSystolic, MaxDiastolic: MaxDiastolic},
+			Stage2HypertensionRule{Systolic: 140, Diastolic: 90},
+			HypotensionRule{MinSystolic: 90},
+			WidePulsePressureRule{MaxPulsePressure: 60},
+			TachycardiaRule{MaxPulse: 130},
+			BradycardiaRule{MinPulse: 40},
+			HypoxemiaRule{MinSpO2: 90},
+			HypoglycemiaRule{MinGlucose: 70, SevereBelow: 54},
+			HyperglycemiaRule{MaxGlucose: 300},
+			WeightGainRule{MaxGain: 2.3, Window: 7 * 24 * time.Hour},
+		},
+		AlertAt: SeverityHigh,
+	}
+}
+
+// Evaluate runs every rule and returns the findings, most severe first.
+func (rs RuleSet) Evaluate(vital Vital, history []Vital) []Finding {
+	var findings []Finding
+	for _, rule := range rs.Rules {
+		findings = append(findings, rule.Evaluate(vital, history)...)
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity > findings[j].Severity
+	})
+	return findings
+}
+
+func (rs RuleSet) Alerting(findings []Finding) []Finding {
+	var alerting []Finding
+	for _, f := range findings {
+		if f.Severity >= rs.AlertAt {
+			alerting = append(alerting, f)
+		}
+	}
+	return alerting
+}
+
+// MaxSeverity returns the highest severity among findings.
+func MaxSeverity(findings []Finding) Severity {
+	max := SeverityUnspecified
+	for _, f := range findings {
+		if f.Severity > max {
+			max = f.Severity
+		}
+	}
+	return max
+}
+
+// IsAbnormal reports whether vital opens an alert under DefaultRuleSet,
+// ignoring the patient's history.
+//
+// Deprecated: evaluate a RuleSet, which takes history and per-patient
+// thresholds into account.
+func IsAbnormal(vital Vital) bool {
+	rules := DefaultRuleSet()
+	return len(rules.Alerting(rules.Evaluate(vital, nil))) > 0
+}
+
+// AlertReason describes why DefaultRuleSet would alert on vital, or the
+// reading itself if it wouldn't.
+//
+// Deprecated: use FindingsReason on a RuleSet's findings.
+func AlertReason(vital Vital) string {
+	rules := DefaultRuleSet()
+	if findings := rules.Alerting(rules.Evaluate(vital, nil)); len(findings) > 0 {
+		return FindingsReason(findings)
+	}
+	return "abnormal reading " + vital.Measurement()
+}
+
+// FindingsReason joins the findings' reasons into a single alert reason.
+func FindingsReason(findings []Finding) string {
+	reasons := make([]string, len(findings))
+	for i, f := range findings {
+		reasons[i] = f.Reason
+	}
+	return strings.Join(reasons, "; ")
+}
+
+type HypertensiveCrisisRule struct {
+	MaxSystolic  int32
+	MaxDiastolic int32
+}
+
+func (HypertensiveCrisisRule) Name() string { return "hypertensive_crisis" }
+
+func (r HypertensiveCrisisRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindBloodPressure {
+		return nil
+	}
+	if vital.Systolic <= r.MaxSystolic && vital.Diastolic <= r.MaxDiastolic {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityCritical,
+		Reason:   fmt.Sprintf("hypertensive crisis %d/%d (limit %d/%d)", vital.Systolic, vital.Diastolic, r.MaxSystolic, r.MaxDiastolic),
+	}}
+}
+
+type Stage2HypertensionRule struct {
+	Systolic  int32
+	Diastolic int32
+}
+
+func (Stage2HypertensionRule) Name() string { return "stage2_hypertension" }
+
+func (r Stage2HypertensionRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindBloodPressure {
+		return nil
+	}
+	if vital.Systolic < r.Systolic && vital.Diastolic < r.Diastolic {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityModerate,
+		Reason:   fmt.Sprintf("stage 2 hypertension %d/%d", vital.Systolic, vital.Diastolic),
+	}}
+}
+
+type HypotensionRule struct {
+	MinSystolic int32
+}
+
+func (HypotensionRule) Name() string { return "hypotension" }
+
+func (r HypotensionRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindBloodPressure {
+		return nil
+	}
+	if vital.Systolic >= r.MinSystolic {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityHigh,
+		Reason:   fmt.Sprintf("hypotension %d/%d (systolic below %d)", vital.Systolic, vital.Diastolic, r.MinSystolic),
+	}}
+}
+
+type WidePulsePressureRule struct {
+	MaxPulsePressure int32
+}
+
+func (WidePulsePressureRule) Name() string { return "wide_pulse_pressure" }
+
+func (r WidePulsePressureRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindBloodPressure {
+		return nil
+	}
+	pp := vital.Systolic - vital.Diastolic
+	if pp <= r.MaxPulsePressure {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityModerate,
+		Reason:   fmt.Sprintf("widened pulse pressure %d mmHg", pp),
+	}}
+}
+
+type TachycardiaRule struct {
+	MaxPulse float64
+}
+
+func (TachycardiaRule) Name() string { return "tachycardia" }
+
+func (r TachycardiaRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindPulse || vital.Value <= r.MaxPulse {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityHigh,
+		Reason:   fmt.Sprintf("tachycardia %g bpm (limit %g)", vital.Value, r.MaxPulse),
+	}}
+}
+
+type BradycardiaRule struct {
+	MinPulse float64
+}
+
+func (BradycardiaRule) Name() string { return "bradycardia" }
+
+func (r BradycardiaRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindPulse || vital.Value >= r.MinPulse {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityHigh,
+		Reason:   fmt.Sprintf("bradycardia %g bpm (limit %g)", vital.Value, r.MinPulse),
+	}}
+}
+
+type HypoxemiaRule struct {
+	MinSpO2 float64
+}
+
+func (HypoxemiaRule) Name() string { return "hypoxemia" }
+
+func (r HypoxemiaRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindSpO2 || vital.Value >= r.MinSpO2 {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityHigh,
+		Reason:   fmt.Sprintf("low oxygen saturation %g%% (limit %g%%)", vital.Value, r.MinSpO2),
+	}}
+}
+
+// HypoglycemiaRule flags low glucose; readings under SevereBelow are
+// critical.
+type HypoglycemiaRule struct {
+	MinGlucose  float64
+	SevereBelow float64
+}
+
+func (HypoglycemiaRule) Name() string { return "hypoglycemia" }
+
+func (r HypoglycemiaRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindGlucose || vital.Value >= r.MinGlucose {
+		return nil
+	}
+	severity := SeverityHigh
+	if vital.Value < r.SevereBelow {
+		severity = SeverityCritical
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: severity,
+		Reason:   fmt.Sprintf("hypoglycemia %g mg/dL (limit %g)", vital.Value, r.MinGlucose),
+	}}
+}
+
+type HyperglycemiaRule struct {
+	MaxGlucose float64
+}
+
+func (HyperglycemiaRule) Name() string { return "hyperglycemia" }
+
+func (r HyperglycemiaRule) Evaluate(vital Vital, _ []Vital) []Finding {
+	if vital.Kind != VitalKindGlucose || vital.Value <= r.MaxGlucose {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityHigh,
+		Reason:   fmt.Sprintf("hyperglycemia %g mg/dL (limit %g)", vital.Value, r.MaxGlucose),
+	}}
+}
+
+// WeightGainRule flags rapid gain, an early sign of fluid retention: more
+// than MaxGain kg above the lowest weight recorded within Window.
+type WeightGainRule struct {
+	MaxGain float64
+	Window  time.Duration
+}
+
+func (WeightGainRule) Name() string { return "rapid_weight_gain" }
+
+func (r WeightGainRule) Evaluate(vital Vital, history []Vital) []Finding {
+	if vital.Kind != VitalKindWeight {
+		return nil
+	}
+	since := vital.TakenAt.Add(-r.Window)
+	baseline, found := 0.0, false
+	for _, v := range history {
+		if v.Kind != VitalKindWeight || v.TakenAt.Before(since) {
+			continue
+		}
+		if !found || v.Value < baseline {
+			baseline, found = v.Value, true
+		}
+	}
+	if !found || vital.Value-baseline <= r.MaxGain {
+		return nil
+	}
+	return []Finding{{
+		Rule:     r.Name(),
+		Severity: SeverityHigh,
+		Reason:   fmt.Sprintf("weight up %.1f kg to %g kg within %s", vital.Value-baseline, vital.Value, days(r.Window)),
+	}}
+}
+
+func days(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d%day != 0 {
+		return d.String()
+	}
+	if d == day {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", d/day)
+}
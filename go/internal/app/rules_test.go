@@ -0,0 +1,165 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDefaultRuleSetFindings(t *testing.T) {
+	rules := DefaultRuleSet()
+	cases := []struct {
+		name      string
+		systolic  int32
+		diastolic int32
+		rules     []string
+		alerting  bool
+		severity  Severity
+	}{
+		{"normal", 120, 80, nil, false, SeverityUnspecified},
+		{"stage 2", 150, 95, []string{"stage2_hypertension"}, false, SeverityModerate},
+		{"crisis systolic", 190, 100, []string{"hypertensive_crisis", "stage2_hypertension", "wide_pulse_pressure"}, true, SeverityCritical},
+		{"crisis diastolic", 170, 125, []string{"hypertensive_crisis", "stage2_hypertension"}, true, SeverityCritical},
+		{"hypotension", 85, 55, []string{"hypotension"}, true, SeverityHigh},
+		{"wide pulse pressure", 135, 60, []string{"wide_pulse_pressure"}, false, SeverityModerate},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := rules.Evaluate(Vital{Systolic: tc.systolic, Diastolic: tc.diastolic}, nil)
+			got := map[string]bool{}
+			for _, f := range findings {
+				got[f.Rule] = true
+				if f.Reason == "" {
+					t.Fatalf("finding %s has no reason", f.Rule)
+				}
+			}
+			if len(got) != len(tc.rules) {
+				t.Fatalf("expected rules %v, got %+v", tc.rules, findings)
+			}
+			for _, name := range tc.rules {
+				if !got[name] {
+					t.Fatalf("expected rule %s to fire, got %+v", name, findings)
+				}
+			}
+			if alerting := len(rules.Alerting(findings)) > 0; alerting != tc.alerting {
+				t.Fatalf("expected alerting=%v, got %v", tc.alerting, alerting)
+			}
+			if sev := MaxSeverity(findings); sev != tc.severity {
+				t.Fatalf("expected severity %s, got %s", tc.severity, sev)
+			}
+		})
+	}
+}
+
+func TestIsAbnormalUsesDefaultRuleSet(t *testing.T) {
+	cases := []struct {
+		vital    Vital
+		abnormal bool
+	}{
+		{Vital{Systolic: 120, Diastolic: 80}, false},
+		{Vital{Systolic: 150, Diastolic: 95}, false},
+		{Vital{Systolic: 190, Diastolic: 100}, true},
+		{Vital{Systolic: 85, Diastolic: 55}, true},
+	}
+	for _, tc := range cases {
+		if got := IsAbnormal(tc.vital); got != tc.abnormal {
+			t.Fatalf("IsAbnormal(%s) = %v, want %v", tc.vital.Measurement(), got, tc.abnormal)
+		}
+		if reason := AlertReason(tc.vital); reason == "" {
+			t.Fatalf("AlertReason(%s) is empty", tc.vital.Measurement())
+		}
+	}
+	if reason := AlertReason(Vital{Systolic: 85, Diastolic: 55}); reason != FindingsReason(DefaultRuleSet().Evaluate(Vital{Systolic: 85, Diastolic: 55}, nil)) {
+		t.Fatalf("unexpected reason %q", reason)
+	}
+}
+
+func TestDefaultRuleSetEvaluatesEachKind(t *testing.T) {
+	rules := DefaultRuleSet()
+	now := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		vital    Vital
+		history  []Vital
+		rule     string
+		severity Severity
+	}{
+		{"normal pulse", Vital{Kind: VitalKindPulse, Value: 72}, nil, "", SeverityUnspecified},
+		{"tachycardia", Vital{Kind: VitalKindPulse, Value: 145}, nil, "tachycardia", SeverityHigh},
+		{"bradycardia", Vital{Kind: VitalKindPulse, Value: 35}, nil, "bradycardia", SeverityHigh},
+		{"low spo2", Vital{Kind: VitalKindSpO2, Value: 86}, nil, "hypoxemia", SeverityHigh},
+		{"low glucose", Vital{Kind: VitalKindGlucose, Value: 65}, nil, "hypoglycemia", SeverityHigh},
+		{"severe low glucose", Vital{Kind: VitalKindGlucose, Value: 45}, nil, "hypoglycemia", SeverityCritical},
+		{"high glucose", Vital{Kind: VitalKindGlucose, Value: 320}, nil, "hyperglycemia", SeverityHigh},
+		{
+			"weight gain within window",
+			Vital{Kind: VitalKindWeight, Value: 84, TakenAt: now},
+			[]Vital{
+				{Kind: VitalKindWeight, Value: 78, TakenAt: now.Add(-10 * 24 * time.Hour)},
+				{Kind: VitalKindWeight, Value: 81, TakenAt: now.Add(-5 * 24 * time.Hour)},
+			},
+			"rapid_weight_gain", SeverityHigh,
+		},
+		{
+			"weight gain outside window",
+			Vital{Kind: VitalKindWeight, Value: 84, TakenAt: now},
+			[]Vital{{Kind: VitalKindWeight, Value: 78, TakenAt: now.Add(-10 * 24 * time.Hour)}},
+			"", SeverityUnspecified,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := rules.Evaluate(tc.vital, tc.history)
+			if tc.rule == "" {
+				if len(findings) != 0 {
+					t.Fatalf("expected no findings, got %+v", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].Rule != tc.rule || findings[0].Severity != tc.severity {
+				t.Fatalf("expected %s at %s, got %+v", tc.rule, tc.severity, findings)
+			}
+		})
+	}
+}
+
+type risingTrendRule struct{}
+
+func (risingTrendRule) Name() string { return "rising_trend" }
+
+func (risingTrendRule) Evaluate(vital Vital, history []Vital) []Finding {
+	if len(history) < 2 {
+		return nil
+	}
+	prev := history[len(history)-1]
+	first := history[len(history)-2]
+	if first.Systolic < prev.Systolic && prev.Systolic < vital.Systolic {
+		return []Finding{{Rule: "rising_trend", Severity: SeverityHigh, Reason: fmt.Sprintf("systolic rising to %d", vital.Systolic)}}
+	}
+	return nil
+}
+
+func TestAlertWorkerEvaluatesConfiguredRuleSetWithHistory(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	worker := NewAlertWorker(pubsub, store, 8, nil, WithRuleSet(RuleSet{Rules: []Rule{risingTrendRule{}}, AlertAt: SeverityHigh}))
+
+	ctx := context.Background()
+	base := time.Now().UTC().Add(-time.Hour)
+	for i, sys := range []int32{120, 130, 140} {
+		stored, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: sys, Diastolic: 80, TakenAt: base.Add(time.Duration(i) * time.Minute)})
+		if err != nil {
+			t.Fatalf("add vital: %v", err)
+		}
+		worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: stored})
+	}
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert from trend rule, got %d", len(alerts))
+	}
+	if alerts[0].Severity != SeverityHigh || alerts[0].Reason != "systolic rising to 140" {
+		t.Fatalf("unexpected alert: %+v", alerts[0])
+	}
+}
@@ -4,94 +4,377 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
 
 var ErrInvalidVital = errors.New("invalid vital")
 
-type Publisher interface {
-	Publish(ctx context.Context, event Event) error
+type Service struct {
+	store      Store
+	relay      *OutboxRelay
+	events     Publisher
+	messages   *MessageQueue
+	retention  time.Duration
+	validation ValidationPolicy
+	clock      Clock
 }
 
-type Service struct {
-	store Store
-	pub   Publisher
+type ServiceOption func(*Service)
+
+// WithIdempotencyRetention sets how long an idempotency key keeps returning
+// the vital it first created.
+func WithIdempotencyRetention(d time.Duration) ServiceOption {
+	return func(s *Service) { s.retention = d }
 }
 
-func NewService(store Store, pub Publisher) *Service {
-	return &Service{store: store, pub: pub}
+// WithAlertEvents publishes ALERT_STATUS_CHANGED when a clinician
+// acknowledges or resolves an alert.
+func WithAlertEvents(pub Publisher) ServiceOption {
+	return func(s *Service) { s.events = pub }
 }
 
-func (s *Service) IngestVital(ctx context.Context, patientID string, systolic, diastolic int32, takenAt time.Time) (Vital, error) {
-	patientID = strings.TrimSpace(patientID)
-	if patientID == "" {
-		return Vital{}, fmt.Errorf("%w: patient_id is required", ErrInvalidVital)
-	}
-	if systolic <= 0 || diastolic <= 0 {
-		return Vital{}, fmt.Errorf("%w: systolic and diastolic must be positive", ErrInvalidVital)
+// WithMessageQueue cancels an alert's undelivered retake request when a
+// clinician resolves the alert.
+func WithMessageQueue(q *MessageQueue) ServiceOption {
+	return func(s *Service) { s.messages = q }
+}
+
+func WithValidationPolicy(policy ValidationPolicy) ServiceOption {
+	return func(s *Service) { s.validation = policy }
+}
+
+func WithServiceClock(clock Clock) ServiceOption {
+	return func(s *Service) { s.clock = clock }
+}
+
+// WithOutboxRelay wakes relay after each ingest so the vital's event goes
+// out without waiting for the relay's next poll.
+func WithOutboxRelay(relay *OutboxRelay) ServiceOption {
+	return func(s *Service) { s.relay = relay }
+}
+
+func NewService(store Store, opts ...ServiceOption) *Service {
+	s := &Service{
+		store:      store,
+		retention:  DefaultIdempotencyRetention,
+		validation: DefaultValidationPolicy(),
+		clock:      SystemClock(),
 	}
-	if takenAt.IsZero() {
-		return Vital{}, fmt.Errorf("%w: taken_at is required", ErrInvalidVital)
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
+}
 
-	vital := Vital{
-		PatientID:  patientID,
-		Systolic:   systolic,
-		Diastolic:  diastolic,
-		TakenAt:    takenAt.UTC(),
-		ReceivedAt: time.Now().UTC(),
-	}
+type IngestVitalRequest struct {
+	PatientID string
+	// Kind defaults to blood pressure, which uses Systolic and Diastolic.
+	// Other kinds set Value, in Unit or the kind's default unit.
+	Kind      VitalKind
+	Systolic  int32
+	Diastolic int32
+	Value     float64
+	Unit      string
+	TakenAt   time.Time
+	// IdempotencyKey is optional. Retrying with the same key and payload
+	// returns the originally stored vital instead of storing a duplicate.
+	IdempotencyKey string
+	// ClientID identifies the caller. Idempotency keys are scoped to it and
+	// to the patient; empty is a namespace of its own.
+	ClientID string
+}
 
-	stored, err := s.store.AddVital(ctx, vital)
+func (s *Service) IngestVital(ctx context.Context, req IngestVitalRequest) (Vital, error) {
+	now := s.clock.Now().UTC()
+	vital, key, err := s.newVital(req, now)
 	if err != nil {
 		return Vital{}, err
 	}
+	stored, err := s.storeVital(ctx, vital, key, now)
+	if err != nil {
+		return Vital{}, err
+	}
+	if s.relay != nil {
+		s.relay.Notify()
+	}
+	return stored, nil
+}
 
-	event := Event{
-		Type:  EventTypeVitalReceived,
-		Vital: stored,
+// MaxIngestBatch caps how many readings one IngestVitals call accepts.
+const MaxIngestBatch = 500
+
+// IngestResult is the outcome for one reading of a batch; exactly one of
+// Vital and Err is set.
+type IngestResult struct {
+	Vital Vital
+	Err   error
+}
+
+// IngestVitals validates and stores a batch of readings, returning one
+// result per request in request order. Valid readings are stored oldest
+// TakenAt first, so their VITAL_RECEIVED events reach the alert worker in
+// the order the readings were taken. Each reading commits on its own; a
+// failed one does not roll back the rest, and retrying the batch with
+// idempotency keys set is safe.
+func (s *Service) IngestVitals(ctx context.Context, reqs []IngestVitalRequest) ([]IngestResult, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%w: batch is empty", ErrInvalidVital)
+	}
+	if len(reqs) > MaxIngestBatch {
+		return nil, fmt.Errorf("%w: batch has %d readings, at most %d allowed", ErrInvalidVital, len(reqs), MaxIngestBatch)
 	}
 
-	if err := s.pub.Publish(ctx, event); err != nil {
-		return stored, err
+	now := s.clock.Now().UTC()
+	results := make([]IngestResult, len(reqs))
+	type pending struct {
+		index int
+		vital Vital
+		key   IdempotencyKey
+	}
+	valid := make([]pending, 0, len(reqs))
+	for i, req := range reqs {
+		vital, key, err := s.newVital(req, now)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		valid = append(valid, pending{index: i, vital: vital, key: key})
 	}
+	slices.SortStableFunc(valid, func(a, b pending) int {
+		return a.vital.TakenAt.Compare(b.vital.TakenAt)
+	})
 
-	return stored, nil
+	for _, p := range valid {
+		if err := ctx.Err(); err != nil {
+			results[p.index].Err = err
+			continue
+		}
+		stored, err := s.storeVital(ctx, p.vital, p.key, now)
+		if err != nil {
+			results[p.index].Err = err
+			continue
+		}
+		results[p.index].Vital = stored
+	}
+	if s.relay != nil && len(valid) > 0 {
+		s.relay.Notify()
+	}
+	return results, nil
+}
+
+func (s *Service) newVital(req IngestVitalRequest, now time.Time) (Vital, IdempotencyKey, error) {
+	var errs violations
+	patientID := strings.TrimSpace(req.PatientID)
+	if patientID == "" {
+		errs.add("patient_id", "is required")
+	}
+	vital := normalizeMeasurement(Vital{
+		PatientID:  patientID,
+		Kind:       req.Kind,
+		Systolic:   req.Systolic,
+		Diastolic:  req.Diastolic,
+		Value:      req.Value,
+		TakenAt:    req.TakenAt.UTC(),
+		ReceivedAt: now,
+	}, strings.TrimSpace(req.Unit), &errs)
+	s.validation.checkTakenAt(req.TakenAt, now, &errs)
+	key := IdempotencyKey{
+		ClientID: strings.TrimSpace(req.ClientID),
+		Key:      strings.TrimSpace(req.IdempotencyKey),
+	}
+	if len(key.Key) > maxIdempotencyKeyLen {
+		errs.add("idempotency_key", "must be at most %d characters", maxIdempotencyKeyLen)
+	}
+	if len(key.ClientID) > maxIdempotencyKeyLen {
+		errs.add("client_id", "must be at most %d characters", maxIdempotencyKeyLen)
+	}
+	if err := errs.err(); err != nil {
+		return Vital{}, IdempotencyKey{}, err
+	}
+	vital.Flags = qualityFlags(vital)
+	return vital, key, nil
+}
+
+// storeVital commits the vital and its VITAL_RECEIVED event together; the
+// outbox relay publishes the event. A replayed key has no new event: the
+// original one is either delivered or still pending.
+func (s *Service) storeVital(ctx context.Context, vital Vital, key IdempotencyKey, now time.Time) (Vital, error) {
+	if key.Key == "" {
+		return s.store.AddVital(ctx, vital)
+	}
+	key.Fingerprint = vitalFingerprint(vital)
+	key.ExpiresAt = now.Add(s.retention)
+	stored, _, err := s.store.AddVitalIdempotent(ctx, vital, key)
+	return stored, err
 }
 
-func (s *Service) ListAlerts(ctx context.Context, patientID string) ([]Alert, error) {
-	alerts, err := s.store.ListAlerts(ctx)
+type ListVitalsRequest struct {
+	PatientID string
+	TakenAt   TimeRange
+	Order     SortOrder
+	// PageSize defaults to DefaultPageSize and is capped at MaxPageSize.
+	PageSize  int
+	PageToken string
+}
+
+type VitalPage struct {
+	Vitals []Vital
+	// NextPageToken is empty on the last page.
+	NextPageToken string
+}
+
+type ListAlertsRequest struct {
+	PatientID string
+	Statuses  []AlertStatus
+	TakenAt   TimeRange
+	Order     SortOrder
+	PageSize  int
+	PageToken string
+}
+
+type AlertPage struct {
+	Alerts        []Alert
+	NextPageToken string
+}
+
+func (s *Service) ListVitals(ctx context.Context, req ListVitalsRequest) (VitalPage, error) {
+	patientID := strings.TrimSpace(req.PatientID)
+	scope := pageScope("vitals", patientID, nil, req.TakenAt, req.Order)
+	size, after, err := pageParams(req.PageSize, req.PageToken, scope, req.TakenAt)
+	if err != nil {
+		return VitalPage{}, err
+	}
+	// Fetch one extra row to learn whether another page exists.
+	vitals, err := s.store.QueryVitals(ctx, VitalQuery{
+		PatientID: patientID,
+		TakenAt:   req.TakenAt,
+		Order:     req.Order,
+		After:     after,
+		Limit:     size + 1,
+	})
+	if err != nil {
+		return VitalPage{}, err
+	}
+	page := VitalPage{Vitals: vitals}
+	if len(vitals) > size {
+		page.Vitals = vitals[:size]
+		last := page.Vitals[size-1]
+		page.NextPageToken = encodePageToken(Cursor{TakenAt: last.TakenAt, ID: last.ID}, scope)
+	}
+	return page, nil
+}
+
+func (s *Service) ListAlerts(ctx context.Context, req ListAlertsRequest) (AlertPage, error) {
+	patientID := strings.TrimSpace(req.PatientID)
+	scope := pageScope("alerts", patientID, req.Statuses, req.TakenAt, req.Order)
+	size, after, err := pageParams(req.PageSize, req.PageToken, scope, req.TakenAt)
+	if err != nil {
+		return AlertPage{}, err
+	}
+	alerts, err := s.store.QueryAlerts(ctx, AlertQuery{
+		PatientID: patientID,
+		Statuses:  req.Statuses,
+		TakenAt:   req.TakenAt,
+		Order:     req.Order,
+		After:     after,
+		Limit:     size + 1,
+	})
+	if err != nil {
+		return AlertPage{}, err
+	}
+	page := AlertPage{Alerts: alerts}
+	if len(alerts) > size {
+		page.Alerts = alerts[:size]
+		last := page.Alerts[size-1]
+		page.NextPageToken = encodePageToken(Cursor{TakenAt: last.TakenAt, ID: last.ID}, scope)
+	}
+	return page, nil
+}
+
+func pageParams(requested int, token, scope string, r TimeRange) (int, *Cursor, error) {
+	size, err := pageSize(requested)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !r.After.IsZero() && !r.Before.IsZero() && !r.After.Before(r.Before) {
+		return 0, nil, fmt.Errorf("%w: taken_after must be before taken_before", ErrInvalidQuery)
+	}
+	after, err := decodePageToken(token, scope)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
+	return size, after, nil
+}
+
+func (s *Service) SetPatientThreshold(ctx context.Context, patientID string, thresholds Thresholds, setBy, reason string) (PatientThreshold, error) {
 	patientID = strings.TrimSpace(patientID)
+	setBy = strings.TrimSpace(setBy)
 	if patientID == "" {
-		return alerts, nil
+		return PatientThreshold{}, fmt.Errorf("%w: patient_id is required", ErrInvalidThreshold)
 	}
-	filtered := make([]Alert, 0, len(alerts))
-	for _, alert := range alerts {
-		if alert.PatientID == patientID {
-			filtered = append(filtered, alert)
-		}
+	if setBy == "" {
+		return PatientThreshold{}, fmt.Errorf("%w: set_by is required", ErrInvalidThreshold)
+	}
+	if err := validateThresholds(thresholds); err != nil {
+		return PatientThreshold{}, err
 	}
-	return filtered, nil
+	return s.store.SetPatientThreshold(ctx, PatientThreshold{
+		PatientID:  patientID,
+		Thresholds: thresholds,
+		SetBy:      setBy,
+		SetAt:      s.clock.Now().UTC(),
+		Reason:     strings.TrimSpace(reason),
+	})
 }
 
-func (s *Service) ListVitals(ctx context.Context, patientID string) ([]Vital, error) {
-	vitals, err := s.store.ListVitals(ctx)
+// GetPatientThreshold returns the patient's current override together with
+// every version recorded for them, oldest first.
+func (s *Service) GetPatientThreshold(ctx context.Context, patientID string) (PatientThreshold, []PatientThreshold, error) {
+	patientID = strings.TrimSpace(patientID)
+	if patientID == "" {
+		return PatientThreshold{}, nil, fmt.Errorf("%w: patient_id is required", ErrInvalidThreshold)
+	}
+	current, err := s.store.GetPatientThreshold(ctx, patientID)
+	if err != nil {
+		return PatientThreshold{}, nil, err
+	}
+	history, err := s.store.ListPatientThresholdHistory(ctx, patientID)
 	if err != nil {
-		return nil, err
+		return PatientThreshold{}, nil, err
 	}
+	return current, history, nil
+}
+
+func (s *Service) ListPatientThresholds(ctx context.Context) ([]PatientThreshold, error) {
+	return s.store.ListPatientThresholds(ctx)
+}
+
+func (s *Service) DeletePatientThreshold(ctx context.Context, patientID, deletedBy string) (PatientThreshold, error) {
 	patientID = strings.TrimSpace(patientID)
+	deletedBy = strings.TrimSpace(deletedBy)
 	if patientID == "" {
-		return vitals, nil
+		return PatientThreshold{}, fmt.Errorf("%w: patient_id is required", ErrInvalidThreshold)
 	}
-	filtered := make([]Vital, 0, len(vitals))
-	for _, vital := range vitals {
-		if vital.PatientID == patientID {
-			filtered = append(filtered, vital)
-		}
+	if deletedBy == "" {
+		return PatientThreshold{}, fmt.Errorf("%w: deleted_by is required", ErrInvalidThreshold)
+	}
+	return s.store.DeletePatientThreshold(ctx, patientID, deletedBy)
+}
+
+func validateThresholds(t Thresholds) error {
+	if t.IsZero() {
+		return fmt.Errorf("%w: at least one limit is required", ErrInvalidThreshold)
+	}
+	if t.MaxSystolic < 0 || t.MaxDiastolic < 0 || t.MinSystolic < 0 {
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidThreshold)
+	}
+	if t.MaxSystolic > 0 && t.MaxDiastolic > 0 && t.MaxDiastolic >= t.MaxSystolic {
+		return fmt.Errorf("%w: max_diastolic must be below max_systolic", ErrInvalidThreshold)
+	}
+	if t.MinSystolic > 0 && t.MaxSystolic > 0 && t.MinSystolic >= t.MaxSystolic {
+		return fmt.Errorf("%w: min_systolic must be below max_systolic", ErrInvalidThreshold)
 	}
-	return filtered, nil
+	return nil
 }
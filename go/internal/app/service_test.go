@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -11,14 +12,15 @@ import (
 func TestServiceIngestPublishesEvent(t *testing.T) {
 	store := NewInMemoryStore()
 	pubsub := NewPubSub()
-	service := NewService(store, pubsub)
+	relay := NewOutboxRelay(store, pubsub)
+	service := NewService(store, WithOutboxRelay(relay))
 
 	events, cancel := pubsub.Subscribe(1)
 	defer cancel()
 
 	ctx := context.Background()
 	takenAt := time.Now().Add(-2 * time.Minute)
-	stored, err := service.IngestVital(ctx, "patient-1", 120, 80, takenAt)
+	stored, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: takenAt})
 	if err != nil {
 		t.Fatalf("ingest failed: %v", err)
 	}
@@ -37,6 +39,10 @@ func TestServiceIngestPublishesEvent(t *testing.T) {
 		t.Fatalf("unexpected patient id: %s", vitals[0].PatientID)
 	}
 
+	relayCtx, relayCancel := context.WithCancel(ctx)
+	defer relayCancel()
+	go relay.Run(relayCtx)
+
 	select {
 	case event := <-events:
 		if event.Type != EventTypeVitalReceived {
@@ -52,28 +58,26 @@ func TestServiceIngestPublishesEvent(t *testing.T) {
 
 func TestServiceIngestValidatesInput(t *testing.T) {
 	store := NewInMemoryStore()
-	pubsub := NewPubSub()
-	service := NewService(store, pubsub)
+	service := NewService(store)
 
 	ctx := context.Background()
-	if _, err := service.IngestVital(ctx, "", 120, 80, time.Now()); err == nil {
+	if _, err := service.IngestVital(ctx, IngestVitalRequest{Systolic: 120, Diastolic: 80, TakenAt: time.Now()}); err == nil {
 		t.Fatal("expected error for missing patient id")
 	}
-	if _, err := service.IngestVital(ctx, "patient-1", -1, 80, time.Now()); err == nil {
+	if _, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Systolic: -1, Diastolic: 80, TakenAt: time.Now()}); err == nil {
 		t.Fatal("expected error for invalid systolic")
 	}
-	if _, err := service.IngestVital(ctx, "patient-1", 120, 0, time.Now()); err == nil {
+	if _, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Systolic: 120, Diastolic: 0, TakenAt: time.Now()}); err == nil {
 		t.Fatal("expected error for invalid diastolic")
 	}
-	if _, err := service.IngestVital(ctx, "patient-1", 120, 80, time.Time{}); err == nil {
+	if _, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Systolic: 120, Diastolic: 80}); err == nil {
 		t.Fatal("expected error for missing taken_at")
 	}
 }
 
 func TestServiceListAlertsFiltersByPatient(t *testing.T) {
 	store := NewInMemoryStore()
-	pubsub := NewPubSub()
-	service := NewService(store, pubsub)
+	service := NewService(store)
 
 	ctx := context.Background()
 	if _, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive}); err != nil {
@@ -83,10 +87,11 @@ func TestServiceListAlertsFiltersByPatient(t *testing.T) {
 		t.Fatalf("add alert: %v", err)
 	}
 
-	alerts, err := service.ListAlerts(ctx, "patient-1")
+	page, err := service.ListAlerts(ctx, ListAlertsRequest{PatientID: "patient-1"})
 	if err != nil {
 		t.Fatalf("list alerts failed: %v", err)
 	}
+	alerts := page.Alerts
 	if len(alerts) != 1 {
 		t.Fatalf("expected 1 alert, got %d", len(alerts))
 	}
@@ -95,6 +100,112 @@ func TestServiceListAlertsFiltersByPatient(t *testing.T) {
 	}
 }
 
+func TestServiceListVitalsPaginates(t *testing.T) {
+	store := NewInMemoryStore()
+	service := NewService(store)
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		if _, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: base.Add(time.Duration(i) * time.Hour)}); err != nil {
+			t.Fatalf("add vital: %v", err)
+		}
+	}
+	// Same taken_at as vital 3: ties are broken by id.
+	if _, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 130, Diastolic: 85, TakenAt: base.Add(2 * time.Hour)}); err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	if _, err := store.AddVital(ctx, Vital{PatientID: "patient-2", Systolic: 120, Diastolic: 80, TakenAt: base}); err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+
+	collect := func(req ListVitalsRequest) []int64 {
+		t.Helper()
+		var ids []int64
+		for {
+			page, err := service.ListVitals(ctx, req)
+			if err != nil {
+				t.Fatalf("list vitals: %v", err)
+			}
+			if len(page.Vitals) > req.PageSize {
+				t.Fatalf("page of %d exceeds page size %d", len(page.Vitals), req.PageSize)
+			}
+			for _, v := range page.Vitals {
+				ids = append(ids, v.ID)
+			}
+			if page.NextPageToken == "" {
+				return ids
+			}
+			req.PageToken = page.NextPageToken
+		}
+	}
+
+	if got := collect(ListVitalsRequest{PatientID: "patient-1", PageSize: 2}); !slices.Equal(got, []int64{1, 2, 3, 6, 4, 5}) {
+		t.Fatalf("unexpected ascending order: %v", got)
+	}
+	if got := collect(ListVitalsRequest{PatientID: "patient-1", PageSize: 2, Order: SortDescending}); !slices.Equal(got, []int64{5, 4, 6, 3, 2, 1}) {
+		t.Fatalf("unexpected descending order: %v", got)
+	}
+	window := TimeRange{After: base.Add(time.Hour), Before: base.Add(3 * time.Hour)}
+	if got := collect(ListVitalsRequest{PatientID: "patient-1", PageSize: 1, TakenAt: window}); !slices.Equal(got, []int64{2, 3, 6}) {
+		t.Fatalf("unexpected range result: %v", got)
+	}
+
+	if _, err := service.ListVitals(ctx, ListVitalsRequest{PageToken: "not a token"}); !errors.Is(err, ErrInvalidQuery) {
+		t.Fatalf("expected invalid query for bad token, got %v", err)
+	}
+	first, err := service.ListVitals(ctx, ListVitalsRequest{PatientID: "patient-1", PageSize: 2})
+	if err != nil || first.NextPageToken == "" {
+		t.Fatalf("list vitals: %+v (%v)", first, err)
+	}
+	for name, req := range map[string]ListVitalsRequest{
+		"order":   {PatientID: "patient-1", Order: SortDescending},
+		"patient": {PatientID: "patient-2"},
+		"range":   {PatientID: "patient-1", TakenAt: window},
+	} {
+		req.PageSize = 2
+		req.PageToken = first.NextPageToken
+		if _, err := service.ListVitals(ctx, req); !errors.Is(err, ErrInvalidQuery) {
+			t.Fatalf("expected invalid query for a token reused with a different %s, got %v", name, err)
+		}
+	}
+	if _, err := service.ListVitals(ctx, ListVitalsRequest{TakenAt: TimeRange{After: base, Before: base}}); !errors.Is(err, ErrInvalidQuery) {
+		t.Fatalf("expected invalid query for empty range, got %v", err)
+	}
+}
+
+func TestServiceListAlertsFiltersByStatus(t *testing.T) {
+	store := NewInMemoryStore()
+	service := NewService(store)
+	ctx := context.Background()
+
+	for _, status := range []AlertStatus{AlertStatusActive, AlertStatusAutoResolved, AlertStatusConfirmedAbnormal} {
+		if _, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: status, TakenAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("add alert: %v", err)
+		}
+	}
+
+	page, err := service.ListAlerts(ctx, ListAlertsRequest{Statuses: []AlertStatus{AlertStatusActive, AlertStatusConfirmedAbnormal}})
+	if err != nil {
+		t.Fatalf("list alerts: %v", err)
+	}
+	if len(page.Alerts) != 2 || page.Alerts[0].ID != 1 || page.Alerts[1].ID != 3 || page.NextPageToken != "" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+
+	first, err := service.ListAlerts(ctx, ListAlertsRequest{Statuses: []AlertStatus{AlertStatusActive, AlertStatusConfirmedAbnormal}, PageSize: 1})
+	if err != nil || first.NextPageToken == "" {
+		t.Fatalf("list alerts: %+v (%v)", first, err)
+	}
+	// Status order doesn't matter, but the set does.
+	if _, err := service.ListAlerts(ctx, ListAlertsRequest{Statuses: []AlertStatus{AlertStatusConfirmedAbnormal, AlertStatusActive}, PageSize: 1, PageToken: first.NextPageToken}); err != nil {
+		t.Fatalf("expected the same statuses in another order to accept the token, got %v", err)
+	}
+	if _, err := service.ListAlerts(ctx, ListAlertsRequest{Statuses: []AlertStatus{AlertStatusActive}, PageSize: 1, PageToken: first.NextPageToken}); !errors.Is(err, ErrInvalidQuery) {
+		t.Fatalf("expected invalid query for a token reused with other statuses, got %v", err)
+	}
+}
+
 type flakyPublisher struct {
 	calls int32
 }
@@ -110,20 +221,34 @@ func (p *flakyPublisher) Publish(ctx context.Context, _ Event) error {
 func TestServiceIngestRetryShouldBeIdempotentAfterPublishTimeout(t *testing.T) {
 	store := NewInMemoryStore()
 	pub := &flakyPublisher{}
-	service := NewService(store, pub)
+	relay := NewOutboxRelay(store, pub)
+	service := NewService(store, WithOutboxRelay(relay))
+
+	req := IngestVitalRequest{
+		PatientID:      "patient-1",
+		Systolic:       120,
+		Diastolic:      80,
+		TakenAt:        time.Now().UTC(),
+		IdempotencyKey: "reading-1",
+	}
+	first, err := service.IngestVital(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
 
 	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), 25*time.Millisecond)
 	defer timeoutCancel()
-
-	if _, err := service.IngestVital(timeoutCtx, "patient-1", 120, 80, time.Now().UTC()); err == nil {
-		t.Fatal("expected ingest to fail due to publish timeout")
-	} else if !errors.Is(err, context.DeadlineExceeded) {
+	if _, err := relay.Drain(timeoutCtx); !errors.Is(err, context.DeadlineExceeded) {
 		t.Fatalf("expected deadline exceeded error, got %v", err)
 	}
 
-	if _, err := service.IngestVital(context.Background(), "patient-1", 120, 80, time.Now().UTC()); err != nil {
+	retried, err := service.IngestVital(context.Background(), req)
+	if err != nil {
 		t.Fatalf("retry ingest failed: %v", err)
 	}
+	if retried.ID != first.ID {
+		t.Fatalf("expected retry to return vital %d, got %d", first.ID, retried.ID)
+	}
 
 	vitals, err := store.ListVitals(context.Background())
 	if err != nil {
@@ -132,4 +257,164 @@ func TestServiceIngestRetryShouldBeIdempotentAfterPublishTimeout(t *testing.T) {
 	if len(vitals) != 1 {
 		t.Fatalf("expected 1 vital after retry, got %d", len(vitals))
 	}
+
+	// The event that timed out is still pending and goes out exactly once.
+	delivered, err := relay.Drain(context.Background())
+	if err != nil || delivered != 1 {
+		t.Fatalf("expected 1 delivered event, got %d (%v)", delivered, err)
+	}
+	if pending, _ := store.ListPendingEvents(context.Background(), 0); len(pending) != 0 {
+		t.Fatalf("expected outbox to be empty, got %+v", pending)
+	}
+}
+
+func TestServiceIngestRejectsReusedIdempotencyKey(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	service := NewService(NewInMemoryStore(), WithServiceClock(clock), WithIdempotencyRetention(time.Hour))
+	ctx := context.Background()
+
+	req := IngestVitalRequest{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: clock.Now(), IdempotencyKey: "reading-1"}
+	first, err := service.IngestVital(ctx, req)
+	if err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
+
+	changed := req
+	changed.Systolic = 190
+	if _, err := service.IngestVital(ctx, changed); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("expected key reuse error, got %v", err)
+	}
+
+	clock.Advance(2 * time.Hour)
+	later, err := service.IngestVital(ctx, changed)
+	if err != nil {
+		t.Fatalf("ingest after retention failed: %v", err)
+	}
+	if later.ID == first.ID {
+		t.Fatal("expected a new vital once the key's retention has passed")
+	}
+}
+
+func TestServiceIngestVitalsPublishesInTakenAtOrder(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	relay := NewOutboxRelay(store, pubsub)
+	service := NewService(store, WithOutboxRelay(relay))
+	events, cancel := pubsub.Subscribe(8)
+	defer cancel()
+
+	ctx := context.Background()
+	base := time.Now().Add(-3 * time.Hour).UTC()
+	results, err := service.IngestVitals(ctx, []IngestVitalRequest{
+		{PatientID: "patient-1", Systolic: 150, Diastolic: 95, TakenAt: base.Add(2 * time.Hour)},
+		{PatientID: "patient-1", Systolic: 0, Diastolic: 80, TakenAt: base},
+		{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: base},
+		{PatientID: "patient-1", Systolic: 130, Diastolic: 85, TakenAt: base.Add(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("ingest batch: %v", err)
+	}
+	if len(results) != 4 || !errors.Is(results[1].Err, ErrInvalidVital) {
+		t.Fatalf("expected the invalid reading to be rejected in place, got %+v", results)
+	}
+	if results[0].Vital.Systolic != 150 || results[2].Vital.Systolic != 120 || results[3].Vital.Systolic != 130 {
+		t.Fatalf("results are not in request order: %+v", results)
+	}
+
+	if _, err := relay.Drain(ctx); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	for _, want := range []int32{120, 130, 150} {
+		if got := receive(t, events); got.Vital.Systolic != want {
+			t.Fatalf("expected the %d reading next, got %+v", want, got.Vital)
+		}
+	}
+
+	if _, err := service.IngestVitals(ctx, nil); !errors.Is(err, ErrInvalidVital) {
+		t.Fatalf("expected empty batch to be rejected, got %v", err)
+	}
+	if _, err := service.IngestVitals(ctx, make([]IngestVitalRequest, MaxIngestBatch+1)); !errors.Is(err, ErrInvalidVital) {
+		t.Fatalf("expected oversized batch to be rejected, got %v", err)
+	}
+}
+
+func TestServiceIngestChecksRangesPerKind(t *testing.T) {
+	store := NewInMemoryStore()
+	service := NewService(store)
+	ctx := context.Background()
+	takenAt := time.Now()
+
+	weight, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Kind: VitalKindWeight, Value: 180, Unit: "lb", TakenAt: takenAt})
+	if err != nil {
+		t.Fatalf("ingest weight: %v", err)
+	}
+	if weight.Value != 81.65 {
+		t.Fatalf("expected 180 lb to be stored as 81.65 kg, got %g", weight.Value)
+	}
+	if _, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Kind: VitalKindPulse, Value: 72, TakenAt: takenAt}); err != nil {
+		t.Fatalf("ingest pulse: %v", err)
+	}
+
+	invalid := []IngestVitalRequest{
+		{Kind: VitalKindPulse, Value: 400},
+		{Kind: VitalKindSpO2, Value: 101},
+		{Kind: VitalKindGlucose, Value: 5.5, Unit: "mg/dL"},
+		{Kind: VitalKindWeight, Value: 80, Unit: "stone"},
+		{Kind: VitalKindPulse, Value: 72, Systolic: 120, Diastolic: 80},
+		{Systolic: 350, Diastolic: 80},
+		{Systolic: 80, Diastolic: 120},
+		{Systolic: 120, Diastolic: 80, Value: 1},
+		{Kind: VitalKind(42), Value: 1},
+	}
+	for _, req := range invalid {
+		req.PatientID, req.TakenAt = "patient-1", takenAt
+		if _, err := service.IngestVital(ctx, req); !errors.Is(err, ErrInvalidVital) {
+			t.Fatalf("expected %+v to be rejected, got %v", req, err)
+		}
+	}
+	// mmol/L converts into range rather than being rejected as too low.
+	glucose, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Kind: VitalKindGlucose, Value: 5.5, Unit: "mmol/L", TakenAt: takenAt})
+	if err != nil || glucose.Value != 99.09 {
+		t.Fatalf("expected 5.5 mmol/L to be stored as 99.09 mg/dL, got %g (%v)", glucose.Value, err)
+	}
+}
+
+func TestServiceIngestReportsEveryViolation(t *testing.T) {
+	clock := newFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	service := NewService(NewInMemoryStore(), WithServiceClock(clock))
+	ctx := context.Background()
+
+	_, err := service.IngestVital(ctx, IngestVitalRequest{Systolic: 12000, Diastolic: 5, TakenAt: clock.Now().Add(time.Hour)})
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) || !errors.Is(err, ErrInvalidVital) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	var fields []string
+	for _, v := range invalid.Violations {
+		fields = append(fields, v.Field)
+	}
+	if !slices.Equal(fields, []string{"patient_id", "systolic", "diastolic", "taken_at"}) {
+		t.Fatalf("expected every field reported, got %+v", invalid.Violations)
+	}
+
+	old := IngestVitalRequest{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: clock.Now().AddDate(-2, 0, 0)}
+	if _, err := service.IngestVital(ctx, old); !errors.As(err, &invalid) || invalid.Violations[0].Field != "taken_at" {
+		t.Fatalf("expected a reading two years old to be rejected, got %v", err)
+	}
+	skewed := IngestVitalRequest{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: clock.Now().Add(2 * time.Minute)}
+	if _, err := service.IngestVital(ctx, skewed); err != nil {
+		t.Fatalf("expected small clock skew to be accepted, got %v", err)
+	}
+
+	narrow, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Systolic: 120, Diastolic: 115, TakenAt: clock.Now()})
+	if err != nil {
+		t.Fatalf("expected a narrow pulse pressure to be stored, got %v", err)
+	}
+	if !slices.Equal(narrow.Flags, []QualityFlag{QualityNarrowPulsePressure}) {
+		t.Fatalf("expected narrow pulse pressure flag, got %v", narrow.Flags)
+	}
+	pulse, err := service.IngestVital(ctx, IngestVitalRequest{PatientID: "patient-1", Kind: VitalKindPulse, Value: 25, TakenAt: clock.Now()})
+	if err != nil || !slices.Equal(pulse.Flags, []QualityFlag{QualityAtypicalValue}) {
+		t.Fatalf("expected an atypical pulse to be stored flagged, got %+v (%v)", pulse, err)
+	}
 }
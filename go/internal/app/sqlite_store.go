@@ -0,0 +1,896 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations are applied in order and recorded in schema_migrations.
+// Append new entries; never edit one that has shipped.
+var sqliteMigrations = []string{
+	`
+CREATE TABLE vitals (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	patient_id  TEXT    NOT NULL,
+	systolic    INTEGER NOT NULL,
+	diastolic   INTEGER NOT NULL,
+	taken_at    INTEGER NOT NULL,
+	received_at INTEGER NOT NULL
+);
+CREATE INDEX idx_vitals_patient_taken_at ON vitals (patient_id, taken_at);
+CREATE INDEX idx_vitals_taken_at ON vitals (taken_at);
+
+CREATE TABLE alerts (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	vital_id          INTEGER NOT NULL,
+	patient_id        TEXT    NOT NULL,
+	systolic          INTEGER NOT NULL,
+	diastolic         INTEGER NOT NULL,
+	taken_at          INTEGER NOT NULL,
+	received_at       INTEGER NOT NULL,
+	reason            TEXT    NOT NULL,
+	severity          INTEGER NOT NULL,
+	status            INTEGER NOT NULL,
+	created_at        INTEGER NOT NULL,
+	message_id        INTEGER NOT NULL,
+	vital_ids         TEXT    NOT NULL,
+	reading_count     INTEGER NOT NULL,
+	last_seen_at      INTEGER NOT NULL,
+	status_changed_at INTEGER NOT NULL,
+	status_vital_id   INTEGER NOT NULL,
+	threshold_version INTEGER NOT NULL
+);
+CREATE INDEX idx_alerts_patient_status ON alerts (patient_id, status);
+CREATE INDEX idx_alerts_taken_at ON alerts (taken_at);
+
+CREATE TABLE patient_thresholds (
+	patient_id    TEXT    NOT NULL,
+	version       INTEGER NOT NULL,
+	max_systolic  INTEGER NOT NULL,
+	max_diastolic INTEGER NOT NULL,
+	min_systolic  INTEGER NOT NULL,
+	set_by        TEXT    NOT NULL,
+	set_at        INTEGER NOT NULL,
+	reason        TEXT    NOT NULL,
+	deleted       INTEGER NOT NULL,
+	PRIMARY KEY (patient_id, version)
+);
+`,
+	`
+CREATE TABLE idempotency_keys (
+	key         TEXT    PRIMARY KEY,
+	fingerprint TEXT    NOT NULL,
+	vital_id    INTEGER NOT NULL REFERENCES vitals (id),
+	expires_at  INTEGER NOT NULL
+);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+`,
+	`
+CREATE TABLE outbox (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	type         TEXT    NOT NULL,
+	vital_id     INTEGER NOT NULL REFERENCES vitals (id),
+	created_at   INTEGER NOT NULL,
+	delivered_at INTEGER
+);
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE delivered_at IS NULL;
+`,
+	`
+CREATE INDEX idx_alerts_patient_taken_at ON alerts (patient_id, taken_at);
+`,
+	`
+ALTER TABLE vitals ADD COLUMN kind INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE vitals ADD COLUMN value REAL NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN kind INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN value REAL NOT NULL DEFAULT 0;
+`,
+	`
+ALTER TABLE vitals ADD COLUMN flags TEXT NOT NULL DEFAULT '';
+`,
+	`
+ALTER TABLE alerts ADD COLUMN acknowledged_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN acknowledged_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN acknowledge_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN resolved_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN resolved_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN resolution_note TEXT NOT NULL DEFAULT '';
+ALTER TABLE alerts ADD COLUMN resolution_reason INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE alerts ADD COLUMN revision INTEGER NOT NULL DEFAULT 1;
+`,
+	`
+ALTER TABLE alerts ADD COLUMN escalations TEXT NOT NULL DEFAULT '[]';
+`,
+	`
+CREATE TABLE alert_history (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	alert_id          INTEGER NOT NULL,
+	type              TEXT    NOT NULL,
+	at                INTEGER NOT NULL,
+	from_status       INTEGER NOT NULL,
+	status            INTEGER NOT NULL,
+	vital_id          INTEGER NOT NULL,
+	message_id        INTEGER NOT NULL,
+	recipient         INTEGER NOT NULL,
+	actor             TEXT    NOT NULL,
+	note              TEXT    NOT NULL,
+	resolution_reason INTEGER NOT NULL
+);
+CREATE INDEX idx_alert_history_alert ON alert_history (alert_id, id);
+`,
+	`
+CREATE TABLE idempotency_keys_scoped (
+	patient_id  TEXT    NOT NULL,
+	client_id   TEXT    NOT NULL,
+	key         TEXT    NOT NULL,
+	fingerprint TEXT    NOT NULL,
+	vital_id    INTEGER NOT NULL REFERENCES vitals (id),
+	expires_at  INTEGER NOT NULL,
+	PRIMARY KEY (patient_id, client_id, key)
+);
+INSERT INTO idempotency_keys_scoped (patient_id, client_id, key, fingerprint, vital_id, expires_at)
+	SELECT v.patient_id, '', k.key, k.fingerprint, k.vital_id, k.expires_at
+	FROM idempotency_keys k JOIN vitals v ON v.id = k.vital_id;
+DROP TABLE idempotency_keys;
+ALTER TABLE idempotency_keys_scoped RENAME TO idempotency_keys;
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+`,
+	`
+DELETE FROM outbox WHERE delivered_at IS NOT NULL;
+`,
+}
+
+// SQLiteStore is a durable Store backed by a single SQLite file. It uses a
+// pure-Go driver so the server still builds without cgo.
+type SQLiteStore struct {
+	db     *sql.DB
+	closed atomic.Bool
+}
+
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite %s: %w", path, err)
+	}
+	// SQLite allows a single writer; one connection keeps writes serialized
+	// and makes ":memory:" databases behave.
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStore{db: db}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+
+	for i := current; i < len(sqliteMigrations); i++ {
+		version := i + 1
+		err := s.withTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, sqliteMigrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().UTC().UnixNano())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("apply migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) check(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.closed.Load() {
+		return ErrStoreClosed
+	}
+	return nil
+}
+
+func (s *SQLiteStore) AddVital(ctx context.Context, vital Vital) (Vital, error) {
+	if err := s.check(ctx); err != nil {
+		return Vital{}, err
+	}
+	if vital.ReceivedAt.IsZero() {
+		vital.ReceivedAt = time.Now().UTC()
+	}
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		vital, err = insertVitalTx(ctx, tx, vital)
+		return err
+	})
+	if err != nil {
+		return Vital{}, err
+	}
+	return vital, nil
+}
+
+// insertVitalTx inserts vital together with its pending outbox event.
+func insertVitalTx(ctx context.Context, tx *sql.Tx, vital Vital) (Vital, error) {
+	res, err := tx.ExecContext(ctx, `INSERT INTO vitals (`+vitalColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		nullableID(vital.ID), vital.PatientID, vital.Kind, vital.Systolic, vital.Diastolic, vital.Value,
+		unixNanos(vital.TakenAt), unixNanos(vital.ReceivedAt), joinFlags(vital.Flags))
+	if err != nil {
+		return Vital{}, fmt.Errorf("insert vital: %w", err)
+	}
+	if vital.ID == 0 {
+		if vital.ID, err = res.LastInsertId(); err != nil {
+			return Vital{}, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO outbox (type, vital_id, created_at) VALUES (?, ?, ?)`,
+		string(EventTypeVitalReceived), vital.ID, unixNanos(vital.ReceivedAt)); err != nil {
+		return Vital{}, fmt.Errorf("insert outbox event: %w", err)
+	}
+	return vital, nil
+}
+
+func (s *SQLiteStore) AddVitalIdempotent(ctx context.Context, vital Vital, key IdempotencyKey) (Vital, bool, error) {
+	if err := s.check(ctx); err != nil {
+		return Vital{}, false, err
+	}
+	if vital.ReceivedAt.IsZero() {
+		vital.ReceivedAt = time.Now().UTC()
+	}
+	now := unixNanos(vital.ReceivedAt)
+	key.PatientID = vital.PatientID
+
+	var replayed bool
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= ?`, now); err != nil {
+			return fmt.Errorf("expire idempotency keys: %w", err)
+		}
+
+		var existing IdempotencyKey
+		err := tx.QueryRowContext(ctx, `SELECT fingerprint, vital_id FROM idempotency_keys WHERE patient_id = ? AND client_id = ? AND key = ?`,
+			key.PatientID, key.ClientID, key.Key).
+			Scan(&existing.Fingerprint, &existing.VitalID)
+		switch {
+		case err == nil:
+			if existing.Fingerprint != key.Fingerprint {
+				return ErrIdempotencyKeyReused
+			}
+			row := tx.QueryRowContext(ctx, `SELECT `+vitalColumns+` FROM vitals WHERE id = ?`, existing.VitalID)
+			if vital, err = scanVital(row); err != nil {
+				return err
+			}
+			replayed = true
+			return nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("lookup idempotency key: %w", err)
+		}
+
+		if vital, err = insertVitalTx(ctx, tx, vital); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO idempotency_keys (patient_id, client_id, key, fingerprint, vital_id, expires_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			key.PatientID, key.ClientID, key.Key, key.Fingerprint, vital.ID, unixNanos(key.ExpiresAt)); err != nil {
+			return fmt.Errorf("insert idempotency key: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return Vital{}, false, err
+	}
+	return vital, replayed, nil
+}
+
+func (s *SQLiteStore) AddAlert(ctx context.Context, alert Alert, history ...AlertHistoryEntry) (Alert, error) {
+	if err := s.check(ctx); err != nil {
+		return Alert{}, err
+	}
+	if alert.Created.IsZero() {
+		alert.Created = time.Now().UTC()
+	}
+	vitalIDs, err := json.Marshal(alert.VitalIDs)
+	if err != nil {
+		return Alert{}, err
+	}
+	escalations, err := json.Marshal(alert.Escalations)
+	if err != nil {
+		return Alert{}, err
+	}
+	alert.Revision = 1
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `INSERT INTO alerts (`+alertColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			nullableID(alert.ID), alert.VitalID, alert.PatientID, alert.Kind, alert.Systolic, alert.Diastolic, alert.Value,
+			unixNanos(alert.TakenAt), unixNanos(alert.ReceivedAt), alert.Reason, alert.Severity, alert.Status,
+			unixNanos(alert.Created), alert.MessageID, string(vitalIDs), alert.ReadingCount, unixNanos(alert.LastSeenAt),
+			unixNanos(alert.StatusChangedAt), alert.StatusVitalID, alert.ThresholdVersion,
+			alert.Acknowledgement.By, unixNanos(alert.Acknowledgement.At), alert.Acknowledgement.Note,
+			alert.Resolution.By, unixNanos(alert.Resolution.At), alert.Resolution.Note, alert.ResolutionReason,
+			string(escalations), alert.Revision)
+		if err != nil {
+			return fmt.Errorf("insert alert: %w", err)
+		}
+		if alert.ID == 0 {
+			if alert.ID, err = res.LastInsertId(); err != nil {
+				return err
+			}
+		}
+		return insertAlertHistory(ctx, tx, alert.ID, history)
+	})
+	if err != nil {
+		return Alert{}, err
+	}
+	return alert, nil
+}
+
+func (s *SQLiteStore) UpdateAlert(ctx context.Context, alert Alert, history ...AlertHistoryEntry) (Alert, error) {
+	if err := s.check(ctx); err != nil {
+		return Alert{}, err
+	}
+	vitalIDs, err := json.Marshal(alert.VitalIDs)
+	if err != nil {
+		return Alert{}, err
+	}
+	escalations, err := json.Marshal(alert.Escalations)
+	if err != nil {
+		return Alert{}, err
+	}
+	err = s.withTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE alerts SET
+		vital_id = ?, patient_id = ?, kind = ?, systolic = ?, diastolic = ?, value = ?, taken_at = ?, received_at = ?, reason = ?,
+		severity = ?, status = ?, created_at = ?, message_id = ?, vital_ids = ?, reading_count = ?,
+		last_seen_at = ?, status_changed_at = ?, status_vital_id = ?, threshold_version = ?,
+		acknowledged_by = ?, acknowledged_at = ?, acknowledge_note = ?,
+		resolved_by = ?, resolved_at = ?, resolution_note = ?, resolution_reason = ?, escalations = ?,
+		revision = revision + 1
+		WHERE id = ? AND revision = ?`,
+			alert.VitalID, alert.PatientID, alert.Kind, alert.Systolic, alert.Diastolic, alert.Value, unixNanos(alert.TakenAt),
+			unixNanos(alert.ReceivedAt), alert.Reason, alert.Severity, alert.Status, unixNanos(alert.Created),
+			alert.MessageID, string(vitalIDs), alert.ReadingCount, unixNanos(alert.LastSeenAt),
+			unixNanos(alert.StatusChangedAt), alert.StatusVitalID, alert.ThresholdVersion,
+			alert.Acknowledgement.By, unixNanos(alert.Acknowledgement.At), alert.Acknowledgement.Note,
+			alert.Resolution.By, unixNanos(alert.Resolution.At), alert.Resolution.Note, alert.ResolutionReason,
+			string(escalations), alert.ID, alert.Revision)
+		if err != nil {
+			return fmt.Errorf("update alert %d: %w", alert.ID, err)
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			var exists bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM alerts WHERE id = ?)`, alert.ID).Scan(&exists); err != nil {
+				return fmt.Errorf("check alert %d: %w", alert.ID, err)
+			}
+			if exists {
+				return ErrAlertConflict
+			}
+			return ErrAlertNotFound
+		}
+		return insertAlertHistory(ctx, tx, alert.ID, history)
+	})
+	if err != nil {
+		return Alert{}, err
+	}
+	alert.Revision++
+	return alert, nil
+}
+
+func (s *SQLiteStore) AppendAlertHistory(ctx context.Context, entries ...AlertHistoryEntry) error {
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	return s.withTx(ctx, func(tx *sql.Tx) error {
+		for _, entry := range entries {
+			if err := insertAlertHistory(ctx, tx, entry.AlertID, []AlertHistoryEntry{entry}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func insertAlertHistory(ctx context.Context, tx *sql.Tx, alertID int64, entries []AlertHistoryEntry) error {
+	for _, e := range entries {
+		_, err := tx.ExecContext(ctx, `INSERT INTO alert_history (`+alertHistoryColumns+`)
+			VALUES (NULL, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			alertID, e.Type, unixNanos(e.At), e.FromStatus, e.Status, e.VitalID, e.MessageID, e.Recipient,
+			e.Actor, e.Note, e.ResolutionReason)
+		if err != nil {
+			return fmt.Errorf("insert alert %d history: %w", alertID, err)
+		}
+	}
+	return nil
+}
+
+const alertHistoryColumns = `id, alert_id, type, at, from_status, status, vital_id, message_id, recipient, actor, note, resolution_reason`
+
+func (s *SQLiteStore) AlertHistory(ctx context.Context, alertID int64) ([]AlertHistoryEntry, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+alertHistoryColumns+` FROM alert_history WHERE alert_id = ? ORDER BY id`, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("query alert %d history: %w", alertID, err)
+	}
+	defer rows.Close()
+	var history []AlertHistoryEntry
+	for rows.Next() {
+		var (
+			e  AlertHistoryEntry
+			at int64
+		)
+		if err := rows.Scan(&e.ID, &e.AlertID, &e.Type, &at, &e.FromStatus, &e.Status, &e.VitalID, &e.MessageID,
+			&e.Recipient, &e.Actor, &e.Note, &e.ResolutionReason); err != nil {
+			return nil, fmt.Errorf("scan alert history: %w", err)
+		}
+		e.At = fromUnixNanos(at)
+		history = append(history, e)
+	}
+	return history, rows.Err()
+}
+
+func (s *SQLiteStore) GetAlert(ctx context.Context, id int64) (Alert, error) {
+	if err := s.check(ctx); err != nil {
+		return Alert{}, err
+	}
+	alert, err := scanAlert(s.db.QueryRowContext(ctx, `SELECT `+alertColumns+` FROM alerts WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Alert{}, ErrAlertNotFound
+	}
+	return alert, err
+}
+
+const alertColumns = `id, vital_id, patient_id, kind, systolic, diastolic, value, taken_at, received_at, reason, severity, status,
+	created_at, message_id, vital_ids, reading_count, last_seen_at, status_changed_at, status_vital_id, threshold_version,
+	acknowledged_by, acknowledged_at, acknowledge_note, resolved_by, resolved_at, resolution_note, resolution_reason,
+	escalations, revision`
+
+func (s *SQLiteStore) ListAlerts(ctx context.Context) ([]Alert, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+alertColumns+` FROM alerts ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []Alert{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func scanAlert(row interface{ Scan(...any) error }) (Alert, error) {
+	var (
+		alert                                                 Alert
+		takenAt, receivedAt, created, lastSeen, statusChanged int64
+		acknowledgedAt, resolvedAt                            int64
+		vitalIDs, escalations                                 string
+	)
+	err := row.Scan(&alert.ID, &alert.VitalID, &alert.PatientID, &alert.Kind, &alert.Systolic, &alert.Diastolic, &alert.Value,
+		&takenAt, &receivedAt, &alert.Reason, &alert.Severity, &alert.Status, &created, &alert.MessageID,
+		&vitalIDs, &alert.ReadingCount, &lastSeen, &statusChanged, &alert.StatusVitalID, &alert.ThresholdVersion,
+		&alert.Acknowledgement.By, &acknowledgedAt, &alert.Acknowledgement.Note,
+		&alert.Resolution.By, &resolvedAt, &alert.Resolution.Note, &alert.ResolutionReason,
+		&escalations, &alert.Revision)
+	if err != nil {
+		return Alert{}, fmt.Errorf("scan alert: %w", err)
+	}
+	alert.Acknowledgement.At = fromUnixNanos(acknowledgedAt)
+	alert.Resolution.At = fromUnixNanos(resolvedAt)
+	if err := json.Unmarshal([]byte(vitalIDs), &alert.VitalIDs); err != nil {
+		return Alert{}, fmt.Errorf("decode alert %d vital ids: %w", alert.ID, err)
+	}
+	if err := json.Unmarshal([]byte(escalations), &alert.Escalations); err != nil {
+		return Alert{}, fmt.Errorf("decode alert %d escalations: %w", alert.ID, err)
+	}
+	alert.TakenAt = fromUnixNanos(takenAt)
+	alert.ReceivedAt = fromUnixNanos(receivedAt)
+	alert.Created = fromUnixNanos(created)
+	alert.LastSeenAt = fromUnixNanos(lastSeen)
+	alert.StatusChangedAt = fromUnixNanos(statusChanged)
+	return alert, nil
+}
+
+func (s *SQLiteStore) ListVitals(ctx context.Context) ([]Vital, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+vitalColumns+` FROM vitals ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list vitals: %w", err)
+	}
+	defer rows.Close()
+
+	vitals := []Vital{}
+	for rows.Next() {
+		vital, err := scanVital(rows)
+		if err != nil {
+			return nil, err
+		}
+		vitals = append(vitals, vital)
+	}
+	return vitals, rows.Err()
+}
+
+func (s *SQLiteStore) QueryVitals(ctx context.Context, q VitalQuery) ([]Vital, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	f := newQueryFilter(q.PatientID, q.TakenAt, q.Order, q.After)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+vitalColumns+` FROM vitals`+f.clause(q.Order, q.Limit), f.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query vitals: %w", err)
+	}
+	defer rows.Close()
+
+	var vitals []Vital
+	for rows.Next() {
+		vital, err := scanVital(rows)
+		if err != nil {
+			return nil, err
+		}
+		vitals = append(vitals, vital)
+	}
+	return vitals, rows.Err()
+}
+
+func (s *SQLiteStore) QueryAlerts(ctx context.Context, q AlertQuery) ([]Alert, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	f := newQueryFilter(q.PatientID, q.TakenAt, q.Order, q.After)
+	if len(q.Statuses) > 0 {
+		placeholders := make([]string, len(q.Statuses))
+		args := make([]any, len(q.Statuses))
+		for i, status := range q.Statuses {
+			placeholders[i] = "?"
+			args[i] = status
+		}
+		f.add("status IN ("+strings.Join(placeholders, ", ")+")", args...)
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+alertColumns+` FROM alerts`+f.clause(q.Order, q.Limit), f.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []Alert
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+// queryFilter builds the WHERE/ORDER BY/LIMIT tail shared by the vital and
+// alert queries. Both tables key pages on (taken_at, id).
+type queryFilter struct {
+	where []string
+	args  []any
+}
+
+func newQueryFilter(patientID string, r TimeRange, order SortOrder, after *Cursor) *queryFilter {
+	f := &queryFilter{}
+	if patientID != "" {
+		f.add("patient_id = ?", patientID)
+	}
+	if !r.After.IsZero() {
+		f.add("taken_at >= ?", unixNanos(r.After))
+	}
+	if !r.Before.IsZero() {
+		f.add("taken_at < ?", unixNanos(r.Before))
+	}
+	if after != nil {
+		op := ">"
+		if order == SortDescending {
+			op = "<"
+		}
+		at := unixNanos(after.TakenAt)
+		f.add("(taken_at "+op+" ? OR (taken_at = ? AND id "+op+" ?))", at, at, after.ID)
+	}
+	return f
+}
+
+func (f *queryFilter) add(clause string, args ...any) {
+	f.where = append(f.where, clause)
+	f.args = append(f.args, args...)
+}
+
+func (f *queryFilter) clause(order SortOrder, limit int) string {
+	var b strings.Builder
+	if len(f.where) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(f.where, " AND "))
+	}
+	dir := "ASC"
+	if order == SortDescending {
+		dir = "DESC"
+	}
+	fmt.Fprintf(&b, " ORDER BY taken_at %s, id %s", dir, dir)
+	if limit > 0 {
+		fmt.Fprintf(&b, " LIMIT %d", limit)
+	}
+	return b.String()
+}
+
+const vitalColumns = `id, patient_id, kind, systolic, diastolic, value, taken_at, received_at, flags`
+
+func scanVital(row interface{ Scan(...any) error }) (Vital, error) {
+	var (
+		vital               Vital
+		takenAt, receivedAt int64
+		flags               string
+	)
+	if err := row.Scan(&vital.ID, &vital.PatientID, &vital.Kind, &vital.Systolic, &vital.Diastolic, &vital.Value, &takenAt, &receivedAt, &flags); err != nil {
+		return Vital{}, fmt.Errorf("scan vital: %w", err)
+	}
+	vital.TakenAt = fromUnixNanos(takenAt)
+	vital.ReceivedAt = fromUnixNanos(receivedAt)
+	vital.Flags = splitFlags(flags)
+	return vital, nil
+}
+
+func joinFlags(flags []QualityFlag) string {
+	names := make([]string, len(flags))
+	for i, flag := range flags {
+		names[i] = string(flag)
+	}
+	return strings.Join(names, ",")
+}
+
+func splitFlags(s string) []QualityFlag {
+	if s == "" {
+		return nil
+	}
+	var flags []QualityFlag
+	for name := range strings.SplitSeq(s, ",") {
+		flags = append(flags, QualityFlag(name))
+	}
+	return flags
+}
+
+func (s *SQLiteStore) SetPatientThreshold(ctx context.Context, threshold PatientThreshold) (PatientThreshold, error) {
+	if err := s.check(ctx); err != nil {
+		return PatientThreshold{}, err
+	}
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		threshold, err = appendThresholdTx(ctx, tx, threshold)
+		return err
+	})
+	if err != nil {
+		return PatientThreshold{}, err
+	}
+	return threshold, nil
+}
+
+func (s *SQLiteStore) GetPatientThreshold(ctx context.Context, patientID string) (PatientThreshold, error) {
+	if err := s.check(ctx); err != nil {
+		return PatientThreshold{}, err
+	}
+	row := s.db.QueryRowContext(ctx, `SELECT `+thresholdColumns+` FROM patient_thresholds
+		WHERE patient_id = ? ORDER BY version DESC LIMIT 1`, patientID)
+	threshold, err := scanThreshold(row)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && threshold.Deleted) {
+		return PatientThreshold{}, ErrThresholdNotFound
+	}
+	if err != nil {
+		return PatientThreshold{}, err
+	}
+	return threshold, nil
+}
+
+func (s *SQLiteStore) ListPatientThresholds(ctx context.Context) ([]PatientThreshold, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+thresholdColumns+` FROM patient_thresholds t
+		WHERE version = (SELECT MAX(version) FROM patient_thresholds WHERE patient_id = t.patient_id)
+		AND deleted = 0
+		ORDER BY patient_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list thresholds: %w", err)
+	}
+	return collectThresholds(rows)
+}
+
+func (s *SQLiteStore) ListPatientThresholdHistory(ctx context.Context, patientID string) ([]PatientThreshold, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT `+thresholdColumns+` FROM patient_thresholds
+		WHERE patient_id = ? ORDER BY version`, patientID)
+	if err != nil {
+		return nil, fmt.Errorf("list threshold history: %w", err)
+	}
+	return collectThresholds(rows)
+}
+
+func (s *SQLiteStore) DeletePatientThreshold(ctx context.Context, patientID, deletedBy string) (PatientThreshold, error) {
+	if err := s.check(ctx); err != nil {
+		return PatientThreshold{}, err
+	}
+	var tombstone PatientThreshold
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+		var deleted bool
+		err := tx.QueryRowContext(ctx, `SELECT deleted FROM patient_thresholds
+			WHERE patient_id = ? ORDER BY version DESC LIMIT 1`, patientID).Scan(&deleted)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && deleted) {
+			return ErrThresholdNotFound
+		}
+		if err != nil {
+			return err
+		}
+		tombstone, err = appendThresholdTx(ctx, tx, PatientThreshold{PatientID: patientID, SetBy: deletedBy, Deleted: true})
+		return err
+	})
+	if err != nil {
+		return PatientThreshold{}, err
+	}
+	return tombstone, nil
+}
+
+const thresholdColumns = `patient_id, version, max_systolic, max_diastolic, min_systolic, set_by, set_at, reason, deleted`
+
+func appendThresholdTx(ctx context.Context, tx *sql.Tx, threshold PatientThreshold) (PatientThreshold, error) {
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) + 1 FROM patient_thresholds WHERE patient_id = ?`,
+		threshold.PatientID).Scan(&threshold.Version); err != nil {
+		return PatientThreshold{}, err
+	}
+	if threshold.SetAt.IsZero() {
+		threshold.SetAt = time.Now().UTC()
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO patient_thresholds (`+thresholdColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		threshold.PatientID, threshold.Version, threshold.Thresholds.MaxSystolic, threshold.Thresholds.MaxDiastolic,
+		threshold.Thresholds.MinSystolic, threshold.SetBy, unixNanos(threshold.SetAt), threshold.Reason, threshold.Deleted)
+	if err != nil {
+		return PatientThreshold{}, fmt.Errorf("insert threshold: %w", err)
+	}
+	return threshold, nil
+}
+
+func scanThreshold(row interface{ Scan(...any) error }) (PatientThreshold, error) {
+	var (
+		threshold PatientThreshold
+		setAt     int64
+	)
+	err := row.Scan(&threshold.PatientID, &threshold.Version, &threshold.Thresholds.MaxSystolic,
+		&threshold.Thresholds.MaxDiastolic, &threshold.Thresholds.MinSystolic, &threshold.SetBy, &setAt,
+		&threshold.Reason, &threshold.Deleted)
+	if err != nil {
+		return PatientThreshold{}, err
+	}
+	threshold.SetAt = fromUnixNanos(setAt)
+	return threshold, nil
+}
+
+func collectThresholds(rows *sql.Rows) ([]PatientThreshold, error) {
+	defer rows.Close()
+	var thresholds []PatientThreshold
+	for rows.Next() {
+		threshold, err := scanThreshold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan threshold: %w", err)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, rows.Err()
+}
+
+func (s *SQLiteStore) ListPendingEvents(ctx context.Context, limit int) ([]OutboxEvent, error) {
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT o.id, o.type, o.created_at, v.id, v.patient_id, v.kind, v.systolic, v.diastolic, v.value, v.taken_at, v.received_at, v.flags
+		FROM outbox o JOIN vitals v ON v.id = o.vital_id
+		WHERE o.delivered_at IS NULL ORDER BY o.id LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list outbox: %w", err)
+	}
+	defer rows.Close()
+	var pending []OutboxEvent
+	for rows.Next() {
+		var (
+			event                        OutboxEvent
+			eventType                    string
+			createdAt, takenAt, received int64
+			flags                        string
+		)
+		vital := &event.Event.Vital
+		if err := rows.Scan(&event.ID, &eventType, &createdAt, &vital.ID, &vital.PatientID, &vital.Kind, &vital.Systolic, &vital.Diastolic, &vital.Value, &takenAt, &received, &flags); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		event.Event.Type = EventType(eventType)
+		event.CreatedAt = fromUnixNanos(createdAt)
+		vital.TakenAt = fromUnixNanos(takenAt)
+		vital.ReceivedAt = fromUnixNanos(received)
+		vital.Flags = splitFlags(flags)
+		pending = append(pending, event)
+	}
+	return pending, rows.Err()
+}
+
+// MarkEventDelivered deletes the event, as the in-memory store does, so the
+// outbox only ever holds pending events. AUTOINCREMENT keeps deleted IDs
+// from being reused.
+func (s *SQLiteStore) MarkEventDelivered(ctx context.Context, id int64) error {
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM outbox WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("mark outbox event %d delivered: %w", id, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() {
+	if s.closed.Swap(true) {
+		return
+	}
+	s.db.Close()
+}
+
+// nullableID lets SQLite assign the row ID when the caller didn't.
+func nullableID(id int64) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+func unixNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNanos(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n).UTC()
+}
@@ -3,17 +3,62 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"sort"
 	"sync"
 	"time"
 )
 
-var ErrStoreClosed = errors.New("store is closed")
+var (
+	ErrStoreClosed   = errors.New("store is closed")
+	ErrAlertNotFound = errors.New("alert not found")
+	// ErrAlertConflict means the alert changed since it was read; reload it
+	// and reapply the change.
+	ErrAlertConflict = errors.New("alert was modified concurrently")
+)
 
 type Store interface {
+	// AddVital stores vital and, in the same transaction, a pending
+	// VITAL_RECEIVED outbox event for it.
 	AddVital(ctx context.Context, vital Vital) (Vital, error)
-	AddAlert(ctx context.Context, alert Alert) (Alert, error)
+	// AddVitalIdempotent stores vital under key unless an unexpired key with
+	// the same name, client and patient exists, in which case it returns the
+	// vital that key created and replayed=true. The key's PatientID is
+	// vital's. Expiry is judged against vital.ReceivedAt.
+	AddVitalIdempotent(ctx context.Context, vital Vital, key IdempotencyKey) (stored Vital, replayed bool, err error)
+	// AddAlert and UpdateAlert append history to the alert's timeline in
+	// the same transaction; the store fills in each entry's ID and AlertID.
+	AddAlert(ctx context.Context, alert Alert, history ...AlertHistoryEntry) (Alert, error)
+	// UpdateAlert replaces the alert if its Revision matches the stored one,
+	// returning it with Revision bumped; otherwise it fails with
+	// ErrAlertConflict.
+	UpdateAlert(ctx context.Context, alert Alert, history ...AlertHistoryEntry) (Alert, error)
+	GetAlert(ctx context.Context, id int64) (Alert, error)
+	// AppendAlertHistory records entries that don't change the alert
+	// itself, such as its notifications being sent.
+	AppendAlertHistory(ctx context.Context, entries ...AlertHistoryEntry) error
+	// AlertHistory returns an alert's timeline, oldest first.
+	AlertHistory(ctx context.Context, alertID int64) ([]AlertHistoryEntry, error)
 	ListAlerts(ctx context.Context) ([]Alert, error)
 	ListVitals(ctx context.Context) ([]Vital, error)
+	// QueryVitals and QueryAlerts return matches sorted by (TakenAt, ID) in
+	// the query's order, starting after its cursor.
+	QueryVitals(ctx context.Context, q VitalQuery) ([]Vital, error)
+	QueryAlerts(ctx context.Context, q AlertQuery) ([]Alert, error)
+
+	SetPatientThreshold(ctx context.Context, threshold PatientThreshold) (PatientThreshold, error)
+	GetPatientThreshold(ctx context.Context, patientID string) (PatientThreshold, error)
+	ListPatientThresholds(ctx context.Context) ([]PatientThreshold, error)
+	ListPatientThresholdHistory(ctx context.Context, patientID string) ([]PatientThreshold, error)
+	DeletePatientThreshold(ctx context.Context, patientID, deletedBy string) (PatientThreshold, error)
+
+	// ListPendingEvents returns up to limit undelivered outbox events,
+	// oldest first.
+	ListPendingEvents(ctx context.Context, limit int) ([]OutboxEvent, error)
+	// MarkEventDelivered is a no-op for events already marked.
+	MarkEventDelivered(ctx context.Context, id int64) error
+
 	Close()
 }
 
@@ -24,10 +69,30 @@ type InMemoryStore struct {
 	alertSeq int64
 	vitals   []Vital
 	alerts   []Alert
+
+	historySeq int64
+	history    map[int64][]AlertHistoryEntry
+
+	// thresholds holds every version of each patient's override, oldest first.
+	thresholds map[string][]PatientThreshold
+
+	keys         map[string]IdempotencyKey
+	lastKeySweep time.Time
+
+	// outbox holds only pending events; delivered ones are dropped.
+	outboxSeq int64
+	outbox    []OutboxEvent
+
+	// journal is nil unless the store was opened with OpenJournaledStore.
+	journal *journal
 }
 
 func NewInMemoryStore() *InMemoryStore {
-	return &InMemoryStore{}
+	return &InMemoryStore{
+		thresholds: make(map[string][]PatientThreshold),
+		keys:       make(map[string]IdempotencyKey),
+		history:    make(map[int64][]AlertHistoryEntry),
+	}
 }
 
 func (s *InMemoryStore) AddVital(ctx context.Context, vital Vital) (Vital, error) {
@@ -43,17 +108,82 @@ func (s *InMemoryStore) AddVital(ctx context.Context, vital Vital) (Vital, error
 		return Vital{}, ErrStoreClosed
 	}
 	if vital.ID == 0 {
-		s.vitalSeq++
-		vital.ID = s.vitalSeq
+		vital.ID = s.vitalSeq + 1
 	}
 	if vital.ReceivedAt.IsZero() {
 		vital.ReceivedAt = time.Now().UTC()
 	}
-	s.vitals = append(s.vitals, vital)
+	rec := journalRecord{Op: opAddVital, Vital: &vital, Outbox: s.vitalEventLocked(vital)}
+	if err := s.commitLocked(rec); err != nil {
+		return Vital{}, err
+	}
 	return vital, nil
 }
 
-func (s *InMemoryStore) AddAlert(ctx context.Context, alert Alert) (Alert, error) {
+func (s *InMemoryStore) AddVitalIdempotent(ctx context.Context, vital Vital, key IdempotencyKey) (Vital, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return Vital{}, false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return Vital{}, false, err
+	}
+	if s.closed {
+		return Vital{}, false, ErrStoreClosed
+	}
+	if vital.ReceivedAt.IsZero() {
+		vital.ReceivedAt = time.Now().UTC()
+	}
+	s.sweepKeysLocked(vital.ReceivedAt)
+	key.PatientID = vital.PatientID
+
+	if existing, ok := s.keys[key.scope()]; ok && !existing.Expired(vital.ReceivedAt) {
+		if existing.Fingerprint != key.Fingerprint {
+			return Vital{}, false, ErrIdempotencyKeyReused
+		}
+		for _, v := range s.vitals {
+			if v.ID == existing.VitalID {
+				return v, true, nil
+			}
+		}
+		return Vital{}, false, fmt.Errorf("idempotency key %q points at missing vital %d", key.Key, existing.VitalID)
+	}
+
+	if vital.ID == 0 {
+		vital.ID = s.vitalSeq + 1
+	}
+	key.VitalID = vital.ID
+	rec := journalRecord{Op: opAddVital, Vital: &vital, Key: &key, Outbox: s.vitalEventLocked(vital)}
+	if err := s.commitLocked(rec); err != nil {
+		return Vital{}, false, err
+	}
+	return vital, false, nil
+}
+
+func (s *InMemoryStore) vitalEventLocked(vital Vital) *OutboxEvent {
+	return &OutboxEvent{
+		ID:        s.outboxSeq + 1,
+		Event:     Event{Type: EventTypeVitalReceived, Vital: vital},
+		CreatedAt: vital.ReceivedAt,
+	}
+}
+
+// sweepKeysLocked drops expired idempotency keys, at most once a minute so
+// busy ingestion doesn't rescan the map on every call.
+func (s *InMemoryStore) sweepKeysLocked(now time.Time) {
+	if now.Sub(s.lastKeySweep) < time.Minute {
+		return
+	}
+	s.lastKeySweep = now
+	for name, key := range s.keys {
+		if key.Expired(now) {
+			delete(s.keys, name)
+		}
+	}
+}
+
+func (s *InMemoryStore) AddAlert(ctx context.Context, alert Alert, history ...AlertHistoryEntry) (Alert, error) {
 	if err := ctx.Err(); err != nil {
 		return Alert{}, err
 	}
@@ -66,16 +196,112 @@ func (s *InMemoryStore) AddAlert(ctx context.Context, alert Alert) (Alert, error
 		return Alert{}, ErrStoreClosed
 	}
 	if alert.ID == 0 {
-		s.alertSeq++
-		alert.ID = s.alertSeq
+		alert.ID = s.alertSeq + 1
 	}
 	if alert.Created.IsZero() {
 		alert.Created = time.Now().UTC()
 	}
-	s.alerts = append(s.alerts, alert)
+	alert.Revision = 1
+	rec := journalRecord{Op: opAddAlert, Alert: &alert, History: s.historyLocked(alert.ID, history)}
+	if err := s.commitLocked(rec); err != nil {
+		return Alert{}, err
+	}
+	return alert, nil
+}
+
+func (s *InMemoryStore) UpdateAlert(ctx context.Context, alert Alert, history ...AlertHistoryEntry) (Alert, error) {
+	if err := ctx.Err(); err != nil {
+		return Alert{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return Alert{}, err
+	}
+	if s.closed {
+		return Alert{}, ErrStoreClosed
+	}
+	i := s.alertIndexLocked(alert.ID)
+	if i < 0 {
+		return Alert{}, ErrAlertNotFound
+	}
+	if s.alerts[i].Revision != alert.Revision {
+		return Alert{}, ErrAlertConflict
+	}
+	alert.Revision++
+	rec := journalRecord{Op: opUpdateAlert, Alert: &alert, History: s.historyLocked(alert.ID, history)}
+	if err := s.commitLocked(rec); err != nil {
+		return Alert{}, err
+	}
 	return alert, nil
 }
 
+func (s *InMemoryStore) AppendAlertHistory(ctx context.Context, entries ...AlertHistoryEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.closed {
+		return ErrStoreClosed
+	}
+	history := make([]AlertHistoryEntry, len(entries))
+	for i, entry := range entries {
+		entry.ID = s.historySeq + int64(i) + 1
+		history[i] = entry
+	}
+	return s.commitLocked(journalRecord{Op: opAppendHistory, History: history})
+}
+
+// historyLocked numbers entries for alertID, continuing the store's
+// sequence; applyLocked advances the sequence once they are committed.
+func (s *InMemoryStore) historyLocked(alertID int64, entries []AlertHistoryEntry) []AlertHistoryEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+	history := make([]AlertHistoryEntry, len(entries))
+	for i, entry := range entries {
+		entry.ID = s.historySeq + int64(i) + 1
+		entry.AlertID = alertID
+		history[i] = entry
+	}
+	return history
+}
+
+func (s *InMemoryStore) AlertHistory(ctx context.Context, alertID int64) ([]AlertHistoryEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+	return append([]AlertHistoryEntry(nil), s.history[alertID]...), nil
+}
+
+func (s *InMemoryStore) GetAlert(ctx context.Context, id int64) (Alert, error) {
+	if err := ctx.Err(); err != nil {
+		return Alert{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return Alert{}, err
+	}
+	if s.closed {
+		return Alert{}, ErrStoreClosed
+	}
+	i := s.alertIndexLocked(id)
+	if i < 0 {
+		return Alert{}, ErrAlertNotFound
+	}
+	return cloneAlert(s.alerts[i]), nil
+}
+
 func (s *InMemoryStore) ListAlerts(ctx context.Context) ([]Alert, error) {
 	if err := ctx.Err(); err != nil {
 		return nil, err
@@ -89,7 +315,9 @@ func (s *InMemoryStore) ListAlerts(ctx context.Context) ([]Alert, error) {
 		return nil, ErrStoreClosed
 	}
 	alerts := make([]Alert, len(s.alerts))
-	copy(alerts, s.alerts)
+	for i, alert := range s.alerts {
+		alerts[i] = cloneAlert(alert)
+	}
 	return alerts, nil
 }
 
@@ -110,10 +338,316 @@ func (s *InMemoryStore) ListVitals(ctx context.Context) ([]Vital, error) {
 	return vitals, nil
 }
 
+func (s *InMemoryStore) QueryVitals(ctx context.Context, q VitalQuery) ([]Vital, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+	var vitals []Vital
+	for _, v := range s.vitals {
+		if q.matches(v) {
+			vitals = append(vitals, v)
+		}
+	}
+	sort.Slice(vitals, func(i, j int) bool {
+		return q.Order.less(vitals[i].TakenAt, vitals[i].ID, vitals[j].TakenAt, vitals[j].ID)
+	})
+	if q.Limit > 0 && len(vitals) > q.Limit {
+		vitals = vitals[:q.Limit]
+	}
+	return vitals, nil
+}
+
+func (s *InMemoryStore) QueryAlerts(ctx context.Context, q AlertQuery) ([]Alert, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+	var alerts []Alert
+	for _, a := range s.alerts {
+		if q.matches(a) {
+			alerts = append(alerts, a)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return q.Order.less(alerts[i].TakenAt, alerts[i].ID, alerts[j].TakenAt, alerts[j].ID)
+	})
+	if q.Limit > 0 && len(alerts) > q.Limit {
+		alerts = alerts[:q.Limit]
+	}
+	for i := range alerts {
+		alerts[i] = cloneAlert(alerts[i])
+	}
+	return alerts, nil
+}
+
+// SetPatientThreshold stores threshold as the patient's newest version,
+// assigning the version number and timestamp.
+func (s *InMemoryStore) SetPatientThreshold(ctx context.Context, threshold PatientThreshold) (PatientThreshold, error) {
+	if err := ctx.Err(); err != nil {
+		return PatientThreshold{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return PatientThreshold{}, err
+	}
+	if s.closed {
+		return PatientThreshold{}, ErrStoreClosed
+	}
+	return s.appendThresholdLocked(threshold)
+}
+
+func (s *InMemoryStore) GetPatientThreshold(ctx context.Context, patientID string) (PatientThreshold, error) {
+	if err := ctx.Err(); err != nil {
+		return PatientThreshold{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return PatientThreshold{}, err
+	}
+	if s.closed {
+		return PatientThreshold{}, ErrStoreClosed
+	}
+	versions := s.thresholds[patientID]
+	if len(versions) == 0 || versions[len(versions)-1].Deleted {
+		return PatientThreshold{}, ErrThresholdNotFound
+	}
+	return versions[len(versions)-1], nil
+}
+
+func (s *InMemoryStore) ListPatientThresholds(ctx context.Context) ([]PatientThreshold, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+	var current []PatientThreshold
+	for _, versions := range s.thresholds {
+		if latest := versions[len(versions)-1]; !latest.Deleted {
+			current = append(current, latest)
+		}
+	}
+	sort.Slice(current, func(i, j int) bool {
+		return current[i].PatientID < current[j].PatientID
+	})
+	return current, nil
+}
+
+func (s *InMemoryStore) ListPatientThresholdHistory(ctx context.Context, patientID string) ([]PatientThreshold, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+	versions := make([]PatientThreshold, len(s.thresholds[patientID]))
+	copy(versions, s.thresholds[patientID])
+	return versions, nil
+}
+
+// DeletePatientThreshold records a tombstone version so the removal is
+// versioned like any other change.
+func (s *InMemoryStore) DeletePatientThreshold(ctx context.Context, patientID, deletedBy string) (PatientThreshold, error) {
+	if err := ctx.Err(); err != nil {
+		return PatientThreshold{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return PatientThreshold{}, err
+	}
+	if s.closed {
+		return PatientThreshold{}, ErrStoreClosed
+	}
+	versions := s.thresholds[patientID]
+	if len(versions) == 0 || versions[len(versions)-1].Deleted {
+		return PatientThreshold{}, ErrThresholdNotFound
+	}
+	return s.appendThresholdLocked(PatientThreshold{PatientID: patientID, SetBy: deletedBy, Deleted: true})
+}
+
+func (s *InMemoryStore) ListPendingEvents(ctx context.Context, limit int) ([]OutboxEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.closed {
+		return nil, ErrStoreClosed
+	}
+	n := len(s.outbox)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	pending := make([]OutboxEvent, n)
+	copy(pending, s.outbox)
+	return pending, nil
+}
+
+func (s *InMemoryStore) MarkEventDelivered(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.closed {
+		return ErrStoreClosed
+	}
+	if s.outboxIndexLocked(id) < 0 {
+		return nil
+	}
+	return s.commitLocked(journalRecord{Op: opMarkDelivered, EventID: id})
+}
+
+func (s *InMemoryStore) appendThresholdLocked(threshold PatientThreshold) (PatientThreshold, error) {
+	threshold.Version = int64(len(s.thresholds[threshold.PatientID])) + 1
+	if threshold.SetAt.IsZero() {
+		threshold.SetAt = time.Now().UTC()
+	}
+	if err := s.commitLocked(journalRecord{Op: opSetThreshold, Threshold: &threshold}); err != nil {
+		return PatientThreshold{}, err
+	}
+	return threshold, nil
+}
+
+// commitLocked makes a mutation durable (when journaling) and then applies
+// it. Every write goes through here so replay rebuilds exactly the same
+// state the live path produced.
+func (s *InMemoryStore) commitLocked(rec journalRecord) error {
+	if s.journal != nil {
+		if err := s.journal.append(&rec); err != nil {
+			return err
+		}
+	}
+	s.applyLocked(rec)
+	if s.journal != nil && s.journal.snapshotDue() {
+		if err := s.journal.snapshot(s.snapshotLocked()); err != nil {
+			log.Printf("store snapshot failed: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *InMemoryStore) applyLocked(rec journalRecord) {
+	switch rec.Op {
+	case opAddVital:
+		s.vitals = append(s.vitals, *rec.Vital)
+		s.vitalSeq = max(s.vitalSeq, rec.Vital.ID)
+		if rec.Key != nil {
+			key := *rec.Key
+			// Records journaled before keys were scoped carry no patient.
+			key.PatientID = rec.Vital.PatientID
+			s.keys[key.scope()] = key
+		}
+		if rec.Outbox != nil {
+			s.outbox = append(s.outbox, *rec.Outbox)
+			s.outboxSeq = max(s.outboxSeq, rec.Outbox.ID)
+		}
+	case opAddAlert:
+		s.alerts = append(s.alerts, cloneAlert(*rec.Alert))
+		s.alertSeq = max(s.alertSeq, rec.Alert.ID)
+		s.appendHistoryLocked(rec.History)
+	case opUpdateAlert:
+		if i := s.alertIndexLocked(rec.Alert.ID); i >= 0 {
+			s.alerts[i] = cloneAlert(*rec.Alert)
+		}
+		s.appendHistoryLocked(rec.History)
+	case opAppendHistory:
+		s.appendHistoryLocked(rec.History)
+	case opSetThreshold:
+		t := *rec.Threshold
+		s.thresholds[t.PatientID] = append(s.thresholds[t.PatientID], t)
+	case opMarkDelivered:
+		if i := s.outboxIndexLocked(rec.EventID); i >= 0 {
+			s.outbox = append(s.outbox[:i], s.outbox[i+1:]...)
+		}
+	}
+}
+
+func (s *InMemoryStore) alertIndexLocked(id int64) int {
+	for i := range s.alerts {
+		if s.alerts[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *InMemoryStore) outboxIndexLocked(id int64) int {
+	for i := range s.outbox {
+		if s.outbox[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
 func (s *InMemoryStore) Close() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if s.journal != nil {
+		if err := s.journal.snapshot(s.snapshotLocked()); err != nil {
+			log.Printf("store snapshot on close failed: %v", err)
+		}
+		s.journal.close()
+	}
 	s.closed = true
 	s.vitals = nil
 	s.alerts = nil
+	s.thresholds = nil
+	s.keys = nil
+	s.outbox = nil
+}
+
+func (s *InMemoryStore) appendHistoryLocked(entries []AlertHistoryEntry) {
+	for _, entry := range entries {
+		s.history[entry.AlertID] = append(s.history[entry.AlertID], entry)
+		s.historySeq = max(s.historySeq, entry.ID)
+	}
+}
+
+func cloneAlert(alert Alert) Alert {
+	if alert.VitalIDs != nil {
+		alert.VitalIDs = append([]int64(nil), alert.VitalIDs...)
+	}
+	if alert.Escalations != nil {
+		alert.Escalations = append([]EscalationStep(nil), alert.Escalations...)
+	}
+	return alert
 }
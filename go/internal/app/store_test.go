@@ -3,11 +3,61 @@ package app
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
-func TestInMemoryStoreHonorsContext(t *testing.T) {
-	store := NewInMemoryStore()
+// storeFactory returns a fresh, empty Store for one conformance case.
+type storeFactory func(t *testing.T) Store
+
+func TestInMemoryStoreConformance(t *testing.T) {
+	runStoreConformance(t, func(t *testing.T) Store {
+		return NewInMemoryStore()
+	})
+}
+
+func TestSQLiteStoreConformance(t *testing.T) {
+	runStoreConformance(t, func(t *testing.T) Store {
+		store, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "vitals.db"))
+		if err != nil {
+			t.Fatalf("open sqlite store: %v", err)
+		}
+		return store
+	})
+}
+
+// runStoreConformance checks the behavior every Store implementation must
+// share, so persistent backends can't drift from the in-memory one.
+func runStoreConformance(t *testing.T, newStore storeFactory) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, store Store)
+	}{
+		{"HonorsContext", testStoreHonorsContext},
+		{"AssignsIDsAndTimestamps", testStoreAssignsIDsAndTimestamps},
+		{"UpdateAlert", testStoreUpdateAlert},
+		{"RoundTripsAlertFields", testStoreRoundTripsAlertFields},
+		{"RejectsStaleAlertUpdates", testStoreRejectsStaleAlertUpdates},
+		{"AppendsAlertHistory", testStoreAppendsAlertHistory},
+		{"RoundTripsVitalKinds", testStoreRoundTripsVitalKinds},
+		{"VersionsPatientThresholds", testStoreVersionsPatientThresholds},
+		{"DeduplicatesIdempotencyKeys", testStoreDeduplicatesIdempotencyKeys},
+		{"WritesOutboxEvents", testStoreWritesOutboxEvents},
+		{"QueriesWithCursor", testStoreQueriesWithCursor},
+		{"RejectsUseAfterClose", testStoreRejectsUseAfterClose},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newStore(t)
+			defer store.Close()
+			tc.fn(t, store)
+		})
+	}
+}
+
+func testStoreHonorsContext(t *testing.T, store Store) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
@@ -40,3 +90,556 @@ func TestInMemoryStoreHonorsContext(t *testing.T) {
 		t.Fatalf("expected no alerts stored, got %d", len(alerts))
 	}
 }
+
+func testStoreAssignsIDsAndTimestamps(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	first, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	second, err := store.AddVital(ctx, Vital{PatientID: "patient-2", Systolic: 130, Diastolic: 85, TakenAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	if first.ID == 0 || second.ID <= first.ID {
+		t.Fatalf("expected increasing vital ids, got %d then %d", first.ID, second.ID)
+	}
+	if first.ReceivedAt.IsZero() {
+		t.Fatal("expected received_at to be defaulted")
+	}
+
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", VitalID: first.ID})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	if alert.ID == 0 || alert.Created.IsZero() {
+		t.Fatalf("expected alert id and created timestamp, got %+v", alert)
+	}
+
+	vitals, err := store.ListVitals(ctx)
+	if err != nil {
+		t.Fatalf("list vitals failed: %v", err)
+	}
+	if len(vitals) != 2 || vitals[0].ID != first.ID || vitals[1].ID != second.ID {
+		t.Fatalf("expected vitals in insertion order, got %+v", vitals)
+	}
+	if !vitals[0].TakenAt.Equal(first.TakenAt) || !vitals[0].ReceivedAt.Equal(first.ReceivedAt) {
+		t.Fatalf("timestamps did not round trip: stored %+v, listed %+v", first, vitals[0])
+	}
+}
+
+func testStoreUpdateAlert(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	alert.Status = AlertStatusAutoResolved
+	if _, err := store.UpdateAlert(ctx, alert); err != nil {
+		t.Fatalf("update alert: %v", err)
+	}
+
+	alerts, err := store.ListAlerts(ctx)
+	if err != nil {
+		t.Fatalf("list alerts failed: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].Status != AlertStatusAutoResolved {
+		t.Fatalf("unexpected alerts after update: %+v", alerts)
+	}
+
+	if _, err := store.UpdateAlert(ctx, Alert{ID: 99}); !errors.Is(err, ErrAlertNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func testStoreRoundTripsAlertFields(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	want := Alert{
+		VitalID:          7,
+		PatientID:        "patient-1",
+		Systolic:         190,
+		Diastolic:        130,
+		TakenAt:          now.Add(-time.Minute),
+		ReceivedAt:       now,
+		Reason:           "hypertensive crisis 190/130",
+		Severity:         SeverityCritical,
+		Status:           AlertStatusActive,
+		Created:          now,
+		MessageID:        3,
+		VitalIDs:         []int64{7, 8},
+		ReadingCount:     2,
+		LastSeenAt:       now,
+		ThresholdVersion: 2,
+		Escalations:      []EscalationStep{{Tier: 1, Recipient: RecipientOnCallNurse, At: now, MessageID: 4}},
+	}
+	stored, err := store.AddAlert(ctx, want)
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	if err := stored.Transition(AlertStatusConfirmedAbnormal, 9, now); err != nil {
+		t.Fatalf("transition: %v", err)
+	}
+	if _, err := store.UpdateAlert(ctx, stored); err != nil {
+		t.Fatalf("update alert: %v", err)
+	}
+
+	alerts, err := store.ListAlerts(ctx)
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("list alerts: %v %+v", err, alerts)
+	}
+	got := alerts[0]
+	if got.PatientID != want.PatientID || got.Reason != want.Reason || got.Severity != want.Severity ||
+		got.MessageID != want.MessageID || got.ReadingCount != want.ReadingCount || got.ThresholdVersion != want.ThresholdVersion {
+		t.Fatalf("alert fields did not round trip: %+v", got)
+	}
+	if len(got.VitalIDs) != 2 || got.VitalIDs[1] != 8 {
+		t.Fatalf("vital ids did not round trip: %v", got.VitalIDs)
+	}
+	if got.Status != AlertStatusConfirmedAbnormal || got.StatusVitalID != 9 || !got.StatusChangedAt.Equal(now) {
+		t.Fatalf("status change did not round trip: %+v", got)
+	}
+	if !got.TakenAt.Equal(want.TakenAt) || !got.LastSeenAt.Equal(want.LastSeenAt) {
+		t.Fatalf("timestamps did not round trip: %+v", got)
+	}
+	if len(got.Escalations) != 1 || got.Escalations[0].Recipient != RecipientOnCallNurse ||
+		got.Escalations[0].MessageID != 4 || !got.Escalations[0].At.Equal(now) {
+		t.Fatalf("escalations did not round trip: %+v", got.Escalations)
+	}
+}
+
+func testStoreRejectsStaleAlertUpdates(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	added, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	stale, err := store.GetAlert(ctx, added.ID)
+	if err != nil || stale.Revision != 1 {
+		t.Fatalf("get alert: %v %+v", err, stale)
+	}
+
+	acked := stale
+	if err := acked.Transition(AlertStatusAcknowledged, 0, now); err != nil {
+		t.Fatalf("transition: %v", err)
+	}
+	acked.Acknowledgement = AlertAction{By: "dr-lee", At: now, Note: "calling patient"}
+	acked, err = store.UpdateAlert(ctx, acked)
+	if err != nil || acked.Revision != 2 {
+		t.Fatalf("update alert: %v %+v", err, acked)
+	}
+	stale.Status = AlertStatusAutoResolved
+	if _, err := store.UpdateAlert(ctx, stale); !errors.Is(err, ErrAlertConflict) {
+		t.Fatalf("expected conflict for stale revision, got %v", err)
+	}
+
+	got, err := store.GetAlert(ctx, added.ID)
+	if err != nil {
+		t.Fatalf("get alert: %v", err)
+	}
+	if got.Status != AlertStatusAcknowledged || got.Revision != 2 || got.Acknowledgement.By != "dr-lee" ||
+		got.Acknowledgement.Note != "calling patient" || !got.Acknowledgement.At.Equal(now) {
+		t.Fatalf("acknowledgement did not round trip: %+v", got)
+	}
+	if _, err := store.GetAlert(ctx, 99); !errors.Is(err, ErrAlertNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func testStoreAppendsAlertHistory(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	alert, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", Status: AlertStatusActive},
+		AlertHistoryEntry{Type: AlertHistoryCreated, At: now, VitalID: 7})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	other, err := store.AddAlert(ctx, Alert{PatientID: "patient-2"}, AlertHistoryEntry{Type: AlertHistoryCreated, At: now})
+	if err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	if err := store.AppendAlertHistory(ctx, AlertHistoryEntry{AlertID: alert.ID, Type: AlertHistoryNotificationQueued, At: now, MessageID: 3}); err != nil {
+		t.Fatalf("append history: %v", err)
+	}
+	stale := alert
+	if err := alert.Transition(AlertStatusResolved, 0, now); err != nil {
+		t.Fatalf("transition: %v", err)
+	}
+	resolved := statusChange(AlertHistoryResolved, alert, AlertStatusActive)
+	resolved.Actor, resolved.Note, resolved.ResolutionReason = "dr-lee", "treated at clinic", ResolutionTreated
+	if _, err := store.UpdateAlert(ctx, alert, resolved); err != nil {
+		t.Fatalf("update alert: %v", err)
+	}
+	// A rejected update records nothing.
+	if _, err := store.UpdateAlert(ctx, stale, AlertHistoryEntry{Type: AlertHistoryVitalAttached}); !errors.Is(err, ErrAlertConflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+
+	history, err := store.AlertHistory(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("alert history: %v", err)
+	}
+	var types []AlertHistoryType
+	for i, entry := range history {
+		types = append(types, entry.Type)
+		if entry.AlertID != alert.ID || (i > 0 && entry.ID <= history[i-1].ID) {
+			t.Fatalf("entry %d: unexpected id or alert: %+v", i, entry)
+		}
+	}
+	if !slices.Equal(types, []AlertHistoryType{AlertHistoryCreated, AlertHistoryNotificationQueued, AlertHistoryResolved}) {
+		t.Fatalf("unexpected history: %v", types)
+	}
+	if got := history[0]; got.VitalID != 7 || !got.At.Equal(now) {
+		t.Fatalf("created entry did not round trip: %+v", got)
+	}
+	if got := history[2]; got.FromStatus != AlertStatusActive || got.Status != AlertStatusResolved || got.Actor != "dr-lee" ||
+		got.Note != "treated at clinic" || got.ResolutionReason != ResolutionTreated {
+		t.Fatalf("resolved entry did not round trip: %+v", got)
+	}
+	if history, err := store.AlertHistory(ctx, other.ID); err != nil || len(history) != 1 {
+		t.Fatalf("expected one entry for the other alert, got %+v (%v)", history, err)
+	}
+}
+
+func testStoreVersionsPatientThresholds(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	if _, err := store.GetPatientThreshold(ctx, "patient-1"); !errors.Is(err, ErrThresholdNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	first, err := store.SetPatientThreshold(ctx, PatientThreshold{PatientID: "patient-1", Thresholds: Thresholds{MaxSystolic: 150}, SetBy: "dr-smith"})
+	if err != nil {
+		t.Fatalf("set threshold: %v", err)
+	}
+	second, err := store.SetPatientThreshold(ctx, PatientThreshold{PatientID: "patient-1", Thresholds: Thresholds{MaxSystolic: 160}, SetBy: "dr-jones", Reason: "post-op"})
+	if err != nil {
+		t.Fatalf("set threshold: %v", err)
+	}
+	if first.Version != 1 || second.Version != 2 || second.SetAt.IsZero() {
+		t.Fatalf("unexpected versions: %+v %+v", first, second)
+	}
+	if _, err := store.SetPatientThreshold(ctx, PatientThreshold{PatientID: "patient-2", Thresholds: Thresholds{MinSystolic: 100}, SetBy: "dr-smith"}); err != nil {
+		t.Fatalf("set threshold: %v", err)
+	}
+
+	current, err := store.GetPatientThreshold(ctx, "patient-1")
+	if err != nil {
+		t.Fatalf("get threshold: %v", err)
+	}
+	if current.Version != 2 || current.Thresholds.MaxSystolic != 160 || current.SetBy != "dr-jones" || current.Reason != "post-op" {
+		t.Fatalf("unexpected current threshold: %+v", current)
+	}
+
+	if _, err := store.DeletePatientThreshold(ctx, "patient-1", "dr-smith"); err != nil {
+		t.Fatalf("delete threshold: %v", err)
+	}
+	if _, err := store.DeletePatientThreshold(ctx, "patient-1", "dr-smith"); !errors.Is(err, ErrThresholdNotFound) {
+		t.Fatalf("expected second delete to report not found, got %v", err)
+	}
+	if _, err := store.GetPatientThreshold(ctx, "patient-1"); !errors.Is(err, ErrThresholdNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+
+	list, err := store.ListPatientThresholds(ctx)
+	if err != nil {
+		t.Fatalf("list thresholds: %v", err)
+	}
+	if len(list) != 1 || list[0].PatientID != "patient-2" {
+		t.Fatalf("expected only patient-2 to have a current threshold, got %+v", list)
+	}
+
+	history, err := store.ListPatientThresholdHistory(ctx, "patient-1")
+	if err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+	if len(history) != 3 || !history[2].Deleted || history[2].Version != 3 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+}
+
+func testStoreDeduplicatesIdempotencyKeys(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	vital := Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: now.Add(-time.Minute), ReceivedAt: now}
+	key := IdempotencyKey{Key: "key-1", Fingerprint: vitalFingerprint(vital), ExpiresAt: now.Add(time.Hour)}
+
+	first, replayed, err := store.AddVitalIdempotent(ctx, vital, key)
+	if err != nil || replayed {
+		t.Fatalf("first add: replayed=%v err=%v", replayed, err)
+	}
+	again, replayed, err := store.AddVitalIdempotent(ctx, vital, key)
+	if err != nil || !replayed || again.ID != first.ID {
+		t.Fatalf("expected replay of vital %d, got %+v replayed=%v err=%v", first.ID, again, replayed, err)
+	}
+
+	other := vital
+	other.Systolic = 150
+	conflict := key
+	conflict.Fingerprint = vitalFingerprint(other)
+	if _, _, err := store.AddVitalIdempotent(ctx, other, conflict); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("expected key reuse error, got %v", err)
+	}
+
+	// Keys are scoped to the patient and client, so neither collides.
+	otherPatient := other
+	otherPatient.PatientID = "patient-2"
+	if stored, replayed, err := store.AddVitalIdempotent(ctx, otherPatient, conflict); err != nil || replayed || stored.PatientID != "patient-2" {
+		t.Fatalf("expected another patient's key to store a new vital, got %+v replayed=%v err=%v", stored, replayed, err)
+	}
+	otherClient := conflict
+	otherClient.ClientID = "client-2"
+	if stored, replayed, err := store.AddVitalIdempotent(ctx, other, otherClient); err != nil || replayed || stored.ID == first.ID {
+		t.Fatalf("expected another client's key to store a new vital, got %+v replayed=%v err=%v", stored, replayed, err)
+	}
+
+	// Once the key has expired it is free for a new reading.
+	other.ReceivedAt = now.Add(2 * time.Hour)
+	conflict.ExpiresAt = other.ReceivedAt.Add(time.Hour)
+	fresh, replayed, err := store.AddVitalIdempotent(ctx, other, conflict)
+	if err != nil || replayed || fresh.ID == first.ID {
+		t.Fatalf("expected expired key to store a new vital, got %+v replayed=%v err=%v", fresh, replayed, err)
+	}
+
+	vitals, err := store.ListVitals(ctx)
+	if err != nil || len(vitals) != 4 {
+		t.Fatalf("expected 4 vitals, got %+v (%v)", vitals, err)
+	}
+}
+
+func testStoreRoundTripsVitalKinds(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	weight, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Kind: VitalKindWeight, Value: 82.45, TakenAt: now, Flags: []QualityFlag{QualityAtypicalValue}})
+	if err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	if _, err := store.AddAlert(ctx, Alert{VitalID: weight.ID, PatientID: "patient-1", Kind: VitalKindWeight, Value: 82.45, TakenAt: now}); err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+
+	vitals, err := store.QueryVitals(ctx, VitalQuery{PatientID: "patient-1"})
+	if err != nil || len(vitals) != 1 || vitals[0].Kind != VitalKindWeight || vitals[0].Value != 82.45 {
+		t.Fatalf("vital kind did not round trip: %v %+v", err, vitals)
+	}
+	if !slices.Equal(vitals[0].Flags, []QualityFlag{QualityAtypicalValue}) {
+		t.Fatalf("quality flags did not round trip: %v", vitals[0].Flags)
+	}
+	pending, err := store.ListPendingEvents(ctx, 0)
+	if err != nil || len(pending) != 1 || pending[0].Event.Vital.Kind != VitalKindWeight || pending[0].Event.Vital.Value != 82.45 {
+		t.Fatalf("outbox event lost the vital kind: %v %+v", err, pending)
+	}
+	alerts, err := store.ListAlerts(ctx)
+	if err != nil || len(alerts) != 1 || alerts[0].Kind != VitalKindWeight || alerts[0].Value != 82.45 {
+		t.Fatalf("alert kind did not round trip: %v %+v", err, alerts)
+	}
+}
+
+func testStoreWritesOutboxEvents(t *testing.T, store Store) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	first, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: now})
+	if err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	keyed := Vital{PatientID: "patient-2", Systolic: 190, Diastolic: 130, TakenAt: now}
+	key := IdempotencyKey{Key: "key-1", Fingerprint: vitalFingerprint(keyed), ExpiresAt: now.Add(time.Hour)}
+	second, _, err := store.AddVitalIdempotent(ctx, keyed, key)
+	if err != nil {
+		t.Fatalf("add keyed vital: %v", err)
+	}
+	// A replayed key must not enqueue a second event.
+	if _, _, err := store.AddVitalIdempotent(ctx, keyed, key); err != nil {
+		t.Fatalf("replay keyed vital: %v", err)
+	}
+
+	pending, err := store.ListPendingEvents(ctx, 0)
+	if err != nil {
+		t.Fatalf("list pending: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Event.Vital.ID != first.ID || pending[1].Event.Vital.ID != second.ID {
+		t.Fatalf("expected events for both vitals in order, got %+v", pending)
+	}
+	if pending[1].Event.Type != EventTypeVitalReceived || pending[1].Event.Vital.Systolic != 190 {
+		t.Fatalf("unexpected event payload: %+v", pending[1].Event)
+	}
+	if limited, _ := store.ListPendingEvents(ctx, 1); len(limited) != 1 {
+		t.Fatalf("expected limit to apply, got %d events", len(limited))
+	}
+
+	if err := store.MarkEventDelivered(ctx, pending[0].ID); err != nil {
+		t.Fatalf("mark delivered: %v", err)
+	}
+	if err := store.MarkEventDelivered(ctx, pending[0].ID); err != nil {
+		t.Fatalf("mark delivered twice: %v", err)
+	}
+	remaining, _ := store.ListPendingEvents(ctx, 0)
+	if len(remaining) != 1 || remaining[0].ID != pending[1].ID {
+		t.Fatalf("expected only the second event pending, got %+v", remaining)
+	}
+}
+
+func testStoreQueriesWithCursor(t *testing.T, store Store) {
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	for i, offset := range []time.Duration{2, 0, 1, 1} {
+		patient := "patient-1"
+		if i == 3 {
+			patient = "patient-2"
+		}
+		vital, err := store.AddVital(ctx, Vital{PatientID: patient, Systolic: 190, Diastolic: 130, TakenAt: base.Add(offset * time.Hour)})
+		if err != nil {
+			t.Fatalf("add vital: %v", err)
+		}
+		status := AlertStatusActive
+		if i == 2 {
+			status = AlertStatusAutoResolved
+		}
+		if _, err := store.AddAlert(ctx, Alert{PatientID: patient, VitalID: vital.ID, TakenAt: vital.TakenAt, Status: status}); err != nil {
+			t.Fatalf("add alert: %v", err)
+		}
+	}
+
+	vitals, err := store.QueryVitals(ctx, VitalQuery{Order: SortDescending})
+	if err != nil {
+		t.Fatalf("query vitals: %v", err)
+	}
+	if got := vitalIDs(vitals); !slices.Equal(got, []int64{1, 4, 3, 2}) {
+		t.Fatalf("unexpected descending order: %v", got)
+	}
+
+	vitals, err = store.QueryVitals(ctx, VitalQuery{
+		PatientID: "patient-1",
+		After:     &Cursor{TakenAt: base, ID: 2},
+		Limit:     1,
+	})
+	if err != nil {
+		t.Fatalf("query vitals: %v", err)
+	}
+	if got := vitalIDs(vitals); !slices.Equal(got, []int64{3}) {
+		t.Fatalf("unexpected page after cursor: %v", got)
+	}
+
+	vitals, err = store.QueryVitals(ctx, VitalQuery{TakenAt: TimeRange{After: base.Add(time.Hour), Before: base.Add(2 * time.Hour)}})
+	if err != nil {
+		t.Fatalf("query vitals: %v", err)
+	}
+	if got := vitalIDs(vitals); !slices.Equal(got, []int64{3, 4}) {
+		t.Fatalf("unexpected range result: %v", got)
+	}
+
+	alerts, err := store.QueryAlerts(ctx, AlertQuery{PatientID: "patient-1", Statuses: []AlertStatus{AlertStatusActive}})
+	if err != nil {
+		t.Fatalf("query alerts: %v", err)
+	}
+	if len(alerts) != 2 || alerts[0].VitalID != 2 || alerts[1].VitalID != 1 {
+		t.Fatalf("unexpected alerts: %+v", alerts)
+	}
+}
+
+func vitalIDs(vitals []Vital) []int64 {
+	ids := make([]int64, len(vitals))
+	for i, v := range vitals {
+		ids[i] = v.ID
+	}
+	return ids
+}
+
+func testStoreRejectsUseAfterClose(t *testing.T, store Store) {
+	store.Close()
+	if _, err := store.AddVital(context.Background(), Vital{PatientID: "patient-1"}); !errors.Is(err, ErrStoreClosed) {
+		t.Fatalf("expected store closed error, got %v", err)
+	}
+	if _, err := store.ListAlerts(context.Background()); !errors.Is(err, ErrStoreClosed) {
+		t.Fatalf("expected store closed error, got %v", err)
+	}
+}
+
+func TestSQLiteStorePrunesDeliveredOutboxEvents(t *testing.T) {
+	store, err := OpenSQLiteStore(filepath.Join(t.TempDir(), "vitals.db"))
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("add vital: %v", err)
+		}
+	}
+	pending, err := store.ListPendingEvents(ctx, 0)
+	if err != nil || len(pending) != 3 {
+		t.Fatalf("expected 3 pending events, got %+v (%v)", pending, err)
+	}
+	for _, event := range pending {
+		if err := store.MarkEventDelivered(ctx, event.ID); err != nil {
+			t.Fatalf("mark delivered: %v", err)
+		}
+	}
+	var rows int
+	if err := store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM outbox`).Scan(&rows); err != nil || rows != 0 {
+		t.Fatalf("expected delivered events to be deleted, got %d rows (%v)", rows, err)
+	}
+
+	if _, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	next, err := store.ListPendingEvents(ctx, 0)
+	if err != nil || len(next) != 1 || next[0].ID <= pending[2].ID {
+		t.Fatalf("expected event ids to keep increasing after pruning, got %+v (%v)", next, err)
+	}
+}
+
+func TestSQLiteStorePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vitals.db")
+	ctx := context.Background()
+
+	store, err := OpenSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("open sqlite store: %v", err)
+	}
+	vital, err := store.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 190, Diastolic: 130, TakenAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("add vital: %v", err)
+	}
+	if _, err := store.AddAlert(ctx, Alert{PatientID: "patient-1", VitalID: vital.ID, VitalIDs: []int64{vital.ID}}); err != nil {
+		t.Fatalf("add alert: %v", err)
+	}
+	keyed := Vital{PatientID: "patient-1", Systolic: 125, Diastolic: 85, TakenAt: time.Now().UTC()}
+	key := IdempotencyKey{Key: "key-1", Fingerprint: vitalFingerprint(keyed), ExpiresAt: time.Now().Add(time.Hour)}
+	keyedVital, _, err := store.AddVitalIdempotent(ctx, keyed, key)
+	if err != nil {
+		t.Fatalf("add keyed vital: %v", err)
+	}
+	store.Close()
+
+	reopened, err := OpenSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("reopen sqlite store: %v", err)
+	}
+	defer reopened.Close()
+
+	vitals, err := reopened.ListVitals(ctx)
+	if err != nil || len(vitals) != 2 || vitals[0].ID != vital.ID {
+		t.Fatalf("expected vital to survive reopen, got %+v (%v)", vitals, err)
+	}
+	alerts, err := reopened.ListAlerts(ctx)
+	if err != nil || len(alerts) != 1 || alerts[0].VitalID != vital.ID {
+		t.Fatalf("expected alert to survive reopen, got %+v (%v)", alerts, err)
+	}
+	replay, replayed, err := reopened.AddVitalIdempotent(ctx, keyed, key)
+	if err != nil || !replayed || replay.ID != keyedVital.ID {
+		t.Fatalf("expected idempotency key to survive reopen, got %+v replayed=%v err=%v", replay, replayed, err)
+	}
+	next, err := reopened.AddVital(ctx, Vital{PatientID: "patient-1", Systolic: 120, Diastolic: 80, TakenAt: time.Now().UTC()})
+	if err != nil || next.ID <= vital.ID {
+		t.Fatalf("expected ids to keep increasing after reopen, got %d (%v)", next.ID, err)
+	}
+}
@@ -0,0 +1,346 @@
+package app
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NotificationType is what a message is for. Each locale has a template
+// named after each type for the message text, and one with a _subject
+// suffix for its subject line.
+type NotificationType string
+
+const (
+	NotificationRetakeRequest NotificationType = "retake_request"
+	NotificationEscalation    NotificationType = "escalation"
+)
+
+var notificationTypes = []NotificationType{NotificationRetakeRequest, NotificationEscalation}
+
+func (t NotificationType) subject() string { return string(t) + "_subject" }
+
+// templateNames returns the templates every locale must be able to render.
+func templateNames() []string {
+	var names []string
+	for _, typ := range notificationTypes {
+		names = append(names, string(typ), typ.subject())
+	}
+	return names
+}
+
+// retakeInstructionsTemplate, if a locale defines it, is rendered first
+// into TemplateData.RetakeInstructions.
+const retakeInstructionsTemplate = "retake_instructions"
+
+const DefaultLocale = "en"
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// TemplateData is what notification templates render. Kind, Severity and
+// Recipient are enum names, such as BLOOD_PRESSURE, CRITICAL and
+// ON_CALL_NURSE.
+type TemplateData struct {
+	PatientID string
+	AlertID   int64
+	Recipient string
+	Severity  string
+	// Reason is the alert's reason, in English.
+	Reason    string
+	Kind      string
+	Systolic  int32
+	Diastolic int32
+	Value     float64
+	Unit      string
+	// Reading is the measurement with its unit, such as "190/130 mmHg".
+	Reading string
+	TakenAt time.Time
+	// RetakeInstructions is the locale's retake_instructions template
+	// rendered for this reading.
+	RetakeInstructions string
+	// Tier is the escalation tier, counting from 1.
+	Tier int
+}
+
+func alertTemplateData(alert Alert) TemplateData {
+	reading := strconv.FormatFloat(alert.Value, 'f', -1, 64) + " " + alert.Kind.Unit()
+	if alert.Kind == VitalKindBloodPressure {
+		reading = fmt.Sprintf("%d/%d mmHg", alert.Systolic, alert.Diastolic)
+	}
+	return TemplateData{
+		PatientID: alert.PatientID,
+		AlertID:   alert.ID,
+		Severity:  alert.Severity.String(),
+		Reason:    alert.Reason,
+		Kind:      alert.Kind.String(),
+		Systolic:  alert.Systolic,
+		Diastolic: alert.Diastolic,
+		Value:     alert.Value,
+		Unit:      alert.Kind.Unit(),
+		Reading:   reading,
+		TakenAt:   alert.TakenAt,
+	}
+}
+
+// TemplateRegistry holds the notification templates for each locale, as
+// text/template sets in which a locale's templates can call one another.
+type TemplateRegistry struct {
+	locales map[string]*template.Template
+}
+
+var defaultTemplates = mustParseBuiltinTemplates()
+
+func mustParseBuiltinTemplates() *TemplateRegistry {
+	root, err := fs.Sub(builtinTemplates, "templates")
+	if err != nil {
+		panic(err)
+	}
+	r := &TemplateRegistry{locales: make(map[string]*template.Template)}
+	if err := r.parseDir(root); err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// DefaultTemplates returns the built-in English and Spanish templates.
+func DefaultTemplates() *TemplateRegistry {
+	return defaultTemplates
+}
+
+// LoadTemplates reads templates from dir, laid out as
+// <locale>/<name>.tmpl, on top of the built-in ones: a file replaces the
+// built-in template of the same name and locale, and a new locale
+// directory adds a language. It fails unless every template renders; see
+// Validate.
+func LoadTemplates(dir string) (*TemplateRegistry, error) {
+	r := &TemplateRegistry{locales: make(map[string]*template.Template)}
+	for locale, set := range defaultTemplates.locales {
+		clone, err := set.Clone()
+		if err != nil {
+			return nil, err
+		}
+		r.locales[locale] = clone
+	}
+	if err := r.parseDir(os.DirFS(dir)); err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *TemplateRegistry) parseDir(root fs.FS) error {
+	locales, err := fs.ReadDir(root, ".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range locales {
+		if !entry.IsDir() {
+			continue
+		}
+		locale := normalizeLocale(entry.Name())
+		files, err := fs.Glob(root, path.Join(entry.Name(), "*.tmpl"))
+		if err != nil {
+			return err
+		}
+		set := r.locales[locale]
+		if set == nil {
+			set = template.New(locale).Option("missingkey=error")
+		}
+		for _, file := range files {
+			text, err := fs.ReadFile(root, file)
+			if err != nil {
+				return err
+			}
+			name := strings.TrimSuffix(path.Base(file), ".tmpl")
+			if _, err := set.New(name).Parse(string(text)); err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+		}
+		r.locales[locale] = set
+	}
+	return nil
+}
+
+// Locales returns the registry's locales, sorted.
+func (r *TemplateRegistry) Locales() []string {
+	locales := make([]string, 0, len(r.locales))
+	for locale := range r.locales {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// Render renders the template for typ in locale. A regional locale such as
+// es-MX falls back to its language, es, and a locale without the template
+// falls back to DefaultLocale.
+func (r *TemplateRegistry) Render(typ NotificationType, locale string, data TemplateData) (string, error) {
+	return r.render(string(typ), locale, data)
+}
+
+// RenderSubject renders the subject line for typ in locale, falling back
+// like Render.
+func (r *TemplateRegistry) RenderSubject(typ NotificationType, locale string, data TemplateData) (string, error) {
+	return r.render(typ.subject(), locale, data)
+}
+
+func (r *TemplateRegistry) render(name, locale string, data TemplateData) (string, error) {
+	set := r.lookup(name, locale)
+	if set == nil {
+		return "", fmt.Errorf("no %s template", name)
+	}
+	if set.Lookup(retakeInstructionsTemplate) != nil {
+		instructions, err := execute(set, retakeInstructionsTemplate, data)
+		if err != nil {
+			return "", err
+		}
+		data.RetakeInstructions = instructions
+	}
+	return execute(set, name, data)
+}
+
+func (r *TemplateRegistry) lookup(name, locale string) *template.Template {
+	locale = normalizeLocale(locale)
+	language, _, _ := strings.Cut(locale, "-")
+	for _, candidate := range []string{locale, language, DefaultLocale} {
+		if set := r.locales[candidate]; set != nil && set.Lookup(name) != nil {
+			return set
+		}
+	}
+	return nil
+}
+
+func execute(set *template.Template, name string, data TemplateData) (string, error) {
+	var b strings.Builder
+	if err := set.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Validate renders every notification type and subject in every locale for
+// a sample reading of each vital kind and recipient, and reports every
+// template that fails or renders empty. DefaultLocale must define them all.
+// A subject must also fit on one line.
+func (r *TemplateRegistry) Validate() error {
+	var errs []error
+	for _, name := range templateNames() {
+		if set := r.locales[DefaultLocale]; set == nil || set.Lookup(name) == nil {
+			errs = append(errs, fmt.Errorf("%s: missing %s template", DefaultLocale, name))
+		}
+	}
+	for _, locale := range r.Locales() {
+		for _, name := range templateNames() {
+			for _, data := range sampleTemplateData() {
+				text, err := r.render(name, locale, data)
+				if err == nil && text == "" {
+					err = errors.New("renders empty")
+				}
+				if err == nil && strings.HasSuffix(name, "_subject") && strings.ContainsAny(text, "\r\n") {
+					err = errors.New("subject spans more than one line")
+				}
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s/%s for %s: %w", locale, name, data.Kind, err))
+					break
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sampleTemplateData() []TemplateData {
+	var samples []TemplateData
+	for _, kind := range vitalKinds {
+		for _, recipient := range recipients {
+			alert := Alert{
+				ID:        1,
+				PatientID: "patient-1",
+				Kind:      kind,
+				Systolic:  190,
+				Diastolic: 130,
+				Value:     100,
+				Reason:    "sample reason",
+				Severity:  SeverityCritical,
+				TakenAt:   time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			}
+			data := alertTemplateData(alert)
+			data.Recipient = recipient.String()
+			data.Tier = 1
+			samples = append(samples, data)
+		}
+	}
+	return samples
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// LocaleDirectory reports the language a message's recipient prefers, as a
+// locale such as "es" or "es-MX"; empty means DefaultLocale.
+type LocaleDirectory interface {
+	Locale(patientID string, recipient Recipient) string
+}
+
+// MessageComposer writes notification text from templates, in each
+// recipient's preferred language.
+type MessageComposer struct {
+	templates *TemplateRegistry
+	locales   LocaleDirectory
+}
+
+// NewMessageComposer renders templates in the locales from locales, which
+// may be nil to always use DefaultLocale.
+func NewMessageComposer(templates *TemplateRegistry, locales LocaleDirectory) *MessageComposer {
+	return &MessageComposer{templates: templates, locales: locales}
+}
+
+func defaultMessageComposer() *MessageComposer {
+	return NewMessageComposer(DefaultTemplates(), nil)
+}
+
+// Compose renders typ for recipient. If the recipient's template fails it
+// falls back to DefaultLocale, and then to data.Reason, so a bad template
+// never stops a notification.
+func (c *MessageComposer) Compose(typ NotificationType, recipient Recipient, data TemplateData) string {
+	return c.compose(string(typ), recipient, data)
+}
+
+// ComposeSubject renders the subject line for typ, falling back like
+// Compose.
+func (c *MessageComposer) ComposeSubject(typ NotificationType, recipient Recipient, data TemplateData) string {
+	return c.compose(typ.subject(), recipient, data)
+}
+
+func (c *MessageComposer) compose(name string, recipient Recipient, data TemplateData) string {
+	data.Recipient = recipient.String()
+	locale := DefaultLocale
+	if c.locales != nil {
+		if l := c.locales.Locale(data.PatientID, recipient); l != "" {
+			locale = l
+		}
+	}
+	text, err := c.templates.render(name, locale, data)
+	if err != nil && locale != DefaultLocale {
+		log.Printf("templates: %s/%s: %v", locale, name, err)
+		text, err = c.templates.render(name, DefaultLocale, data)
+	}
+	if err != nil {
+		log.Printf("templates: %s/%s: %v", DefaultLocale, name, err)
+		return data.Reason
+	}
+	return text
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type fixedLocales map[string]string
+
+func (l fixedLocales) Locale(patientID string, _ Recipient) string { return l[patientID] }
+
+func TestDefaultTemplatesValidate(t *testing.T) {
+	if err := DefaultTemplates().Validate(); err != nil {
+		t.Fatalf("built-in templates: %v", err)
+	}
+	if got := DefaultTemplates().Locales(); len(got) != 2 || got[0] != "en" || got[1] != "es" {
+		t.Fatalf("expected en and es templates, got %v", got)
+	}
+}
+
+func TestMessageComposerUsesPatientLanguage(t *testing.T) {
+	composer := NewMessageComposer(DefaultTemplates(), fixedLocales{"patient-es": "es-MX"})
+	alert := Alert{
+		ID:        3,
+		Kind:      VitalKindBloodPressure,
+		Systolic:  190,
+		Diastolic: 130,
+		Severity:  SeverityCritical,
+		Reason:    "BP 190/130 exceeds critical threshold",
+	}
+
+	alert.PatientID = "patient-en"
+	en := composer.Compose(NotificationRetakeRequest, RecipientPatient, alertTemplateData(alert))
+	if !strings.HasPrefix(en, "Urgent alert: BP 190/130 exceeds critical threshold.") || !strings.Contains(en, "retake your blood pressure") {
+		t.Fatalf("unexpected English message: %q", en)
+	}
+
+	alert.PatientID = "patient-es"
+	es := composer.Compose(NotificationRetakeRequest, RecipientPatient, alertTemplateData(alert))
+	if !strings.Contains(es, "presión arterial de 190/130 mmHg") || !strings.Contains(es, "vuelva a tomarse la presión") {
+		t.Fatalf("unexpected Spanish message: %q", es)
+	}
+}
+
+func TestMessageComposerLocalizesSubject(t *testing.T) {
+	composer := NewMessageComposer(DefaultTemplates(), fixedLocales{"patient-es": "es"})
+	alert := Alert{ID: 3, PatientID: "patient-es", Kind: VitalKindPulse, Value: 140, Severity: SeverityCritical}
+
+	if got := composer.ComposeSubject(NotificationRetakeRequest, RecipientPatient, alertTemplateData(alert)); got != "Urgente: vuelva a medir su pulso" {
+		t.Fatalf("unexpected Spanish subject: %q", got)
+	}
+	alert.PatientID = "patient-en"
+	if got := composer.ComposeSubject(NotificationEscalation, RecipientOnCallNurse, alertTemplateData(alert)); got != "Alert 3 for patient-en needs attention" {
+		t.Fatalf("unexpected English subject: %q", got)
+	}
+}
+
+func TestLoadTemplatesOverridesWording(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "en"), 0o755)
+	os.WriteFile(filepath.Join(dir, "en", "retake_request.tmpl"), []byte("Hi {{.PatientID}}, your reading of {{.Reading}} needs a retake. {{.RetakeInstructions}}\n"), 0o644)
+
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("load templates: %v", err)
+	}
+	data := alertTemplateData(Alert{PatientID: "patient-1", Kind: VitalKindPulse, Value: 140, Reason: "pulse high"})
+	got, err := templates.Render(NotificationRetakeRequest, "en", data)
+	if err != nil || got != "Hi patient-1, your reading of 140 bpm needs a retake. Please rest for 5 minutes, then retake your pulse." {
+		t.Fatalf("unexpected overridden message: %q (%v)", got, err)
+	}
+	// Templates the directory leaves out keep their built-in wording.
+	if got, _ := templates.Render(NotificationRetakeRequest, "es", data); !strings.Contains(got, "pulso") {
+		t.Fatalf("expected the built-in Spanish template, got %q", got)
+	}
+
+	os.WriteFile(filepath.Join(dir, "en", "retake_request_subject.tmpl"), []byte("Retake\n{{.Reading}}\n"), 0o644)
+	if _, err := LoadTemplates(dir); err == nil || !strings.Contains(err.Error(), "en/retake_request_subject") {
+		t.Fatalf("expected a multi-line subject to fail validation, got %v", err)
+	}
+	os.Remove(filepath.Join(dir, "en", "retake_request_subject.tmpl"))
+
+	os.WriteFile(filepath.Join(dir, "en", "escalation.tmpl"), []byte("Escalation for {{.Patient}}\n"), 0o644)
+	if _, err := LoadTemplates(dir); err == nil || !strings.Contains(err.Error(), "en/escalation") {
+		t.Fatalf("expected a template with an unknown field to fail validation, got %v", err)
+	}
+}
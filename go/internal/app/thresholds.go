@@ -0,0 +1,72 @@
+package app
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidThreshold  = errors.New("invalid threshold")
+	ErrThresholdNotFound = errors.New("threshold not found")
+)
+
+// Thresholds are physician-ordered blood pressure limits. A zero field
+// keeps the rule set's default for that limit.
+type Thresholds struct {
+	MaxSystolic  int32
+	MaxDiastolic int32
+	MinSystolic  int32
+}
+
+func (t Thresholds) IsZero() bool {
+	return t == Thresholds{}
+}
+
+// PatientThreshold is one version of a patient's override. Every change,
+// including removal, is a new version so alerts can point at exactly the
+// limits that fired them.
+type PatientThreshold struct {
+	PatientID  string
+	Thresholds Thresholds
+	Version    int64
+	SetBy      string
+	SetAt      time.Time
+	Reason     string
+	Deleted    bool
+}
+
+// ThresholdAware is implemented by rules whose limits can be replaced by a
+// patient's override.
+type ThresholdAware interface {
+	WithThresholds(t Thresholds) Rule
+}
+
+// ForThresholds returns a copy of the rule set with t applied to every rule
+// that supports overrides.
+func (rs RuleSet) ForThresholds(t Thresholds) RuleSet {
+	rules := make([]Rule, len(rs.Rules))
+	for i, rule := range rs.Rules {
+		if aware, ok := rule.(ThresholdAware); ok {
+			rule = aware.WithThresholds(t)
+		}
+		rules[i] = rule
+	}
+	return RuleSet{Rules: rules, AlertAt: rs.AlertAt}
+}
+
+func (r HypertensiveCrisisRule) WithThresholds(t Thresholds) Rule {
+	if t.MaxSystolic > 0 {
+		r.MaxSystolic = t.MaxSystolic
+	}
+	if t.MaxDiastolic > 0 {
+		r.MaxDiastolic = t.MaxDiastolic
+	}
+	return r
+}
+
+func (r HypotensionRule) WithThresholds(t Thresholds) Rule {
+	if t.MinSystolic > 0 {
+		r.MinSystolic = t.MinSystolic
+	}
+	return r
+}
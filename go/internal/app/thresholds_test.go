@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRuleSetForThresholdsOverridesOnlySetLimits(t *testing.T) {
+	rules := DefaultRuleSet().ForThresholds(Thresholds{MaxSystolic: 160})
+
+	if findings := rules.Alerting(rules.Evaluate(Vital{Systolic: 165, Diastolic: 90}, nil)); len(findings) == 0 {
+		t.Fatal("expected override max systolic to alert at 165")
+	}
+	if findings := rules.Alerting(rules.Evaluate(Vital{Systolic: 150, Diastolic: 125}, nil)); len(findings) == 0 {
+		t.Fatal("expected default max diastolic to still apply")
+	}
+	if findings := DefaultRuleSet().Alerting(DefaultRuleSet().Evaluate(Vital{Systolic: 165, Diastolic: 90}, nil)); len(findings) != 0 {
+		t.Fatal("override must not mutate the base rule set")
+	}
+}
+
+func TestAlertWorkerAppliesPatientThresholdOverride(t *testing.T) {
+	store := NewInMemoryStore()
+	pubsub := NewPubSub()
+	service := NewService(store)
+	worker := NewAlertWorker(pubsub, store, 8, nil)
+	ctx := context.Background()
+
+	if _, err := service.SetPatientThreshold(ctx, "post-op", Thresholds{MaxSystolic: 150}, "dr-smith", "initial"); err != nil {
+		t.Fatalf("set threshold: %v", err)
+	}
+	override, err := service.SetPatientThreshold(ctx, "post-op", Thresholds{MaxSystolic: 160}, "dr-jones", "post-op day 2")
+	if err != nil {
+		t.Fatalf("set threshold: %v", err)
+	}
+	if override.Version != 2 {
+		t.Fatalf("expected version 2, got %d", override.Version)
+	}
+
+	reading := Vital{Systolic: 170, Diastolic: 85, TakenAt: time.Now().UTC()}
+	reading.ID, reading.PatientID = 1, "post-op"
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: reading})
+	reading.ID, reading.PatientID = 2, "default-patient"
+	worker.handleEvent(ctx, Event{Type: EventTypeVitalReceived, Vital: reading})
+
+	alerts, _ := store.ListAlerts(ctx)
+	if len(alerts) != 1 {
+		t.Fatalf("expected only the override patient to alert, got %+v", alerts)
+	}
+	if alerts[0].PatientID != "post-op" || alerts[0].ThresholdVersion != 2 {
+		t.Fatalf("unexpected alert: %+v", alerts[0])
+	}
+}
+
+func TestServicePatientThresholdLifecycle(t *testing.T) {
+	store := NewInMemoryStore()
+	clock := newFakeClock(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC))
+	service := NewService(store, WithServiceClock(clock))
+	ctx := context.Background()
+
+	if _, err := service.SetPatientThreshold(ctx, "patient-1", Thresholds{}, "dr-smith", ""); !errors.Is(err, ErrInvalidThreshold) {
+		t.Fatalf("expected invalid threshold for empty limits, got %v", err)
+	}
+	if _, err := service.SetPatientThreshold(ctx, "patient-1", Thresholds{MaxSystolic: 150}, "", ""); !errors.Is(err, ErrInvalidThreshold) {
+		t.Fatalf("expected invalid threshold without set_by, got %v", err)
+	}
+	if _, err := service.SetPatientThreshold(ctx, "patient-1", Thresholds{MaxSystolic: 100, MinSystolic: 110}, "dr-smith", ""); !errors.Is(err, ErrInvalidThreshold) {
+		t.Fatalf("expected invalid threshold for inverted limits, got %v", err)
+	}
+
+	if _, err := service.SetPatientThreshold(ctx, "patient-1", Thresholds{MaxSystolic: 150}, "dr-smith", "pregnancy"); err != nil {
+		t.Fatalf("set threshold: %v", err)
+	}
+	current, history, err := service.GetPatientThreshold(ctx, "patient-1")
+	if err != nil {
+		t.Fatalf("get threshold: %v", err)
+	}
+	if current.Version != 1 || current.SetBy != "dr-smith" || !current.SetAt.Equal(clock.Now()) || len(history) != 1 {
+		t.Fatalf("unexpected threshold %+v history %+v", current, history)
+	}
+
+	deleted, err := service.DeletePatientThreshold(ctx, "patient-1", "dr-jones")
+	if err != nil {
+		t.Fatalf("delete threshold: %v", err)
+	}
+	if !deleted.Deleted || deleted.Version != 2 || deleted.SetBy != "dr-jones" {
+		t.Fatalf("unexpected tombstone: %+v", deleted)
+	}
+	if _, _, err := service.GetPatientThreshold(ctx, "patient-1"); !errors.Is(err, ErrThresholdNotFound) {
+		t.Fatalf("expected not found after delete, got %v", err)
+	}
+	if list, _ := service.ListPatientThresholds(ctx); len(list) != 0 {
+		t.Fatalf("expected no current thresholds, got %+v", list)
+	}
+	if history, _ := store.ListPatientThresholdHistory(ctx, "patient-1"); len(history) != 2 {
+		t.Fatalf("expected both versions retained, got %+v", history)
+	}
+}
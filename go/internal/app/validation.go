@@ -0,0 +1,130 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FieldViolation is one problem with one field of an ingest request.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists every problem found with a reading, so a client
+// can fix them all at once. It matches ErrInvalidVital under errors.Is.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + ": " + v.Description
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidVital, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidVital
+}
+
+type violations []FieldViolation
+
+func (v *violations) add(field, format string, args ...any) {
+	*v = append(*v, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+// ValidationPolicy bounds how far a reading's TakenAt may be from the
+// server clock. Readings outside the window are rejected.
+type ValidationPolicy struct {
+	// MaxClockSkew is how far in the future TakenAt may be, to allow for
+	// device clocks running fast.
+	MaxClockSkew time.Duration
+	// MaxAge is how old a reading may be when it arrives.
+	MaxAge time.Duration
+}
+
+func DefaultValidationPolicy() ValidationPolicy {
+	return ValidationPolicy{MaxClockSkew: 5 * time.Minute, MaxAge: 365 * 24 * time.Hour}
+}
+
+func (p ValidationPolicy) checkTakenAt(takenAt, now time.Time, errs *violations) {
+	switch {
+	case takenAt.IsZero():
+		errs.add("taken_at", "is required")
+	case p.MaxClockSkew >= 0 && takenAt.After(now.Add(p.MaxClockSkew)):
+		errs.add("taken_at", "is %s in the future (at most %s allowed)", takenAt.Sub(now).Round(time.Second), p.MaxClockSkew)
+	case p.MaxAge > 0 && takenAt.Before(now.Add(-p.MaxAge)):
+		errs.add("taken_at", "is more than %s old", days(p.MaxAge))
+	}
+}
+
+// QualityFlag marks a stored reading as possible but implausible, most
+// likely a measurement artifact.
+type QualityFlag string
+
+const (
+	// QualityNarrowPulsePressure is blood pressure whose systolic and
+	// diastolic are within 10 mmHg of each other.
+	QualityNarrowPulsePressure QualityFlag = "NARROW_PULSE_PRESSURE"
+	// QualityAtypicalValue is a value outside the range usually seen from
+	// a working home device, though not impossible.
+	QualityAtypicalValue QualityFlag = "ATYPICAL_VALUE"
+)
+
+var qualityFlagNames = []QualityFlag{QualityNarrowPulsePressure, QualityAtypicalValue}
+
+// ParseQualityFlag accepts flag names case-insensitively.
+func ParseQualityFlag(name string) (QualityFlag, bool) {
+	for _, flag := range qualityFlagNames {
+		if strings.EqualFold(name, string(flag)) {
+			return flag, true
+		}
+	}
+	return "", false
+}
+
+const minTypicalPulsePressure = 10
+
+// qualityFlags returns the flags for a reading that passed validation.
+func qualityFlags(v Vital) []QualityFlag {
+	var flags []QualityFlag
+	if v.Kind == VitalKindBloodPressure {
+		if v.Systolic-v.Diastolic < minTypicalPulsePressure {
+			flags = append(flags, QualityNarrowPulsePressure)
+		}
+		return flags
+	}
+	spec := vitalKindSpecs[v.Kind]
+	if (spec.typicalMin > 0 && v.Value < spec.typicalMin) || (spec.typicalMax > 0 && v.Value > spec.typicalMax) {
+		flags = append(flags, QualityAtypicalValue)
+	}
+	return flags
+}
+
+// QualityPolicy decides whether flagged readings may alert. By default a
+// flagged reading is stored but neither opens nor settles alerts.
+type QualityPolicy struct {
+	// AlertOn lists the flags that do not hold a reading back from alerting.
+	AlertOn []QualityFlag
+}
+
+// Evaluates reports whether the alert worker should evaluate v.
+func (p QualityPolicy) Evaluates(v Vital) bool {
+	for _, flag := range v.Flags {
+		if !slices.Contains(p.AlertOn, flag) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+// VitalKind is what a reading measures. The zero value is blood pressure so
+// readings from clients that predate kinds keep their meaning.
+type VitalKind int32
+
+const (
+	VitalKindBloodPressure VitalKind = 0
+	VitalKindPulse         VitalKind = 1
+	VitalKindSpO2          VitalKind = 2
+	VitalKindWeight        VitalKind = 3
+	VitalKindGlucose       VitalKind = 4
+)
+
+var vitalKinds = []VitalKind{
+	VitalKindBloodPressure,
+	VitalKindPulse,
+	VitalKindSpO2,
+	VitalKindWeight,
+	VitalKindGlucose,
+}
+
+func (k VitalKind) String() string {
+	switch k {
+	case VitalKindBloodPressure:
+		return "BLOOD_PRESSURE"
+	case VitalKindPulse:
+		return "PULSE"
+	case VitalKindSpO2:
+		return "SPO2"
+	case VitalKindWeight:
+		return "WEIGHT"
+	case VitalKindGlucose:
+		return "GLUCOSE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseVitalKind accepts the names String returns, case-insensitively.
+func ParseVitalKind(name string) (VitalKind, bool) {
+	for _, kind := range vitalKinds {
+		if strings.EqualFold(name, kind.String()) {
+			return kind, true
+		}
+	}
+	return 0, false
+}
+
+// vitalKindSpec is the canonical unit and ranges for a single-value kind.
+// Values outside [min, max] are impossible and rejected; values outside
+// [typicalMin, typicalMax] are stored with QualityAtypicalValue. A zero
+// typical bound is not checked.
+type vitalKindSpec struct {
+	unit                   string
+	min, max               float64
+	typicalMin, typicalMax float64
+	// conversions maps accepted alternative units to the factor that
+	// converts them to unit.
+	conversions map[string]float64
+}
+
+var vitalKindSpecs = map[VitalKind]vitalKindSpec{
+	VitalKindPulse:   {unit: "bpm", min: 20, max: 300, typicalMin: 30, typicalMax: 220},
+	VitalKindSpO2:    {unit: "%", min: 50, max: 100, typicalMin: 70},
+	VitalKindWeight:  {unit: "kg", min: 0.5, max: 500, conversions: map[string]float64{"lb": 0.45359237}},
+	VitalKindGlucose: {unit: "mg/dL", min: 10, max: 1000, typicalMin: 20, typicalMax: 600, conversions: map[string]float64{"mmol/L": 18.016}},
+}
+
+// Possible blood pressure bounds, in mmHg.
+const (
+	minPlausibleSystolic  = 40
+	maxPlausibleSystolic  = 300
+	minPlausibleDiastolic = 20
+	maxPlausibleDiastolic = 200
+)
+
+// Unit is the unit readings of this kind are stored in.
+func (k VitalKind) Unit() string {
+	if k == VitalKindBloodPressure {
+		return "mmHg"
+	}
+	return vitalKindSpecs[k].unit
+}
+
+// normalizeMeasurement checks a reading's measurement against its kind,
+// recording every problem in errs, and converts Value from unit, if set, to
+// the kind's canonical unit.
+func normalizeMeasurement(v Vital, unit string, errs *violations) Vital {
+	if v.Kind == VitalKindBloodPressure {
+		if v.Value != 0 {
+			errs.add("value", "must be unset for blood pressure; use systolic and diastolic")
+		}
+		if unit != "" && !strings.EqualFold(unit, "mmHg") {
+			errs.add("unit", "must be mmHg for blood pressure")
+		}
+		systolicOK := checkRange(errs, "systolic", v.Systolic, minPlausibleSystolic, maxPlausibleSystolic)
+		diastolicOK := checkRange(errs, "diastolic", v.Diastolic, minPlausibleDiastolic, maxPlausibleDiastolic)
+		if systolicOK && diastolicOK && v.Systolic <= v.Diastolic {
+			errs.add("diastolic", "%d must be lower than systolic %d", v.Diastolic, v.Systolic)
+		}
+		return v
+	}
+
+	spec, ok := vitalKindSpecs[v.Kind]
+	if !ok {
+		errs.add("kind", "unknown kind %d", v.Kind)
+		return v
+	}
+	if v.Systolic != 0 || v.Diastolic != 0 {
+		errs.add("systolic", "systolic and diastolic only apply to blood pressure")
+	}
+	if math.IsNaN(v.Value) || math.IsInf(v.Value, 0) {
+		errs.add("value", "must be a number")
+		return v
+	}
+	if unit != "" && !strings.EqualFold(unit, spec.unit) {
+		factor, ok := spec.conversion(unit)
+		if !ok {
+			errs.add("unit", "must be %s for %s", spec.acceptedUnits(), strings.ToLower(v.Kind.String()))
+			return v
+		}
+		v.Value = math.Round(v.Value*factor*100) / 100
+	}
+	if v.Value < spec.min || v.Value > spec.max {
+		errs.add("value", "%g %s is outside the possible range %g-%g", v.Value, spec.unit, spec.min, spec.max)
+	}
+	return v
+}
+
+func checkRange(errs *violations, field string, value, lo, hi int32) bool {
+	if value < lo || value > hi {
+		errs.add(field, "%d is outside the possible range %d-%d", value, lo, hi)
+		return false
+	}
+	return true
+}
+
+func (s vitalKindSpec) conversion(unit string) (float64, bool) {
+	for name, factor := range s.conversions {
+		if strings.EqualFold(unit, name) {
+			return factor, true
+		}
+	}
+	return 0, false
+}
+
+func (s vitalKindSpec) acceptedUnits() string {
+	return strings.Join(append([]string{s.unit}, slices.Sorted(maps.Keys(s.conversions))...), " or ")
+}
+
+// Measurement formats the reading for people, e.g. "120/80 mmHg".
+func (v Vital) Measurement() string {
+	if v.Kind == VitalKindBloodPressure {
+		return fmt.Sprintf("%d/%d mmHg", v.Systolic, v.Diastolic)
+	}
+	return fmt.Sprintf("%g %s", v.Value, v.Kind.Unit())
+}
@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"cadence-vitals-interview/internal/app"
+)
+
+// StaticDirectory is a Directory loaded from a JSON contacts file:
+//
+//	{
+//	  "default_channels": ["sms", "in_app"],
+//	  "default_time_zone": "America/New_York",
+//	  "default_quiet_hours": "22:00-07:00",
+//	  "patients": {
+//	    "patient-1": {"phone": "+15555550100", "email": "pat@example.com", "channels": ["sms", "email", "in_app"], "language": "es", "time_zone": "America/Los_Angeles"}
+//	  },
+//	  "care_team": {
+//	    "on_call_nurse": {"webhook_url": "https://pager.example.com/hook", "channels": ["webhook", "in_app"]}
+//	  }
+//	}
+//
+// Contacts without channels of their own use default_channels, which
+// default to in_app. Recipients missing from the file get only the default
+// channels and so, unless those include in_app, can't be reached.
+//
+// Patients without a time zone or quiet hours of their own use the
+// defaults. Care team members have quiet hours only if their own entry sets
+// them, since they are paged about alerts that need an answer.
+type StaticDirectory struct {
+	DefaultChannels   []string           `json:"default_channels"`
+	DefaultTimeZone   string             `json:"default_time_zone"`
+	DefaultQuietHours string             `json:"default_quiet_hours"`
+	Patients          map[string]Contact `json:"patients"`
+	// CareTeam is keyed by recipient name, such as on_call_nurse.
+	CareTeam map[string]Contact `json:"care_team"`
+}
+
+func LoadDirectory(path string) (*StaticDirectory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var d StaticDirectory
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if _, err := quietHours(d.DefaultTimeZone, d.DefaultQuietHours); err != nil {
+		return nil, fmt.Errorf("%s: defaults: %w", path, err)
+	}
+	for id, c := range d.Patients {
+		if _, err := quietHours(cmp.Or(c.TimeZone, d.DefaultTimeZone), c.QuietHours); err != nil {
+			return nil, fmt.Errorf("%s: patient %s: %w", path, id, err)
+		}
+	}
+	for name, c := range d.CareTeam {
+		if recipient, ok := app.ParseRecipient(name); !ok || recipient == app.RecipientPatient {
+			return nil, fmt.Errorf("%s: unknown care team member %q (want on_call_nurse or physician)", path, name)
+		}
+		if _, err := quietHours(c.TimeZone, c.QuietHours); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, name, err)
+		}
+	}
+	return &d, nil
+}
+
+func (d *StaticDirectory) Lookup(patientID string, recipient app.Recipient) Contact {
+	contact := d.Patients[patientID]
+	if recipient != app.RecipientPatient {
+		contact = Contact{}
+		for name, c := range d.CareTeam {
+			if r, ok := app.ParseRecipient(name); ok && r == recipient {
+				contact = c
+			}
+		}
+	}
+	if len(contact.Channels) == 0 {
+		contact.Channels = d.DefaultChannels
+	}
+	if len(contact.Channels) == 0 {
+		contact.Channels = []string{ChannelInApp}
+	}
+	return contact
+}
+
+// Locale makes a StaticDirectory an app.LocaleDirectory.
+func (d *StaticDirectory) Locale(patientID string, recipient app.Recipient) string {
+	return d.Lookup(patientID, recipient).Language
+}
+
+// QuietHours makes a StaticDirectory an app.QuietHoursDirectory. Settings
+// LoadDirectory would reject count as no quiet hours.
+func (d *StaticDirectory) QuietHours(patientID string, recipient app.Recipient) app.QuietHours {
+	contact := d.Lookup(patientID, recipient)
+	zone, window := contact.TimeZone, contact.QuietHours
+	if recipient == app.RecipientPatient {
+		zone, window = cmp.Or(zone, d.DefaultTimeZone), cmp.Or(window, d.DefaultQuietHours)
+	}
+	hours, _ := quietHours(zone, window)
+	return hours
+}
+
+func quietHours(zone, window string) (app.QuietHours, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return app.QuietHours{}, fmt.Errorf("time zone: %w", err)
+	}
+	return app.ParseQuietHours(window, loc)
+}
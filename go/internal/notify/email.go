@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"cadence-vitals-interview/internal/app"
+)
+
+// SMTPConfig configures email delivery through an SMTP relay.
+type SMTPConfig struct {
+	// Addr is the relay's host:port.
+	Addr string
+	From string
+	// Username and Password enable PLAIN auth, which net/smtp only allows
+	// over TLS or to localhost.
+	Username string
+	Password string
+	// TLS configures STARTTLS. Nil verifies the relay's certificate for
+	// the host in Addr against the system roots.
+	TLS *tls.Config
+	// AllowPlaintext sends over an unencrypted connection to a relay that
+	// doesn't offer STARTTLS. Without it only a relay on localhost may.
+	AllowPlaintext bool
+}
+
+// ErrSTARTTLSUnavailable means the relay didn't offer STARTTLS, so the email
+// would have gone out in plaintext.
+var ErrSTARTTLSUnavailable = errors.New("smtp relay does not offer STARTTLS")
+
+type Email struct {
+	cfg  SMTPConfig
+	host string
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+func NewEmail(cfg SMTPConfig) *Email {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		host = cfg.Addr
+	}
+	return &Email{cfg: cfg, host: host, dial: (&net.Dialer{}).DialContext}
+}
+
+func (e *Email) Name() string { return ChannelEmail }
+
+func (e *Email) Reaches(contact Contact) bool { return contact.Email != "" }
+
+// Send returns the Message-ID it gave the email; SMTP relays don't report
+// an ID of their own.
+func (e *Email) Send(ctx context.Context, contact Contact, msg app.Message) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	conn, err := e.dial(ctx, "tcp", e.cfg.Addr)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, e.host)
+	if err != nil {
+		return "", err
+	}
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(e.tlsConfig()); err != nil {
+			return "", fmt.Errorf("starttls: %w", err)
+		}
+	} else if !e.cfg.AllowPlaintext && !isLocalhost(e.host) {
+		return "", fmt.Errorf("%w: %s", ErrSTARTTLSUnavailable, e.cfg.Addr)
+	}
+	if e.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.host)); err != nil {
+			return "", fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	messageID := fmt.Sprintf("<cadence-%d-%d@%s>", msg.ID, time.Now().UnixNano(), mailDomain(e.cfg.From))
+	if err := c.Mail(e.cfg.From); err != nil {
+		return "", err
+	}
+	if err := c.Rcpt(contact.Email); err != nil {
+		return "", err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(formatEmail(e.cfg.From, contact.Email, messageID, msg)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	c.Quit()
+	return messageID, nil
+}
+
+func (e *Email) tlsConfig() *tls.Config {
+	cfg := &tls.Config{}
+	if e.cfg.TLS != nil {
+		cfg = e.cfg.TLS.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = e.host
+	}
+	return cfg
+}
+
+// formatEmail titles the email with msg.Subject, which the message
+// composer renders in the recipient's language. A message queued without
+// one is titled with the first line of its content.
+func formatEmail(from, to, messageID string, msg app.Message) []byte {
+	subject := msg.Subject
+	if subject == "" {
+		subject, _, _ = strings.Cut(msg.Content, "\n")
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Message-ID: %s\r\n", messageID)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Content, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
+
+// isLocalhost reports whether host is this machine, which net/smtp also
+// trusts with credentials in plaintext.
+func isLocalhost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func mailDomain(addr string) string {
+	if _, domain, ok := strings.Cut(addr, "@"); ok {
+		return strings.TrimSuffix(domain, ">")
+	}
+	return "localhost"
+}
@@ -0,0 +1,14 @@
+package notify
+
+import (
+	"context"
+	"net"
+)
+
+// DialVia makes e connect to addr whatever relay its config names, so tests
+// can put a relay that isn't on localhost in front of a local fake server.
+func DialVia(e *Email, addr string) {
+	e.dial = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+}
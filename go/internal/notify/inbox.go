@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"cadence-vitals-interview/internal/app"
+)
+
+// InboxMessage is a message delivered to an in-app inbox.
+type InboxMessage struct {
+	ID        string    `json:"id"`
+	MessageID int64     `json:"message_id"`
+	PatientID string    `json:"patient_id"`
+	AlertID   int64     `json:"alert_id,omitempty"`
+	Content   string    `json:"content"`
+	At        time.Time `json:"at"`
+}
+
+// Inbox is the in-app channel: it keeps delivered messages in memory for
+// the patient's app, or a care team member's, to fetch. It reaches every
+// recipient, so it makes a good last fallback.
+type Inbox struct {
+	mu      sync.Mutex
+	seq     int64
+	inboxes map[string][]InboxMessage
+}
+
+func NewInbox() *Inbox {
+	return &Inbox{inboxes: make(map[string][]InboxMessage)}
+}
+
+func (i *Inbox) Name() string { return ChannelInApp }
+
+func (i *Inbox) Reaches(Contact) bool { return true }
+
+func (i *Inbox) Send(_ context.Context, _ Contact, msg app.Message) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.seq++
+	id := fmt.Sprintf("inapp-%d", i.seq)
+	owner := InboxOwner(msg)
+	i.inboxes[owner] = append(i.inboxes[owner], InboxMessage{
+		ID:        id,
+		MessageID: msg.ID,
+		PatientID: msg.PatientID,
+		AlertID:   msg.AlertID,
+		Content:   msg.Content,
+		At:        time.Now(),
+	})
+	return id, nil
+}
+
+// InboxOwner is whose inbox msg goes to: the patient's ID, or the care team
+// member's recipient name in lower case, such as on_call_nurse.
+func InboxOwner(msg app.Message) string {
+	if msg.Recipient == app.RecipientPatient {
+		return msg.PatientID
+	}
+	return strings.ToLower(msg.Recipient.String())
+}
+
+// Messages returns owner's inbox, oldest first.
+func (i *Inbox) Messages(owner string) []InboxMessage {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return append([]InboxMessage(nil), i.inboxes[owner]...)
+}
+
+// Handler serves GET /inbox/{owner} with the owner's messages.
+func (i *Inbox) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /inbox/{owner}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		messages := i.Messages(r.PathValue("owner"))
+		if messages == nil {
+			messages = []InboxMessage{}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"messages": messages})
+	})
+	return mux
+}
@@ -0,0 +1,102 @@
+// Package notify delivers queued messages to patients and the care team
+// over SMS, email, webhooks and an in-app inbox.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cadence-vitals-interview/internal/app"
+)
+
+const (
+	ChannelSMS     = "sms"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelInApp   = "in_app"
+)
+
+// sendTimeout bounds one delivery attempt, so a provider that hangs can't
+// hold up a message worker.
+const sendTimeout = 30 * time.Second
+
+var ErrNoChannel = errors.New("no notification channel reaches the recipient")
+
+// Channel delivers messages over one medium.
+type Channel interface {
+	// Name is the name contacts use to prefer the channel.
+	Name() string
+	// Reaches reports whether contact has the details the channel needs,
+	// such as a phone number for SMS.
+	Reaches(contact Contact) bool
+	// Send delivers msg to contact and returns the provider's ID for it.
+	Send(ctx context.Context, contact Contact, msg app.Message) (string, error)
+}
+
+// Contact is how to reach one recipient.
+type Contact struct {
+	Phone      string `json:"phone,omitempty"`
+	Email      string `json:"email,omitempty"`
+	WebhookURL string `json:"webhook_url,omitempty"`
+	// Channels names the channels to try, in order of preference; each
+	// later one is a fallback for when the earlier ones fail.
+	Channels []string `json:"channels,omitempty"`
+	// Language is the locale messages are written in, such as "es";
+	// empty means English.
+	Language string `json:"language,omitempty"`
+	// TimeZone is an IANA zone name, such as "America/Chicago", that
+	// QuietHours is read in.
+	TimeZone string `json:"time_zone,omitempty"`
+	// QuietHours is a local "HH:MM-HH:MM" window, such as "22:00-07:00",
+	// during which only critical messages are sent.
+	QuietHours string `json:"quiet_hours,omitempty"`
+}
+
+// Directory finds the contact for a message's recipient: the patient, or
+// the care team member the message is for.
+type Directory interface {
+	Lookup(patientID string, recipient app.Recipient) Contact
+}
+
+// Router is an app.MessageSender that delivers each message over the
+// recipient's preferred channel, falling back to the next one when a
+// channel fails. A message fails only if every channel does.
+type Router struct {
+	directory Directory
+	channels  map[string]Channel
+}
+
+func NewRouter(directory Directory, channels ...Channel) *Router {
+	r := &Router{directory: directory, channels: make(map[string]Channel, len(channels))}
+	for _, ch := range channels {
+		r.channels[ch.Name()] = ch
+	}
+	return r
+}
+
+func (r *Router) Send(ctx context.Context, msg app.Message) (app.Delivery, error) {
+	contact := r.directory.Lookup(msg.PatientID, msg.Recipient)
+	var errs []error
+	for _, name := range contact.Channels {
+		ch, ok := r.channels[name]
+		if !ok || !ch.Reaches(contact) {
+			continue
+		}
+		id, err := ch.Send(ctx, contact, msg)
+		if err == nil {
+			return app.Delivery{Channel: name, ProviderMessageID: id}, nil
+		}
+		if ctx.Err() != nil {
+			return app.Delivery{}, ctx.Err()
+		}
+		log.Printf("notify: message %d over %s failed: %v", msg.ID, name, err)
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+	if len(errs) == 0 {
+		return app.Delivery{}, fmt.Errorf("%w: %s for %s", ErrNoChannel, msg.Recipient, msg.PatientID)
+	}
+	return app.Delivery{}, errors.Join(errs...)
+}
@@ -0,0 +1,316 @@
+package notify_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cadence-vitals-interview/internal/app"
+	"cadence-vitals-interview/internal/notify"
+	"cadence-vitals-interview/internal/notify/notifytest"
+)
+
+type fixedDirectory map[app.Recipient]notify.Contact
+
+func (d fixedDirectory) Lookup(_ string, recipient app.Recipient) notify.Contact {
+	return d[recipient]
+}
+
+func startSMS(t *testing.T) (*notifytest.SMSProvider, *notify.SMS) {
+	t.Helper()
+	provider := notifytest.NewSMSProvider("ACtest", "secret")
+	server := httptest.NewServer(provider)
+	t.Cleanup(server.Close)
+	return provider, notify.NewSMS(notify.TwilioConfig{BaseURL: server.URL, AccountSID: "ACtest", AuthToken: "secret", From: "+15555550000"})
+}
+
+func startSMTP(t *testing.T) (*notifytest.SMTPServer, *notify.Email) {
+	t.Helper()
+	server := notifytest.NewSMTPServer()
+	if err := server.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("start smtp: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server, notify.NewEmail(notify.SMTPConfig{Addr: server.Addr(), From: "alerts@cadence.example"})
+}
+
+func TestQueueDeliversOverPreferredChannelAndFallsBack(t *testing.T) {
+	sms, smsChannel := startSMS(t)
+	smtp, emailChannel := startSMTP(t)
+	directory := fixedDirectory{app.RecipientPatient: {
+		Phone:    "+15555550100",
+		Email:    "pat@example.com",
+		Channels: []string{notify.ChannelSMS, notify.ChannelEmail},
+	}}
+	queue := app.NewMessageQueue(0, 0, app.WithMessageSender(notify.NewRouter(directory, smsChannel, emailChannel)))
+	ctx := context.Background()
+
+	queue.Enqueue(app.Message{PatientID: "patient-1", AlertID: 1, Content: "Please retake your vitals."})
+	msg := queue.ProcessNext(ctx)
+	if msg == nil || msg.Status != app.MessageStatusSent || msg.Channel != notify.ChannelSMS || msg.ProviderMessageID == "" {
+		t.Fatalf("expected delivery over sms, got %+v", msg)
+	}
+	if got := sms.Received(); len(got) != 1 || got[0].To != "+15555550100" || got[0].SID != msg.ProviderMessageID ||
+		got[0].Body != "Please retake your vitals." {
+		t.Fatalf("unexpected sms: %+v", got)
+	}
+
+	sms.FailNext(1)
+	queue.Enqueue(app.Message{PatientID: "patient-1", AlertID: 2, Content: "Please retake again."})
+	msg = queue.ProcessNext(ctx)
+	if msg == nil || msg.Status != app.MessageStatusSent || msg.Channel != notify.ChannelEmail {
+		t.Fatalf("expected fallback to email, got %+v", msg)
+	}
+	emails := smtp.Received()
+	if len(emails) != 1 || emails[0].To[0] != "pat@example.com" || !strings.Contains(emails[0].Data, "Message-ID: "+msg.ProviderMessageID) ||
+		!strings.Contains(emails[0].Data, "Please retake again.") {
+		t.Fatalf("unexpected email: %+v", emails)
+	}
+}
+
+func TestEmailUsesMessageSubject(t *testing.T) {
+	server, email := startSMTP(t)
+	contact := notify.Contact{Email: "pat@example.com"}
+	msg := app.Message{ID: 1, PatientID: "patient-1", Subject: "Urgente: vuelva a medir su presión arterial", Content: "Alerta urgente."}
+	if _, err := email.Send(context.Background(), contact, msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	received := server.Received()
+	if len(received) != 1 {
+		t.Fatalf("expected one email, got %d", len(received))
+	}
+	parsed, err := mail.ReadMessage(strings.NewReader(received[0].Data))
+	if err != nil {
+		t.Fatalf("parse email: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Fatalf("expected subject %q, got %q (%v)", msg.Subject, subject, err)
+	}
+}
+
+func TestEmailUsesSTARTTLS(t *testing.T) {
+	serverTLS, roots := selfSignedTLS(t)
+	server := notifytest.NewSMTPServer()
+	server.TLS = serverTLS
+	if err := server.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("start smtp: %v", err)
+	}
+	defer server.Close()
+	contact := notify.Contact{Email: "pat@example.com"}
+	msg := app.Message{ID: 1, PatientID: "patient-1", Content: "Please retake your vitals."}
+
+	email := notify.NewEmail(notify.SMTPConfig{Addr: server.Addr(), From: "alerts@cadence.example", TLS: &tls.Config{RootCAs: roots}})
+	if _, err := email.Send(context.Background(), contact, msg); err != nil {
+		t.Fatalf("send over starttls: %v", err)
+	}
+	if got := server.Received(); len(got) != 1 || !got[0].TLS {
+		t.Fatalf("expected the email to arrive over TLS, got %+v", got)
+	}
+
+	// By default the relay's certificate is verified for its host name.
+	untrusted := notify.NewEmail(notify.SMTPConfig{Addr: server.Addr(), From: "alerts@cadence.example"})
+	if _, err := untrusted.Send(context.Background(), contact, msg); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Fatalf("expected an untrusted certificate error, got %v", err)
+	}
+}
+
+func TestEmailRefusesPlaintextToRemoteRelay(t *testing.T) {
+	server := notifytest.NewSMTPServer()
+	if err := server.Start("127.0.0.1:0"); err != nil {
+		t.Fatalf("start smtp: %v", err)
+	}
+	defer server.Close()
+	contact := notify.Contact{Email: "pat@example.com"}
+	msg := app.Message{ID: 1, PatientID: "patient-1", Content: "Please retake your vitals."}
+
+	// The fake server doesn't offer STARTTLS; pose as a remote relay.
+	email := notify.NewEmail(notify.SMTPConfig{Addr: "mail.example.com:25", From: "alerts@cadence.example"})
+	notify.DialVia(email, server.Addr())
+	if _, err := email.Send(context.Background(), contact, msg); !errors.Is(err, notify.ErrSTARTTLSUnavailable) {
+		t.Fatalf("expected ErrSTARTTLSUnavailable, got %v", err)
+	}
+	if got := server.Received(); len(got) != 0 {
+		t.Fatalf("expected nothing sent in plaintext, got %+v", got)
+	}
+
+	plaintext := notify.NewEmail(notify.SMTPConfig{Addr: "mail.example.com:25", From: "alerts@cadence.example", AllowPlaintext: true})
+	notify.DialVia(plaintext, server.Addr())
+	if _, err := plaintext.Send(context.Background(), contact, msg); err != nil {
+		t.Fatalf("send with AllowPlaintext: %v", err)
+	}
+	if got := server.Received(); len(got) != 1 || got[0].TLS {
+		t.Fatalf("expected one plaintext email, got %+v", got)
+	}
+}
+
+// selfSignedTLS returns a server config with a certificate for 127.0.0.1
+// and the pool that trusts it.
+func selfSignedTLS(t *testing.T) (*tls.Config, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, roots
+}
+
+func TestRouterFailsWhenNoChannelDelivers(t *testing.T) {
+	sms, smsChannel := startSMS(t)
+	sms.FailNext(1)
+	router := notify.NewRouter(fixedDirectory{
+		app.RecipientPatient:     {Phone: "+15555550100", Channels: []string{notify.ChannelSMS, notify.ChannelEmail}},
+		app.RecipientOnCallNurse: {Channels: []string{notify.ChannelSMS}},
+	}, smsChannel)
+
+	_, err := router.Send(context.Background(), app.Message{PatientID: "patient-1", Content: "retake"})
+	if err == nil || !strings.Contains(err.Error(), "sms: provider returned 503") {
+		t.Fatalf("expected the sms failure, got %v", err)
+	}
+	// The nurse has no phone number, so nothing can reach them.
+	_, err = router.Send(context.Background(), app.Message{PatientID: "patient-1", Recipient: app.RecipientOnCallNurse})
+	if !errors.Is(err, notify.ErrNoChannel) {
+		t.Fatalf("expected ErrNoChannel, got %v", err)
+	}
+}
+
+func TestSMSRejectsBadCredentials(t *testing.T) {
+	provider := notifytest.NewSMSProvider("ACtest", "secret")
+	server := httptest.NewServer(provider)
+	defer server.Close()
+	channel := notify.NewSMS(notify.TwilioConfig{BaseURL: server.URL, AccountSID: "ACtest", AuthToken: "wrong"})
+
+	_, err := channel.Send(context.Background(), notify.Contact{Phone: "+15555550100"}, app.Message{Content: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "Authenticate") {
+		t.Fatalf("expected an auth error, got %v", err)
+	}
+	if got := provider.Received(); len(got) != 0 {
+		t.Fatalf("expected nothing sent, got %+v", got)
+	}
+}
+
+func TestWebhookSignsPayload(t *testing.T) {
+	receiver := notifytest.NewWebhookReceiver("hook-secret")
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	contact := notify.Contact{WebhookURL: server.URL}
+	msg := app.Message{ID: 4, PatientID: "patient-1", Recipient: app.RecipientPhysician, AlertID: 9, Content: "Escalation"}
+
+	id, err := notify.NewWebhook(notify.WebhookConfig{Secret: "hook-secret"}).Send(context.Background(), contact, msg)
+	if err != nil || id != "hook-1" {
+		t.Fatalf("expected acknowledged webhook, got %q (%v)", id, err)
+	}
+	got := receiver.Received()
+	if len(got) != 1 || got[0].Payload.MessageID != 4 || got[0].Payload.Recipient != "PHYSICIAN" || got[0].Payload.AlertID != 9 {
+		t.Fatalf("unexpected webhook payload: %+v", got)
+	}
+
+	if _, err := notify.NewWebhook(notify.WebhookConfig{Secret: "other"}).Send(context.Background(), contact, msg); err == nil {
+		t.Fatal("expected a badly signed webhook to be rejected")
+	}
+}
+
+func TestInboxKeepsMessagesPerRecipient(t *testing.T) {
+	inbox := notify.NewInbox()
+	ctx := context.Background()
+	inbox.Send(ctx, notify.Contact{}, app.Message{ID: 1, PatientID: "patient-1", Content: "retake"})
+	inbox.Send(ctx, notify.Contact{}, app.Message{ID: 2, PatientID: "patient-1", Recipient: app.RecipientOnCallNurse, Content: "escalation"})
+
+	rec := httptest.NewRecorder()
+	inbox.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/inbox/on_call_nurse", nil))
+	var resp struct {
+		Messages []notify.InboxMessage `json:"messages"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode inbox: %v", err)
+	}
+	if len(resp.Messages) != 1 || resp.Messages[0].MessageID != 2 || resp.Messages[0].PatientID != "patient-1" {
+		t.Fatalf("unexpected nurse inbox: %+v", resp.Messages)
+	}
+	if got := inbox.Messages("patient-1"); len(got) != 1 || got[0].Content != "retake" {
+		t.Fatalf("unexpected patient inbox: %+v", got)
+	}
+}
+
+func TestLoadDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.json")
+	os.WriteFile(path, []byte(`{
+		"default_quiet_hours": "22:00-07:00",
+		"patients": {"patient-1": {"phone": "+15555550100", "channels": ["sms", "in_app"], "language": "es", "time_zone": "America/Denver"}},
+		"care_team": {"physician": {"email": "doc@example.com", "channels": ["email"]}}
+	}`), 0o600)
+
+	directory, err := notify.LoadDirectory(path)
+	if err != nil {
+		t.Fatalf("load directory: %v", err)
+	}
+	if got := directory.Lookup("patient-1", app.RecipientPatient); got.Phone != "+15555550100" || len(got.Channels) != 2 {
+		t.Fatalf("unexpected patient contact: %+v", got)
+	}
+	if got := directory.Locale("patient-1", app.RecipientPatient); got != "es" {
+		t.Fatalf("expected the patient's language, got %q", got)
+	}
+	// 04:30 UTC is 22:30 the evening before in Denver; 23:00 UTC is 17:00.
+	hours := directory.QuietHours("patient-1", app.RecipientPatient)
+	if got := hours.Until(time.Date(2025, 6, 2, 4, 30, 0, 0, time.UTC)); !got.Equal(time.Date(2025, 6, 2, 13, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected quiet hours to end at 07:00 Denver time, got %s", got)
+	}
+	if got := hours.Until(time.Date(2025, 6, 2, 23, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Fatalf("expected no quiet hours in the afternoon, got %s", got)
+	}
+	if got := directory.QuietHours("patient-1", app.RecipientPhysician); got.String() != "" {
+		t.Fatalf("expected the care team to have no default quiet hours, got %q", got)
+	}
+	if got := directory.Lookup("patient-1", app.RecipientPhysician); got.Email != "doc@example.com" {
+		t.Fatalf("expected the physician's contact for their messages, got %+v", got)
+	}
+	if got := directory.Lookup("patient-2", app.RecipientPatient); len(got.Channels) != 1 || got.Channels[0] != notify.ChannelInApp {
+		t.Fatalf("expected unknown patients to get the in-app default, got %+v", got)
+	}
+
+	os.WriteFile(path, []byte(`{"patients": {"patient-1": {"time_zone": "Mars/Olympus_Mons"}}}`), 0o600)
+	if _, err := notify.LoadDirectory(path); err == nil || !strings.Contains(err.Error(), "patient-1") {
+		t.Fatalf("expected an unknown time zone to be rejected, got %v", err)
+	}
+
+	os.WriteFile(path, []byte(`{"care_team": {"janitor": {}}}`), 0o600)
+	if _, err := notify.LoadDirectory(path); err == nil || !strings.Contains(err.Error(), "janitor") {
+		t.Fatalf("expected unknown care team member to be rejected, got %v", err)
+	}
+}
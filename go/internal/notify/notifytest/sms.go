@@ -0,0 +1,93 @@
+// Package notifytest provides fake SMS, SMTP and webhook servers for
+// exercising the notify channels offline, in tests and with
+// cmd/fakenotify.
+package notifytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// SMS is a text message the fake SMS provider accepted.
+type SMS struct {
+	SID  string
+	To   string
+	From string
+	Body string
+}
+
+// SMSProvider fakes Twilio's Messages API: it accepts POST
+// /2010-04-01/Accounts/{sid}/Messages.json with the account's credentials.
+type SMSProvider struct {
+	AccountSID string
+	AuthToken  string
+	// Logf, if set, is called for each message received.
+	Logf func(format string, args ...any)
+
+	mu       sync.Mutex
+	received []SMS
+	failures int
+}
+
+func NewSMSProvider(accountSID, authToken string) *SMSProvider {
+	return &SMSProvider{AccountSID: accountSID, AuthToken: authToken}
+}
+
+// FailNext makes the next n requests fail with 503.
+func (p *SMSProvider) FailNext(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures = n
+}
+
+// Received returns the messages accepted so far, oldest first.
+func (p *SMSProvider) Received() []SMS {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]SMS(nil), p.received...)
+}
+
+func (p *SMSProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodPost || r.URL.Path != "/2010-04-01/Accounts/"+p.AccountSID+"/Messages.json" {
+		writeTwilioError(w, http.StatusNotFound, 20404, "The requested resource was not found")
+		return
+	}
+	if sid, token, ok := r.BasicAuth(); !ok || sid != p.AccountSID || token != p.AuthToken {
+		writeTwilioError(w, http.StatusUnauthorized, 20003, "Authenticate")
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("To") == "" || r.PostForm.Get("Body") == "" {
+		writeTwilioError(w, http.StatusBadRequest, 21604, "A 'To' phone number and 'Body' are required")
+		return
+	}
+
+	p.mu.Lock()
+	if p.failures > 0 {
+		p.failures--
+		p.mu.Unlock()
+		writeTwilioError(w, http.StatusServiceUnavailable, 20503, "Service unavailable")
+		return
+	}
+	sms := SMS{
+		SID:  fmt.Sprintf("SM%032d", len(p.received)+1),
+		To:   r.PostForm.Get("To"),
+		From: r.PostForm.Get("From"),
+		Body: r.PostForm.Get("Body"),
+	}
+	p.received = append(p.received, sms)
+	p.mu.Unlock()
+
+	if p.Logf != nil {
+		p.Logf("[sms] %s -> %s: %s", sms.SID, sms.To, sms.Body)
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"sid": sms.SID, "status": "queued", "to": sms.To, "body": sms.Body})
+}
+
+func writeTwilioError(w http.ResponseWriter, status, code int, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"code": code, "message": message, "status": status})
+}
@@ -0,0 +1,209 @@
+package notifytest
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Email is a message the fake SMTP server accepted.
+type Email struct {
+	From string
+	To   []string
+	// Data is the raw message, headers and body, with CRLF line endings.
+	Data string
+	// TLS reports whether the message was sent after STARTTLS.
+	TLS bool
+}
+
+// SMTPServer is a minimal SMTP server that accepts every message without
+// auth and keeps it in memory.
+type SMTPServer struct {
+	// Logf, if set, is called for each message received.
+	Logf func(format string, args ...any)
+	// TLS, if set before Start, makes the server offer STARTTLS.
+	TLS *tls.Config
+
+	listener net.Listener
+	mu       sync.Mutex
+	received []Email
+	failures int
+	wg       sync.WaitGroup
+}
+
+func NewSMTPServer() *SMTPServer {
+	return &SMTPServer{}
+}
+
+// Start listens on addr, "127.0.0.1:0" for any free port, and serves in the
+// background until Close.
+func (s *SMTPServer) Start(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.listener = l
+	s.wg.Add(1)
+	go s.serve()
+	return nil
+}
+
+func (s *SMTPServer) Addr() string { return s.listener.Addr().String() }
+
+// Close stops accepting connections and waits for open ones to finish.
+func (s *SMTPServer) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// FailNext makes the next n messages be rejected at the end of DATA.
+func (s *SMTPServer) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// Received returns the messages accepted so far, oldest first.
+func (s *SMTPServer) Received() []Email {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Email(nil), s.received...)
+}
+
+func (s *SMTPServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) && s.Logf != nil {
+				s.Logf("[smtp] accept: %v", err)
+			}
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.session(conn)
+		}()
+	}
+}
+
+func (s *SMTPServer) session(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	reply := func(format string, args ...any) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+	reply("220 fakenotify ESMTP ready")
+
+	var (
+		mail   Email
+		secure bool
+	)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			if s.TLS != nil && !secure {
+				reply("250-fakenotify")
+				reply("250 STARTTLS")
+				continue
+			}
+			reply("250 fakenotify")
+		case "STARTTLS":
+			if s.TLS == nil || secure {
+				reply("502 Command not implemented")
+				continue
+			}
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.TLS)
+			if err := tlsConn.Handshake(); err != nil {
+				if s.Logf != nil {
+					s.Logf("[smtp] tls handshake: %v", err)
+				}
+				return
+			}
+			conn, r, secure = tlsConn, bufio.NewReader(tlsConn), true
+			mail = Email{}
+		case "MAIL":
+			mail = Email{From: addressArg(arg), TLS: secure}
+			reply("250 OK")
+		case "RCPT":
+			mail.To = append(mail.To, addressArg(arg))
+			reply("250 OK")
+		case "DATA":
+			if len(mail.To) == 0 {
+				reply("503 RCPT first")
+				continue
+			}
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(r)
+			if err != nil {
+				return
+			}
+			mail.Data = data
+			reply(s.accept(mail))
+			mail = Email{}
+		case "RSET":
+			mail = Email{}
+			reply("250 OK")
+		case "NOOP":
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *SMTPServer) accept(mail Email) string {
+	s.mu.Lock()
+	if s.failures > 0 {
+		s.failures--
+		s.mu.Unlock()
+		return "451 Try again later"
+	}
+	s.received = append(s.received, mail)
+	n := len(s.received)
+	s.mu.Unlock()
+
+	if s.Logf != nil {
+		s.Logf("[smtp] message %d from %s to %s:\n%s", n, mail.From, strings.Join(mail.To, ", "), mail.Data)
+	}
+	return fmt.Sprintf("250 OK queued as %d", n)
+}
+
+// readData reads a DATA section up to the lone "." line, undoing dot
+// stuffing.
+func readData(r *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" || line == ".\n" {
+			return b.String(), nil
+		}
+		b.WriteString(strings.TrimPrefix(line, "."))
+	}
+}
+
+// addressArg extracts the address from "FROM:<a@b>" or "TO:<a@b>".
+func addressArg(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	addr, _, _ = strings.Cut(strings.TrimSpace(addr), " ")
+	return strings.Trim(addr, "<>")
+}
@@ -0,0 +1,88 @@
+package notifytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"cadence-vitals-interview/internal/notify"
+)
+
+// WebhookCall is a request the fake webhook receiver accepted.
+type WebhookCall struct {
+	Payload   notify.WebhookPayload
+	Signature string
+}
+
+// WebhookReceiver fakes a webhook endpoint: it accepts POSTed message
+// payloads and acknowledges each with an id.
+type WebhookReceiver struct {
+	// Secret, if set, makes the receiver reject requests without a valid
+	// signature.
+	Secret string
+	// Logf, if set, is called for each payload received.
+	Logf func(format string, args ...any)
+
+	mu       sync.Mutex
+	received []WebhookCall
+	failures int
+}
+
+func NewWebhookReceiver(secret string) *WebhookReceiver {
+	return &WebhookReceiver{Secret: secret}
+}
+
+// FailNext makes the next n requests fail with 500.
+func (h *WebhookReceiver) FailNext(n int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failures = n
+}
+
+// Received returns the calls accepted so far, oldest first.
+func (h *WebhookReceiver) Received() []WebhookCall {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]WebhookCall(nil), h.received...)
+}
+
+func (h *WebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	signature := r.Header.Get(notify.SignatureHeader)
+	if h.Secret != "" && signature != notify.Sign(h.Secret, body) {
+		http.Error(w, "bad signature", http.StatusUnauthorized)
+		return
+	}
+	var payload notify.WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	if h.failures > 0 {
+		h.failures--
+		h.mu.Unlock()
+		http.Error(w, "receiver unavailable", http.StatusInternalServerError)
+		return
+	}
+	h.received = append(h.received, WebhookCall{Payload: payload, Signature: signature})
+	id := fmt.Sprintf("hook-%d", len(h.received))
+	h.mu.Unlock()
+
+	if h.Logf != nil {
+		h.Logf("[webhook] %s for %s (%s): %s", id, payload.PatientID, payload.Recipient, payload.Content)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"id": id})
+}
@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"cadence-vitals-interview/internal/app"
+)
+
+const defaultTwilioURL = "https://api.twilio.com"
+
+// TwilioConfig configures SMS delivery through Twilio's Messages API or a
+// compatible one.
+type TwilioConfig struct {
+	// BaseURL defaults to Twilio's; point it at another provider or a fake.
+	BaseURL    string
+	AccountSID string
+	AuthToken  string
+	// From is the sending phone number.
+	From string
+	// Client defaults to one that gives up after 30 seconds.
+	Client *http.Client
+}
+
+type SMS struct {
+	cfg TwilioConfig
+}
+
+func NewSMS(cfg TwilioConfig) *SMS {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultTwilioURL
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: sendTimeout}
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &SMS{cfg: cfg}
+}
+
+func (s *SMS) Name() string { return ChannelSMS }
+
+func (s *SMS) Reaches(contact Contact) bool { return contact.Phone != "" }
+
+func (s *SMS) Send(ctx context.Context, contact Contact, msg app.Message) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.cfg.BaseURL, url.PathEscape(s.cfg.AccountSID))
+	form := url.Values{"To": {contact.Phone}, "From": {s.cfg.From}, "Body": {msg.Content}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		SID     string `json:"sid"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode < 300 {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("provider returned %s: %s (code %d)", resp.Status, body.Message, body.Code)
+	}
+	return body.SID, nil
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"cadence-vitals-interview/internal/app"
+)
+
+// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the request
+// body when the webhook channel has a secret.
+const SignatureHeader = "X-Cadence-Signature"
+
+// WebhookConfig configures delivery by POSTing each message as JSON to the
+// contact's webhook URL.
+type WebhookConfig struct {
+	// Secret, if set, signs each request; see SignatureHeader.
+	Secret string
+	// Client defaults to one that gives up after 30 seconds.
+	Client *http.Client
+}
+
+// WebhookPayload is the JSON body of a webhook request.
+type WebhookPayload struct {
+	MessageID int64  `json:"message_id"`
+	PatientID string `json:"patient_id"`
+	Recipient string `json:"recipient"`
+	AlertID   int64  `json:"alert_id,omitempty"`
+	Content   string `json:"content"`
+	QueuedAt  int64  `json:"queued_at"`
+}
+
+type Webhook struct {
+	cfg WebhookConfig
+}
+
+func NewWebhook(cfg WebhookConfig) *Webhook {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: sendTimeout}
+	}
+	return &Webhook{cfg: cfg}
+}
+
+func (h *Webhook) Name() string { return ChannelWebhook }
+
+func (h *Webhook) Reaches(contact Contact) bool { return contact.WebhookURL != "" }
+
+// Send returns the "id" field of the receiver's JSON response, if it sent
+// one.
+func (h *Webhook) Send(ctx context.Context, contact Contact, msg app.Message) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	body, err := json.Marshal(WebhookPayload{
+		MessageID: msg.ID,
+		PatientID: msg.PatientID,
+		Recipient: msg.Recipient.String(),
+		AlertID:   msg.AlertID,
+		Content:   msg.Content,
+		QueuedAt:  msg.QueuedAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, contact.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.cfg.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(h.cfg.Secret, body))
+	}
+
+	resp, err := h.cfg.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	var ack struct {
+		ID string `json:"id"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	json.Unmarshal(data, &ack)
+	return ack.ID, nil
+}
+
+// Sign returns the SignatureHeader value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
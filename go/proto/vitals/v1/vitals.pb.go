@@ -21,12 +21,19 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// AlertStatus is the coarse status clients saw before AlertState: it reports
+// CONFIRMED_ABNORMAL alerts as ACTIVE and RESOLVED_BY_RETAKE ones as
+// RESOLVED. It is still set everywhere alongside the AlertState for older
+// clients; new clients should read and filter by AlertState.
+//
+// Deprecated: Marked as deprecated in proto/vitals/v1/vitals.proto.
 type AlertStatus int32
 
 const (
 	AlertStatus_ALERT_STATUS_ACTIVE        AlertStatus = 0
 	AlertStatus_ALERT_STATUS_RESOLVED      AlertStatus = 1
 	AlertStatus_ALERT_STATUS_AUTO_RESOLVED AlertStatus = 2
+	AlertStatus_ALERT_STATUS_ACKNOWLEDGED  AlertStatus = 3
 )
 
 // Enum value maps for AlertStatus.
@@ -35,11 +42,13 @@ var (
 		0: "ALERT_STATUS_ACTIVE",
 		1: "ALERT_STATUS_RESOLVED",
 		2: "ALERT_STATUS_AUTO_RESOLVED",
+		3: "ALERT_STATUS_ACKNOWLEDGED",
 	}
 	AlertStatus_value = map[string]int32{
 		"ALERT_STATUS_ACTIVE":        0,
 		"ALERT_STATUS_RESOLVED":      1,
 		"ALERT_STATUS_AUTO_RESOLVED": 2,
+		"ALERT_STATUS_ACKNOWLEDGED":  3,
 	}
 )
 
@@ -70,31 +79,2163 @@ func (AlertStatus) EnumDescriptor() ([]byte, []int) {
 	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{0}
 }
 
+// AlertState is where an alert is in its lifecycle.
+type AlertState int32
+
+const (
+	AlertState_ALERT_STATE_UNSPECIFIED AlertState = 0
+	// Raised and waiting for the patient's retake.
+	AlertState_ALERT_STATE_ACTIVE AlertState = 1
+	// The retake was normal before the retake request went out.
+	AlertState_ALERT_STATE_AUTO_RESOLVED AlertState = 2
+	// The retake was normal after the retake request went out.
+	AlertState_ALERT_STATE_RESOLVED_BY_RETAKE AlertState = 3
+	// The retake was abnormal too.
+	AlertState_ALERT_STATE_CONFIRMED_ABNORMAL AlertState = 4
+	// A clinician has seen the alert; the next reading still settles it.
+	AlertState_ALERT_STATE_ACKNOWLEDGED AlertState = 5
+	// A clinician closed the alert.
+	AlertState_ALERT_STATE_RESOLVED AlertState = 6
+)
+
+// Enum value maps for AlertState.
+var (
+	AlertState_name = map[int32]string{
+		0: "ALERT_STATE_UNSPECIFIED",
+		1: "ALERT_STATE_ACTIVE",
+		2: "ALERT_STATE_AUTO_RESOLVED",
+		3: "ALERT_STATE_RESOLVED_BY_RETAKE",
+		4: "ALERT_STATE_CONFIRMED_ABNORMAL",
+		5: "ALERT_STATE_ACKNOWLEDGED",
+		6: "ALERT_STATE_RESOLVED",
+	}
+	AlertState_value = map[string]int32{
+		"ALERT_STATE_UNSPECIFIED":        0,
+		"ALERT_STATE_ACTIVE":             1,
+		"ALERT_STATE_AUTO_RESOLVED":      2,
+		"ALERT_STATE_RESOLVED_BY_RETAKE": 3,
+		"ALERT_STATE_CONFIRMED_ABNORMAL": 4,
+		"ALERT_STATE_ACKNOWLEDGED":       5,
+		"ALERT_STATE_RESOLVED":           6,
+	}
+)
+
+func (x AlertState) Enum() *AlertState {
+	p := new(AlertState)
+	*p = x
+	return p
+}
+
+func (x AlertState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertState) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[1].Descriptor()
+}
+
+func (AlertState) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[1]
+}
+
+func (x AlertState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertState.Descriptor instead.
+func (AlertState) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{1}
+}
+
+// Why a clinician resolved an alert.
+type ResolutionReason int32
+
+const (
+	ResolutionReason_RESOLUTION_REASON_UNSPECIFIED       ResolutionReason = 0
+	ResolutionReason_RESOLUTION_REASON_TREATED           ResolutionReason = 1
+	ResolutionReason_RESOLUTION_REASON_FALSE_ALARM       ResolutionReason = 2
+	ResolutionReason_RESOLUTION_REASON_MEASUREMENT_ERROR ResolutionReason = 3
+	ResolutionReason_RESOLUTION_REASON_DUPLICATE         ResolutionReason = 4
+	ResolutionReason_RESOLUTION_REASON_OTHER             ResolutionReason = 5
+)
+
+// Enum value maps for ResolutionReason.
+var (
+	ResolutionReason_name = map[int32]string{
+		0: "RESOLUTION_REASON_UNSPECIFIED",
+		1: "RESOLUTION_REASON_TREATED",
+		2: "RESOLUTION_REASON_FALSE_ALARM",
+		3: "RESOLUTION_REASON_MEASUREMENT_ERROR",
+		4: "RESOLUTION_REASON_DUPLICATE",
+		5: "RESOLUTION_REASON_OTHER",
+	}
+	ResolutionReason_value = map[string]int32{
+		"RESOLUTION_REASON_UNSPECIFIED":       0,
+		"RESOLUTION_REASON_TREATED":           1,
+		"RESOLUTION_REASON_FALSE_ALARM":       2,
+		"RESOLUTION_REASON_MEASUREMENT_ERROR": 3,
+		"RESOLUTION_REASON_DUPLICATE":         4,
+		"RESOLUTION_REASON_OTHER":             5,
+	}
+)
+
+func (x ResolutionReason) Enum() *ResolutionReason {
+	p := new(ResolutionReason)
+	*p = x
+	return p
+}
+
+func (x ResolutionReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ResolutionReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[2].Descriptor()
+}
+
+func (ResolutionReason) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[2]
+}
+
+func (x ResolutionReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResolutionReason.Descriptor instead.
+func (ResolutionReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{2}
+}
+
+type SortOrder int32
+
+const (
+	// Unspecified sorts ascending.
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0
+	SortOrder_SORT_ORDER_ASC         SortOrder = 1
+	SortOrder_SORT_ORDER_DESC        SortOrder = 2
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_ASC",
+		2: "SORT_ORDER_DESC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_ASC":         1,
+		"SORT_ORDER_DESC":        2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[3].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[3]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{3}
+}
+
+// Blood pressure readings use systolic and diastolic; every other kind uses
+// value. Unspecified means blood pressure so older clients keep working.
+type VitalKind int32
+
+const (
+	VitalKind_VITAL_KIND_UNSPECIFIED    VitalKind = 0
+	VitalKind_VITAL_KIND_BLOOD_PRESSURE VitalKind = 1
+	// Heart rate in bpm.
+	VitalKind_VITAL_KIND_PULSE VitalKind = 2
+	// Oxygen saturation in %.
+	VitalKind_VITAL_KIND_SPO2 VitalKind = 3
+	// Body weight in kg; lb is accepted on ingest.
+	VitalKind_VITAL_KIND_WEIGHT VitalKind = 4
+	// Blood glucose in mg/dL; mmol/L is accepted on ingest.
+	VitalKind_VITAL_KIND_GLUCOSE VitalKind = 5
+)
+
+// Enum value maps for VitalKind.
+var (
+	VitalKind_name = map[int32]string{
+		0: "VITAL_KIND_UNSPECIFIED",
+		1: "VITAL_KIND_BLOOD_PRESSURE",
+		2: "VITAL_KIND_PULSE",
+		3: "VITAL_KIND_SPO2",
+		4: "VITAL_KIND_WEIGHT",
+		5: "VITAL_KIND_GLUCOSE",
+	}
+	VitalKind_value = map[string]int32{
+		"VITAL_KIND_UNSPECIFIED":    0,
+		"VITAL_KIND_BLOOD_PRESSURE": 1,
+		"VITAL_KIND_PULSE":          2,
+		"VITAL_KIND_SPO2":           3,
+		"VITAL_KIND_WEIGHT":         4,
+		"VITAL_KIND_GLUCOSE":        5,
+	}
+)
+
+func (x VitalKind) Enum() *VitalKind {
+	p := new(VitalKind)
+	*p = x
+	return p
+}
+
+func (x VitalKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VitalKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[4].Descriptor()
+}
+
+func (VitalKind) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[4]
+}
+
+func (x VitalKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VitalKind.Descriptor instead.
+func (VitalKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{4}
+}
+
+// Quality flags mark a stored reading as possible but implausible. Flagged
+// readings do not alert unless the server is configured to allow it.
+type QualityFlag int32
+
+const (
+	QualityFlag_QUALITY_FLAG_UNSPECIFIED QualityFlag = 0
+	// Systolic and diastolic within 10 mmHg of each other.
+	QualityFlag_QUALITY_FLAG_NARROW_PULSE_PRESSURE QualityFlag = 1
+	// Outside the range usually seen from a working home device.
+	QualityFlag_QUALITY_FLAG_ATYPICAL_VALUE QualityFlag = 2
+)
+
+// Enum value maps for QualityFlag.
+var (
+	QualityFlag_name = map[int32]string{
+		0: "QUALITY_FLAG_UNSPECIFIED",
+		1: "QUALITY_FLAG_NARROW_PULSE_PRESSURE",
+		2: "QUALITY_FLAG_ATYPICAL_VALUE",
+	}
+	QualityFlag_value = map[string]int32{
+		"QUALITY_FLAG_UNSPECIFIED":           0,
+		"QUALITY_FLAG_NARROW_PULSE_PRESSURE": 1,
+		"QUALITY_FLAG_ATYPICAL_VALUE":        2,
+	}
+)
+
+func (x QualityFlag) Enum() *QualityFlag {
+	p := new(QualityFlag)
+	*p = x
+	return p
+}
+
+func (x QualityFlag) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (QualityFlag) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[5].Descriptor()
+}
+
+func (QualityFlag) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[5]
+}
+
+func (x QualityFlag) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use QualityFlag.Descriptor instead.
+func (QualityFlag) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{5}
+}
+
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0
+	Severity_SEVERITY_LOW         Severity = 1
+	Severity_SEVERITY_MODERATE    Severity = 2
+	Severity_SEVERITY_HIGH        Severity = 3
+	Severity_SEVERITY_CRITICAL    Severity = 4
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_LOW",
+		2: "SEVERITY_MODERATE",
+		3: "SEVERITY_HIGH",
+		4: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_LOW":         1,
+		"SEVERITY_MODERATE":    2,
+		"SEVERITY_HIGH":        3,
+		"SEVERITY_CRITICAL":    4,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[6].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[6]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{6}
+}
+
+type Recipient int32
+
+const (
+	Recipient_RECIPIENT_UNSPECIFIED   Recipient = 0
+	Recipient_RECIPIENT_PATIENT       Recipient = 1
+	Recipient_RECIPIENT_ON_CALL_NURSE Recipient = 2
+	Recipient_RECIPIENT_PHYSICIAN     Recipient = 3
+)
+
+// Enum value maps for Recipient.
+var (
+	Recipient_name = map[int32]string{
+		0: "RECIPIENT_UNSPECIFIED",
+		1: "RECIPIENT_PATIENT",
+		2: "RECIPIENT_ON_CALL_NURSE",
+		3: "RECIPIENT_PHYSICIAN",
+	}
+	Recipient_value = map[string]int32{
+		"RECIPIENT_UNSPECIFIED":   0,
+		"RECIPIENT_PATIENT":       1,
+		"RECIPIENT_ON_CALL_NURSE": 2,
+		"RECIPIENT_PHYSICIAN":     3,
+	}
+)
+
+func (x Recipient) Enum() *Recipient {
+	p := new(Recipient)
+	*p = x
+	return p
+}
+
+func (x Recipient) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Recipient) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[7].Descriptor()
+}
+
+func (Recipient) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[7]
+}
+
+func (x Recipient) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Recipient.Descriptor instead.
+func (Recipient) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{7}
+}
+
+type AlertHistoryType int32
+
+const (
+	AlertHistoryType_ALERT_HISTORY_TYPE_UNSPECIFIED    AlertHistoryType = 0
+	AlertHistoryType_ALERT_HISTORY_TYPE_CREATED        AlertHistoryType = 1
+	AlertHistoryType_ALERT_HISTORY_TYPE_VITAL_ATTACHED AlertHistoryType = 2
+	// A reading changed the alert's status.
+	AlertHistoryType_ALERT_HISTORY_TYPE_STATUS_CHANGED         AlertHistoryType = 3
+	AlertHistoryType_ALERT_HISTORY_TYPE_ACKNOWLEDGED           AlertHistoryType = 4
+	AlertHistoryType_ALERT_HISTORY_TYPE_RESOLVED               AlertHistoryType = 5
+	AlertHistoryType_ALERT_HISTORY_TYPE_ESCALATED              AlertHistoryType = 6
+	AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_QUEUED    AlertHistoryType = 7
+	AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_SENT      AlertHistoryType = 8
+	AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_CANCELLED AlertHistoryType = 9
+	// A notification was dead-lettered; note holds the last error.
+	AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_FAILED AlertHistoryType = 10
+	// The patient's notification cooldown held the retake request back; note
+	// says until when.
+	AlertHistoryType_ALERT_HISTORY_TYPE_NOTIFICATION_THROTTLED AlertHistoryType = 11
+)
+
+// Enum value maps for AlertHistoryType.
+var (
+	AlertHistoryType_name = map[int32]string{
+		0:  "ALERT_HISTORY_TYPE_UNSPECIFIED",
+		1:  "ALERT_HISTORY_TYPE_CREATED",
+		2:  "ALERT_HISTORY_TYPE_VITAL_ATTACHED",
+		3:  "ALERT_HISTORY_TYPE_STATUS_CHANGED",
+		4:  "ALERT_HISTORY_TYPE_ACKNOWLEDGED",
+		5:  "ALERT_HISTORY_TYPE_RESOLVED",
+		6:  "ALERT_HISTORY_TYPE_ESCALATED",
+		7:  "ALERT_HISTORY_TYPE_NOTIFICATION_QUEUED",
+		8:  "ALERT_HISTORY_TYPE_NOTIFICATION_SENT",
+		9:  "ALERT_HISTORY_TYPE_NOTIFICATION_CANCELLED",
+		10: "ALERT_HISTORY_TYPE_NOTIFICATION_FAILED",
+		11: "ALERT_HISTORY_TYPE_NOTIFICATION_THROTTLED",
+	}
+	AlertHistoryType_value = map[string]int32{
+		"ALERT_HISTORY_TYPE_UNSPECIFIED":            0,
+		"ALERT_HISTORY_TYPE_CREATED":                1,
+		"ALERT_HISTORY_TYPE_VITAL_ATTACHED":         2,
+		"ALERT_HISTORY_TYPE_STATUS_CHANGED":         3,
+		"ALERT_HISTORY_TYPE_ACKNOWLEDGED":           4,
+		"ALERT_HISTORY_TYPE_RESOLVED":               5,
+		"ALERT_HISTORY_TYPE_ESCALATED":              6,
+		"ALERT_HISTORY_TYPE_NOTIFICATION_QUEUED":    7,
+		"ALERT_HISTORY_TYPE_NOTIFICATION_SENT":      8,
+		"ALERT_HISTORY_TYPE_NOTIFICATION_CANCELLED": 9,
+		"ALERT_HISTORY_TYPE_NOTIFICATION_FAILED":    10,
+		"ALERT_HISTORY_TYPE_NOTIFICATION_THROTTLED": 11,
+	}
+)
+
+func (x AlertHistoryType) Enum() *AlertHistoryType {
+	p := new(AlertHistoryType)
+	*p = x
+	return p
+}
+
+func (x AlertHistoryType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertHistoryType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[8].Descriptor()
+}
+
+func (AlertHistoryType) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[8]
+}
+
+func (x AlertHistoryType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertHistoryType.Descriptor instead.
+func (AlertHistoryType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{8}
+}
+
+type WatchEventType int32
+
+const (
+	WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED          WatchEventType = 0
+	WatchEventType_WATCH_EVENT_TYPE_VITAL_RECEIVED       WatchEventType = 1
+	WatchEventType_WATCH_EVENT_TYPE_ALERT_CREATED        WatchEventType = 2
+	WatchEventType_WATCH_EVENT_TYPE_ALERT_STATUS_CHANGED WatchEventType = 3
+)
+
+// Enum value maps for WatchEventType.
+var (
+	WatchEventType_name = map[int32]string{
+		0: "WATCH_EVENT_TYPE_UNSPECIFIED",
+		1: "WATCH_EVENT_TYPE_VITAL_RECEIVED",
+		2: "WATCH_EVENT_TYPE_ALERT_CREATED",
+		3: "WATCH_EVENT_TYPE_ALERT_STATUS_CHANGED",
+	}
+	WatchEventType_value = map[string]int32{
+		"WATCH_EVENT_TYPE_UNSPECIFIED":          0,
+		"WATCH_EVENT_TYPE_VITAL_RECEIVED":       1,
+		"WATCH_EVENT_TYPE_ALERT_CREATED":        2,
+		"WATCH_EVENT_TYPE_ALERT_STATUS_CHANGED": 3,
+	}
+)
+
+func (x WatchEventType) Enum() *WatchEventType {
+	p := new(WatchEventType)
+	*p = x
+	return p
+}
+
+func (x WatchEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_vitals_v1_vitals_proto_enumTypes[9].Descriptor()
+}
+
+func (WatchEventType) Type() protoreflect.EnumType {
+	return &file_proto_vitals_v1_vitals_proto_enumTypes[9]
+}
+
+func (x WatchEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEventType.Descriptor instead.
+func (WatchEventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{9}
+}
+
+// IngestVital rejects impossible readings, and taken_at more than 5 minutes
+// in the future or over a year old, with INVALID_ARGUMENT. The status
+// carries a google.rpc.BadRequest detail listing every violated field.
 type IngestVitalRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PatientId string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	Systolic  int32                  `protobuf:"varint,2,opt,name=systolic,proto3" json:"systolic,omitempty"`
+	Diastolic int32                  `protobuf:"varint,3,opt,name=diastolic,proto3" json:"diastolic,omitempty"`
+	TakenAt   int64                  `protobuf:"varint,4,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
+	// Optional. Retrying with the same key and payload returns the vital the
+	// first request stored; reusing it with a different payload fails with
+	// ALREADY_EXISTS. Keys are scoped to the patient and to the client named
+	// by x-client-id metadata.
+	IdempotencyKey string    `protobuf:"bytes,5,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Kind           VitalKind `protobuf:"varint,6,opt,name=kind,proto3,enum=vitals.v1.VitalKind" json:"kind,omitempty"`
+	Value          float64   `protobuf:"fixed64,7,opt,name=value,proto3" json:"value,omitempty"`
+	// Unit of value; empty means the kind's default unit. Values are stored
+	// converted to the default unit.
+	Unit          string `protobuf:"bytes,8,opt,name=unit,proto3" json:"unit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestVitalRequest) Reset() {
+	*x = IngestVitalRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestVitalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestVitalRequest) ProtoMessage() {}
+
+func (x *IngestVitalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestVitalRequest.ProtoReflect.Descriptor instead.
+func (*IngestVitalRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IngestVitalRequest) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *IngestVitalRequest) GetSystolic() int32 {
+	if x != nil {
+		return x.Systolic
+	}
+	return 0
+}
+
+func (x *IngestVitalRequest) GetDiastolic() int32 {
+	if x != nil {
+		return x.Diastolic
+	}
+	return 0
+}
+
+func (x *IngestVitalRequest) GetTakenAt() int64 {
+	if x != nil {
+		return x.TakenAt
+	}
+	return 0
+}
+
+func (x *IngestVitalRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *IngestVitalRequest) GetKind() VitalKind {
+	if x != nil {
+		return x.Kind
+	}
+	return VitalKind_VITAL_KIND_UNSPECIFIED
+}
+
+func (x *IngestVitalRequest) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *IngestVitalRequest) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+type IngestVitalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vital         *Vital                 `protobuf:"bytes,1,opt,name=vital,proto3" json:"vital,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestVitalResponse) Reset() {
+	*x = IngestVitalResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestVitalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestVitalResponse) ProtoMessage() {}
+
+func (x *IngestVitalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestVitalResponse.ProtoReflect.Descriptor instead.
+func (*IngestVitalResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IngestVitalResponse) GetVital() *Vital {
+	if x != nil {
+		return x.Vital
+	}
+	return nil
+}
+
+// Batches hold at most 500 readings. Each reading is validated and stored
+// independently, oldest taken_at first; set idempotency keys to make a
+// retried batch safe.
+type BatchIngestVitalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vitals        []*IngestVitalRequest  `protobuf:"bytes,1,rep,name=vitals,proto3" json:"vitals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchIngestVitalsRequest) Reset() {
+	*x = BatchIngestVitalsRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchIngestVitalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchIngestVitalsRequest) ProtoMessage() {}
+
+func (x *BatchIngestVitalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchIngestVitalsRequest.ProtoReflect.Descriptor instead.
+func (*BatchIngestVitalsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchIngestVitalsRequest) GetVitals() []*IngestVitalRequest {
+	if x != nil {
+		return x.Vitals
+	}
+	return nil
+}
+
+type IngestVitalResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the reading in the request (or stream).
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Set when the reading was stored.
+	Vital *Vital `protobuf:"bytes,2,opt,name=vital,proto3" json:"vital,omitempty"`
+	// A google.rpc.Code value; 0 (OK) when the reading was stored.
+	ErrorCode     int32  `protobuf:"varint,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorMessage  string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IngestVitalResult) Reset() {
+	*x = IngestVitalResult{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IngestVitalResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IngestVitalResult) ProtoMessage() {}
+
+func (x *IngestVitalResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IngestVitalResult.ProtoReflect.Descriptor instead.
+func (*IngestVitalResult) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *IngestVitalResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *IngestVitalResult) GetVital() *Vital {
+	if x != nil {
+		return x.Vital
+	}
+	return nil
+}
+
+func (x *IngestVitalResult) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *IngestVitalResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type BatchIngestVitalsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per reading, in request order.
+	Results       []*IngestVitalResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	AcceptedCount int32                `protobuf:"varint,2,opt,name=accepted_count,json=acceptedCount,proto3" json:"accepted_count,omitempty"`
+	RejectedCount int32                `protobuf:"varint,3,opt,name=rejected_count,json=rejectedCount,proto3" json:"rejected_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchIngestVitalsResponse) Reset() {
+	*x = BatchIngestVitalsResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchIngestVitalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchIngestVitalsResponse) ProtoMessage() {}
+
+func (x *BatchIngestVitalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchIngestVitalsResponse.ProtoReflect.Descriptor instead.
+func (*BatchIngestVitalsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchIngestVitalsResponse) GetResults() []*IngestVitalResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchIngestVitalsResponse) GetAcceptedCount() int32 {
+	if x != nil {
+		return x.AcceptedCount
+	}
+	return 0
+}
+
+func (x *BatchIngestVitalsResponse) GetRejectedCount() int32 {
+	if x != nil {
+		return x.RejectedCount
+	}
+	return 0
+}
+
+// List requests page through results ordered by (taken_at, id). page_size
+// defaults to 100 and is capped at 1000; pass next_page_token back as
+// page_token, unchanged, to get the following page, with the same filters
+// and order; a token sent with different ones fails with INVALID_ARGUMENT.
+// taken_after is inclusive and taken_before exclusive, both unix seconds.
+type ListAlertsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PatientId   string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	PageSize    int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken   string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	TakenAfter  int64                  `protobuf:"varint,4,opt,name=taken_after,json=takenAfter,proto3" json:"taken_after,omitempty"`
+	TakenBefore int64                  `protobuf:"varint,5,opt,name=taken_before,json=takenBefore,proto3" json:"taken_before,omitempty"`
+	Order       SortOrder              `protobuf:"varint,6,opt,name=order,proto3,enum=vitals.v1.SortOrder" json:"order,omitempty"`
+	// Keep only alerts in these coarse statuses; superseded by states. When
+	// both are set an alert matching either is kept.
+	//
+	// Deprecated: Marked as deprecated in proto/vitals/v1/vitals.proto.
+	Statuses []AlertStatus `protobuf:"varint,7,rep,packed,name=statuses,proto3,enum=vitals.v1.AlertStatus" json:"statuses,omitempty"`
+	// Keep only alerts in these states; empty (with no statuses) means all.
+	States        []AlertState `protobuf:"varint,8,rep,packed,name=states,proto3,enum=vitals.v1.AlertState" json:"states,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsRequest) Reset() {
+	*x = ListAlertsRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsRequest) ProtoMessage() {}
+
+func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListAlertsRequest) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *ListAlertsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAlertsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAlertsRequest) GetTakenAfter() int64 {
+	if x != nil {
+		return x.TakenAfter
+	}
+	return 0
+}
+
+func (x *ListAlertsRequest) GetTakenBefore() int64 {
+	if x != nil {
+		return x.TakenBefore
+	}
+	return 0
+}
+
+func (x *ListAlertsRequest) GetOrder() SortOrder {
+	if x != nil {
+		return x.Order
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+// Deprecated: Marked as deprecated in proto/vitals/v1/vitals.proto.
+func (x *ListAlertsRequest) GetStatuses() []AlertStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListAlertsRequest) GetStates() []AlertState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+type ListAlertsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Alerts []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAlertsResponse) Reset() {
+	*x = ListAlertsResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertsResponse) ProtoMessage() {}
+
+func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListAlertsResponse) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+func (x *ListAlertsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ListVitalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	TakenAfter    int64                  `protobuf:"varint,4,opt,name=taken_after,json=takenAfter,proto3" json:"taken_after,omitempty"`
+	TakenBefore   int64                  `protobuf:"varint,5,opt,name=taken_before,json=takenBefore,proto3" json:"taken_before,omitempty"`
+	Order         SortOrder              `protobuf:"varint,6,opt,name=order,proto3,enum=vitals.v1.SortOrder" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVitalsRequest) Reset() {
+	*x = ListVitalsRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVitalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVitalsRequest) ProtoMessage() {}
+
+func (x *ListVitalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVitalsRequest.ProtoReflect.Descriptor instead.
+func (*ListVitalsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListVitalsRequest) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *ListVitalsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListVitalsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListVitalsRequest) GetTakenAfter() int64 {
+	if x != nil {
+		return x.TakenAfter
+	}
+	return 0
+}
+
+func (x *ListVitalsRequest) GetTakenBefore() int64 {
+	if x != nil {
+		return x.TakenBefore
+	}
+	return 0
+}
+
+func (x *ListVitalsRequest) GetOrder() SortOrder {
+	if x != nil {
+		return x.Order
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+type ListVitalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vitals        []*Vital               `protobuf:"bytes,1,rep,name=vitals,proto3" json:"vitals,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVitalsResponse) Reset() {
+	*x = ListVitalsResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVitalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVitalsResponse) ProtoMessage() {}
+
+func (x *ListVitalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVitalsResponse.ProtoReflect.Descriptor instead.
+func (*ListVitalsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListVitalsResponse) GetVitals() []*Vital {
+	if x != nil {
+		return x.Vitals
+	}
+	return nil
+}
+
+func (x *ListVitalsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Vital struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PatientId     string                 `protobuf:"bytes,2,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	Systolic      int32                  `protobuf:"varint,3,opt,name=systolic,proto3" json:"systolic,omitempty"`
+	Diastolic     int32                  `protobuf:"varint,4,opt,name=diastolic,proto3" json:"diastolic,omitempty"`
+	TakenAt       int64                  `protobuf:"varint,5,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
+	ReceivedAt    int64                  `protobuf:"varint,6,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	Kind          VitalKind              `protobuf:"varint,7,opt,name=kind,proto3,enum=vitals.v1.VitalKind" json:"kind,omitempty"`
+	Value         float64                `protobuf:"fixed64,8,opt,name=value,proto3" json:"value,omitempty"`
+	Unit          string                 `protobuf:"bytes,9,opt,name=unit,proto3" json:"unit,omitempty"`
+	QualityFlags  []QualityFlag          `protobuf:"varint,10,rep,packed,name=quality_flags,json=qualityFlags,proto3,enum=vitals.v1.QualityFlag" json:"quality_flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vital) Reset() {
+	*x = Vital{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vital) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vital) ProtoMessage() {}
+
+func (x *Vital) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vital.ProtoReflect.Descriptor instead.
+func (*Vital) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Vital) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Vital) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *Vital) GetSystolic() int32 {
+	if x != nil {
+		return x.Systolic
+	}
+	return 0
+}
+
+func (x *Vital) GetDiastolic() int32 {
+	if x != nil {
+		return x.Diastolic
+	}
+	return 0
+}
+
+func (x *Vital) GetTakenAt() int64 {
+	if x != nil {
+		return x.TakenAt
+	}
+	return 0
+}
+
+func (x *Vital) GetReceivedAt() int64 {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return 0
+}
+
+func (x *Vital) GetKind() VitalKind {
+	if x != nil {
+		return x.Kind
+	}
+	return VitalKind_VITAL_KIND_UNSPECIFIED
+}
+
+func (x *Vital) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Vital) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Vital) GetQualityFlags() []QualityFlag {
+	if x != nil {
+		return x.QualityFlags
+	}
+	return nil
+}
+
+type Alert struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vital     *Vital                 `protobuf:"bytes,2,opt,name=vital,proto3" json:"vital,omitempty"`
+	Reason    string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Deprecated: Marked as deprecated in proto/vitals/v1/vitals.proto.
+	Status   AlertStatus `protobuf:"varint,5,opt,name=status,proto3,enum=vitals.v1.AlertStatus" json:"status,omitempty"`
+	Severity Severity    `protobuf:"varint,6,opt,name=severity,proto3,enum=vitals.v1.Severity" json:"severity,omitempty"`
+	// Patient threshold override version in effect when the alert fired; 0
+	// means the default limits applied.
+	ThresholdVersion int64 `protobuf:"varint,7,opt,name=threshold_version,json=thresholdVersion,proto3" json:"threshold_version,omitempty"`
+	// Set once a clinician has acknowledged or resolved the alert.
+	Acknowledgement  *AlertAction     `protobuf:"bytes,8,opt,name=acknowledgement,proto3" json:"acknowledgement,omitempty"`
+	Resolution       *AlertAction     `protobuf:"bytes,9,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	ResolutionReason ResolutionReason `protobuf:"varint,10,opt,name=resolution_reason,json=resolutionReason,proto3,enum=vitals.v1.ResolutionReason" json:"resolution_reason,omitempty"`
+	State            AlertState       `protobuf:"varint,11,opt,name=state,proto3,enum=vitals.v1.AlertState" json:"state,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Alert) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Alert) GetVital() *Vital {
+	if x != nil {
+		return x.Vital
+	}
+	return nil
+}
+
+func (x *Alert) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Alert) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/vitals/v1/vitals.proto.
+func (x *Alert) GetStatus() AlertStatus {
+	if x != nil {
+		return x.Status
+	}
+	return AlertStatus_ALERT_STATUS_ACTIVE
+}
+
+func (x *Alert) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Alert) GetThresholdVersion() int64 {
+	if x != nil {
+		return x.ThresholdVersion
+	}
+	return 0
+}
+
+func (x *Alert) GetAcknowledgement() *AlertAction {
+	if x != nil {
+		return x.Acknowledgement
+	}
+	return nil
+}
+
+func (x *Alert) GetResolution() *AlertAction {
+	if x != nil {
+		return x.Resolution
+	}
+	return nil
+}
+
+func (x *Alert) GetResolutionReason() ResolutionReason {
+	if x != nil {
+		return x.ResolutionReason
+	}
+	return ResolutionReason_RESOLUTION_REASON_UNSPECIFIED
+}
+
+func (x *Alert) GetState() AlertState {
+	if x != nil {
+		return x.State
+	}
+	return AlertState_ALERT_STATE_UNSPECIFIED
+}
+
+// AlertAction records a clinician's action on an alert.
+type AlertAction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clinician     string                 `protobuf:"bytes,1,opt,name=clinician,proto3" json:"clinician,omitempty"`
+	At            int64                  `protobuf:"varint,2,opt,name=at,proto3" json:"at,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertAction) Reset() {
+	*x = AlertAction{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertAction) ProtoMessage() {}
+
+func (x *AlertAction) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertAction.ProtoReflect.Descriptor instead.
+func (*AlertAction) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AlertAction) GetClinician() string {
+	if x != nil {
+		return x.Clinician
+	}
+	return ""
+}
+
+func (x *AlertAction) GetAt() int64 {
+	if x != nil {
+		return x.At
+	}
+	return 0
+}
+
+func (x *AlertAction) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+// AlertHistoryEntry is one event in an alert's timeline. Fields that don't
+// apply to the type are unset.
+type AlertHistoryEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  AlertHistoryType       `protobuf:"varint,2,opt,name=type,proto3,enum=vitals.v1.AlertHistoryType" json:"type,omitempty"`
+	At    int64                  `protobuf:"varint,3,opt,name=at,proto3" json:"at,omitempty"`
+	// The alert's state before and after the entry; unset for notification
+	// entries.
+	FromState AlertState `protobuf:"varint,4,opt,name=from_state,json=fromState,proto3,enum=vitals.v1.AlertState" json:"from_state,omitempty"`
+	State     AlertState `protobuf:"varint,5,opt,name=state,proto3,enum=vitals.v1.AlertState" json:"state,omitempty"`
+	VitalId   int64      `protobuf:"varint,6,opt,name=vital_id,json=vitalId,proto3" json:"vital_id,omitempty"`
+	MessageId int64      `protobuf:"varint,7,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	// Who a notification or escalation went to.
+	Recipient Recipient `protobuf:"varint,8,opt,name=recipient,proto3,enum=vitals.v1.Recipient" json:"recipient,omitempty"`
+	// The clinician, for ACKNOWLEDGED and RESOLVED.
+	Actor            string           `protobuf:"bytes,9,opt,name=actor,proto3" json:"actor,omitempty"`
+	Note             string           `protobuf:"bytes,10,opt,name=note,proto3" json:"note,omitempty"`
+	ResolutionReason ResolutionReason `protobuf:"varint,11,opt,name=resolution_reason,json=resolutionReason,proto3,enum=vitals.v1.ResolutionReason" json:"resolution_reason,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AlertHistoryEntry) Reset() {
+	*x = AlertHistoryEntry{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertHistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertHistoryEntry) ProtoMessage() {}
+
+func (x *AlertHistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertHistoryEntry.ProtoReflect.Descriptor instead.
+func (*AlertHistoryEntry) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AlertHistoryEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AlertHistoryEntry) GetType() AlertHistoryType {
+	if x != nil {
+		return x.Type
+	}
+	return AlertHistoryType_ALERT_HISTORY_TYPE_UNSPECIFIED
+}
+
+func (x *AlertHistoryEntry) GetAt() int64 {
+	if x != nil {
+		return x.At
+	}
+	return 0
+}
+
+func (x *AlertHistoryEntry) GetFromState() AlertState {
+	if x != nil {
+		return x.FromState
+	}
+	return AlertState_ALERT_STATE_UNSPECIFIED
+}
+
+func (x *AlertHistoryEntry) GetState() AlertState {
+	if x != nil {
+		return x.State
+	}
+	return AlertState_ALERT_STATE_UNSPECIFIED
+}
+
+func (x *AlertHistoryEntry) GetVitalId() int64 {
+	if x != nil {
+		return x.VitalId
+	}
+	return 0
+}
+
+func (x *AlertHistoryEntry) GetMessageId() int64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *AlertHistoryEntry) GetRecipient() Recipient {
+	if x != nil {
+		return x.Recipient
+	}
+	return Recipient_RECIPIENT_UNSPECIFIED
+}
+
+func (x *AlertHistoryEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AlertHistoryEntry) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *AlertHistoryEntry) GetResolutionReason() ResolutionReason {
+	if x != nil {
+		return x.ResolutionReason
+	}
+	return ResolutionReason_RESOLUTION_REASON_UNSPECIFIED
+}
+
+type GetAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlertId       int64                  `protobuf:"varint,1,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertRequest) Reset() {
+	*x = GetAlertRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertRequest) ProtoMessage() {}
+
+func (x *GetAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertRequest.ProtoReflect.Descriptor instead.
+func (*GetAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetAlertRequest) GetAlertId() int64 {
+	if x != nil {
+		return x.AlertId
+	}
+	return 0
+}
+
+type GetAlertResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Alert *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	// Oldest first.
+	History       []*AlertHistoryEntry `protobuf:"bytes,2,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAlertResponse) Reset() {
+	*x = GetAlertResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAlertResponse) ProtoMessage() {}
+
+func (x *GetAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAlertResponse.ProtoReflect.Descriptor instead.
+func (*GetAlertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetAlertResponse) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+func (x *GetAlertResponse) GetHistory() []*AlertHistoryEntry {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type AcknowledgeAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlertId       int64                  `protobuf:"varint,1,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	Clinician     string                 `protobuf:"bytes,2,opt,name=clinician,proto3" json:"clinician,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeAlertRequest) Reset() {
+	*x = AcknowledgeAlertRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeAlertRequest) ProtoMessage() {}
+
+func (x *AcknowledgeAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeAlertRequest.ProtoReflect.Descriptor instead.
+func (*AcknowledgeAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AcknowledgeAlertRequest) GetAlertId() int64 {
+	if x != nil {
+		return x.AlertId
+	}
+	return 0
+}
+
+func (x *AcknowledgeAlertRequest) GetClinician() string {
+	if x != nil {
+		return x.Clinician
+	}
+	return ""
+}
+
+func (x *AcknowledgeAlertRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type AcknowledgeAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alert         *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeAlertResponse) Reset() {
+	*x = AcknowledgeAlertResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeAlertResponse) ProtoMessage() {}
+
+func (x *AcknowledgeAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeAlertResponse.ProtoReflect.Descriptor instead.
+func (*AcknowledgeAlertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AcknowledgeAlertResponse) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+type ResolveAlertRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlertId       int64                  `protobuf:"varint,1,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	Clinician     string                 `protobuf:"bytes,2,opt,name=clinician,proto3" json:"clinician,omitempty"`
+	Reason        ResolutionReason       `protobuf:"varint,3,opt,name=reason,proto3,enum=vitals.v1.ResolutionReason" json:"reason,omitempty"`
+	Note          string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAlertRequest) Reset() {
+	*x = ResolveAlertRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAlertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAlertRequest) ProtoMessage() {}
+
+func (x *ResolveAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAlertRequest.ProtoReflect.Descriptor instead.
+func (*ResolveAlertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ResolveAlertRequest) GetAlertId() int64 {
+	if x != nil {
+		return x.AlertId
+	}
+	return 0
+}
+
+func (x *ResolveAlertRequest) GetClinician() string {
+	if x != nil {
+		return x.Clinician
+	}
+	return ""
+}
+
+func (x *ResolveAlertRequest) GetReason() ResolutionReason {
+	if x != nil {
+		return x.Reason
+	}
+	return ResolutionReason_RESOLUTION_REASON_UNSPECIFIED
+}
+
+func (x *ResolveAlertRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ResolveAlertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alert         *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAlertResponse) Reset() {
+	*x = ResolveAlertResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAlertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAlertResponse) ProtoMessage() {}
+
+func (x *ResolveAlertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAlertResponse.ProtoReflect.Descriptor instead.
+func (*ResolveAlertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ResolveAlertResponse) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+// Thresholds are physician-ordered limits; a zero field keeps the default.
+type Thresholds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxSystolic   int32                  `protobuf:"varint,1,opt,name=max_systolic,json=maxSystolic,proto3" json:"max_systolic,omitempty"`
+	MaxDiastolic  int32                  `protobuf:"varint,2,opt,name=max_diastolic,json=maxDiastolic,proto3" json:"max_diastolic,omitempty"`
+	MinSystolic   int32                  `protobuf:"varint,3,opt,name=min_systolic,json=minSystolic,proto3" json:"min_systolic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Thresholds) Reset() {
+	*x = Thresholds{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Thresholds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Thresholds) ProtoMessage() {}
+
+func (x *Thresholds) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Thresholds.ProtoReflect.Descriptor instead.
+func (*Thresholds) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Thresholds) GetMaxSystolic() int32 {
+	if x != nil {
+		return x.MaxSystolic
+	}
+	return 0
+}
+
+func (x *Thresholds) GetMaxDiastolic() int32 {
+	if x != nil {
+		return x.MaxDiastolic
+	}
+	return 0
+}
+
+func (x *Thresholds) GetMinSystolic() int32 {
+	if x != nil {
+		return x.MinSystolic
+	}
+	return 0
+}
+
+type PatientThreshold struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	Thresholds    *Thresholds            `protobuf:"bytes,2,opt,name=thresholds,proto3" json:"thresholds,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	SetBy         string                 `protobuf:"bytes,4,opt,name=set_by,json=setBy,proto3" json:"set_by,omitempty"`
+	SetAt         int64                  `protobuf:"varint,5,opt,name=set_at,json=setAt,proto3" json:"set_at,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Deleted       bool                   `protobuf:"varint,7,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatientThreshold) Reset() {
+	*x = PatientThreshold{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatientThreshold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatientThreshold) ProtoMessage() {}
+
+func (x *PatientThreshold) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatientThreshold.ProtoReflect.Descriptor instead.
+func (*PatientThreshold) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PatientThreshold) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *PatientThreshold) GetThresholds() *Thresholds {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+func (x *PatientThreshold) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PatientThreshold) GetSetBy() string {
+	if x != nil {
+		return x.SetBy
+	}
+	return ""
+}
+
+func (x *PatientThreshold) GetSetAt() int64 {
+	if x != nil {
+		return x.SetAt
+	}
+	return 0
+}
+
+func (x *PatientThreshold) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PatientThreshold) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type SetPatientThresholdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	Thresholds    *Thresholds            `protobuf:"bytes,2,opt,name=thresholds,proto3" json:"thresholds,omitempty"`
+	SetBy         string                 `protobuf:"bytes,3,opt,name=set_by,json=setBy,proto3" json:"set_by,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPatientThresholdRequest) Reset() {
+	*x = SetPatientThresholdRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPatientThresholdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPatientThresholdRequest) ProtoMessage() {}
+
+func (x *SetPatientThresholdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPatientThresholdRequest.ProtoReflect.Descriptor instead.
+func (*SetPatientThresholdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetPatientThresholdRequest) GetPatientId() string {
+	if x != nil {
+		return x.PatientId
+	}
+	return ""
+}
+
+func (x *SetPatientThresholdRequest) GetThresholds() *Thresholds {
+	if x != nil {
+		return x.Thresholds
+	}
+	return nil
+}
+
+func (x *SetPatientThresholdRequest) GetSetBy() string {
+	if x != nil {
+		return x.SetBy
+	}
+	return ""
+}
+
+func (x *SetPatientThresholdRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetPatientThresholdResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
-	Systolic      int32                  `protobuf:"varint,2,opt,name=systolic,proto3" json:"systolic,omitempty"`
-	Diastolic     int32                  `protobuf:"varint,3,opt,name=diastolic,proto3" json:"diastolic,omitempty"`
-	TakenAt       int64                  `protobuf:"varint,4,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
+	Threshold     *PatientThreshold      `protobuf:"bytes,1,opt,name=threshold,proto3" json:"threshold,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IngestVitalRequest) Reset() {
-	*x = IngestVitalRequest{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[0]
+func (x *SetPatientThresholdResponse) Reset() {
+	*x = SetPatientThresholdResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IngestVitalRequest) String() string {
+func (x *SetPatientThresholdResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IngestVitalRequest) ProtoMessage() {}
+func (*SetPatientThresholdResponse) ProtoMessage() {}
 
-func (x *IngestVitalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[0]
+func (x *SetPatientThresholdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -105,61 +2246,86 @@ func (x *IngestVitalRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IngestVitalRequest.ProtoReflect.Descriptor instead.
-func (*IngestVitalRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use SetPatientThresholdResponse.ProtoReflect.Descriptor instead.
+func (*SetPatientThresholdResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *IngestVitalRequest) GetPatientId() string {
+func (x *SetPatientThresholdResponse) GetThreshold() *PatientThreshold {
 	if x != nil {
-		return x.PatientId
+		return x.Threshold
 	}
-	return ""
+	return nil
 }
 
-func (x *IngestVitalRequest) GetSystolic() int32 {
-	if x != nil {
-		return x.Systolic
-	}
-	return 0
+type GetPatientThresholdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IngestVitalRequest) GetDiastolic() int32 {
+func (x *GetPatientThresholdRequest) Reset() {
+	*x = GetPatientThresholdRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPatientThresholdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPatientThresholdRequest) ProtoMessage() {}
+
+func (x *GetPatientThresholdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[23]
 	if x != nil {
-		return x.Diastolic
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *IngestVitalRequest) GetTakenAt() int64 {
+// Deprecated: Use GetPatientThresholdRequest.ProtoReflect.Descriptor instead.
+func (*GetPatientThresholdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetPatientThresholdRequest) GetPatientId() string {
 	if x != nil {
-		return x.TakenAt
+		return x.PatientId
 	}
-	return 0
+	return ""
 }
 
-type IngestVitalResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vital         *Vital                 `protobuf:"bytes,1,opt,name=vital,proto3" json:"vital,omitempty"`
+type GetPatientThresholdResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Threshold *PatientThreshold      `protobuf:"bytes,1,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Every version for the patient, oldest first.
+	History       []*PatientThreshold `protobuf:"bytes,2,rep,name=history,proto3" json:"history,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IngestVitalResponse) Reset() {
-	*x = IngestVitalResponse{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[1]
+func (x *GetPatientThresholdResponse) Reset() {
+	*x = GetPatientThresholdResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IngestVitalResponse) String() string {
+func (x *GetPatientThresholdResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IngestVitalResponse) ProtoMessage() {}
+func (*GetPatientThresholdResponse) ProtoMessage() {}
 
-func (x *IngestVitalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[1]
+func (x *GetPatientThresholdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -170,40 +2336,46 @@ func (x *IngestVitalResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IngestVitalResponse.ProtoReflect.Descriptor instead.
-func (*IngestVitalResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use GetPatientThresholdResponse.ProtoReflect.Descriptor instead.
+func (*GetPatientThresholdResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *IngestVitalResponse) GetVital() *Vital {
+func (x *GetPatientThresholdResponse) GetThreshold() *PatientThreshold {
 	if x != nil {
-		return x.Vital
+		return x.Threshold
 	}
 	return nil
 }
 
-type ListAlertsRequest struct {
+func (x *GetPatientThresholdResponse) GetHistory() []*PatientThreshold {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type ListPatientThresholdsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAlertsRequest) Reset() {
-	*x = ListAlertsRequest{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[2]
+func (x *ListPatientThresholdsRequest) Reset() {
+	*x = ListPatientThresholdsRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAlertsRequest) String() string {
+func (x *ListPatientThresholdsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAlertsRequest) ProtoMessage() {}
+func (*ListPatientThresholdsRequest) ProtoMessage() {}
 
-func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[2]
+func (x *ListPatientThresholdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -214,40 +2386,33 @@ func (x *ListAlertsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAlertsRequest.ProtoReflect.Descriptor instead.
-func (*ListAlertsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *ListAlertsRequest) GetPatientId() string {
-	if x != nil {
-		return x.PatientId
-	}
-	return ""
+// Deprecated: Use ListPatientThresholdsRequest.ProtoReflect.Descriptor instead.
+func (*ListPatientThresholdsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{25}
 }
 
-type ListAlertsResponse struct {
+type ListPatientThresholdsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=alerts,proto3" json:"alerts,omitempty"`
+	Thresholds    []*PatientThreshold    `protobuf:"bytes,1,rep,name=thresholds,proto3" json:"thresholds,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAlertsResponse) Reset() {
-	*x = ListAlertsResponse{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[3]
+func (x *ListPatientThresholdsResponse) Reset() {
+	*x = ListPatientThresholdsResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAlertsResponse) String() string {
+func (x *ListPatientThresholdsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAlertsResponse) ProtoMessage() {}
+func (*ListPatientThresholdsResponse) ProtoMessage() {}
 
-func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[3]
+func (x *ListPatientThresholdsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -258,40 +2423,41 @@ func (x *ListAlertsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAlertsResponse.ProtoReflect.Descriptor instead.
-func (*ListAlertsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ListPatientThresholdsResponse.ProtoReflect.Descriptor instead.
+func (*ListPatientThresholdsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *ListAlertsResponse) GetAlerts() []*Alert {
+func (x *ListPatientThresholdsResponse) GetThresholds() []*PatientThreshold {
 	if x != nil {
-		return x.Alerts
+		return x.Thresholds
 	}
 	return nil
 }
 
-type ListVitalsRequest struct {
+type DeletePatientThresholdRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	DeletedBy     string                 `protobuf:"bytes,2,opt,name=deleted_by,json=deletedBy,proto3" json:"deleted_by,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListVitalsRequest) Reset() {
-	*x = ListVitalsRequest{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[4]
+func (x *DeletePatientThresholdRequest) Reset() {
+	*x = DeletePatientThresholdRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListVitalsRequest) String() string {
+func (x *DeletePatientThresholdRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListVitalsRequest) ProtoMessage() {}
+func (*DeletePatientThresholdRequest) ProtoMessage() {}
 
-func (x *ListVitalsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[4]
+func (x *DeletePatientThresholdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -302,40 +2468,47 @@ func (x *ListVitalsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListVitalsRequest.ProtoReflect.Descriptor instead.
-func (*ListVitalsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use DeletePatientThresholdRequest.ProtoReflect.Descriptor instead.
+func (*DeletePatientThresholdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *ListVitalsRequest) GetPatientId() string {
+func (x *DeletePatientThresholdRequest) GetPatientId() string {
 	if x != nil {
 		return x.PatientId
 	}
 	return ""
 }
 
-type ListVitalsResponse struct {
+func (x *DeletePatientThresholdRequest) GetDeletedBy() string {
+	if x != nil {
+		return x.DeletedBy
+	}
+	return ""
+}
+
+type DeletePatientThresholdResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Vitals        []*Vital               `protobuf:"bytes,1,rep,name=vitals,proto3" json:"vitals,omitempty"`
+	Threshold     *PatientThreshold      `protobuf:"bytes,1,opt,name=threshold,proto3" json:"threshold,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListVitalsResponse) Reset() {
-	*x = ListVitalsResponse{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[5]
+func (x *DeletePatientThresholdResponse) Reset() {
+	*x = DeletePatientThresholdResponse{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListVitalsResponse) String() string {
+func (x *DeletePatientThresholdResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListVitalsResponse) ProtoMessage() {}
+func (*DeletePatientThresholdResponse) ProtoMessage() {}
 
-func (x *ListVitalsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[5]
+func (x *DeletePatientThresholdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -346,45 +2519,47 @@ func (x *ListVitalsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListVitalsResponse.ProtoReflect.Descriptor instead.
-func (*ListVitalsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use DeletePatientThresholdResponse.ProtoReflect.Descriptor instead.
+func (*DeletePatientThresholdResponse) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *ListVitalsResponse) GetVitals() []*Vital {
+func (x *DeletePatientThresholdResponse) GetThreshold() *PatientThreshold {
 	if x != nil {
-		return x.Vitals
+		return x.Threshold
 	}
 	return nil
 }
 
-type Vital struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	PatientId     string                 `protobuf:"bytes,2,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
-	Systolic      int32                  `protobuf:"varint,3,opt,name=systolic,proto3" json:"systolic,omitempty"`
-	Diastolic     int32                  `protobuf:"varint,4,opt,name=diastolic,proto3" json:"diastolic,omitempty"`
-	TakenAt       int64                  `protobuf:"varint,5,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"`
-	ReceivedAt    int64                  `protobuf:"varint,6,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+// Watch requests stream events for one patient, or all patients when
+// patient_id is empty. Pass the resume_token of the last event received to
+// pick up where a dropped stream left off; OUT_OF_RANGE means the gap is no
+// longer retained and the client should re-list before watching again.
+type WatchAlertsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	PatientId   string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	ResumeToken string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	// Also stream VITAL_RECEIVED events.
+	IncludeVitals bool `protobuf:"varint,3,opt,name=include_vitals,json=includeVitals,proto3" json:"include_vitals,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Vital) Reset() {
-	*x = Vital{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[6]
+func (x *WatchAlertsRequest) Reset() {
+	*x = WatchAlertsRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Vital) String() string {
+func (x *WatchAlertsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Vital) ProtoMessage() {}
+func (*WatchAlertsRequest) ProtoMessage() {}
 
-func (x *Vital) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[6]
+func (x *WatchAlertsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -395,79 +2570,114 @@ func (x *Vital) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Vital.ProtoReflect.Descriptor instead.
-func (*Vital) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use WatchAlertsRequest.ProtoReflect.Descriptor instead.
+func (*WatchAlertsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *Vital) GetId() int64 {
+func (x *WatchAlertsRequest) GetPatientId() string {
 	if x != nil {
-		return x.Id
+		return x.PatientId
 	}
-	return 0
+	return ""
 }
 
-func (x *Vital) GetPatientId() string {
+func (x *WatchAlertsRequest) GetResumeToken() string {
 	if x != nil {
-		return x.PatientId
+		return x.ResumeToken
 	}
 	return ""
 }
 
-func (x *Vital) GetSystolic() int32 {
+func (x *WatchAlertsRequest) GetIncludeVitals() bool {
 	if x != nil {
-		return x.Systolic
+		return x.IncludeVitals
 	}
-	return 0
+	return false
 }
 
-func (x *Vital) GetDiastolic() int32 {
-	if x != nil {
-		return x.Diastolic
-	}
-	return 0
+type WatchVitalsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PatientId     string                 `protobuf:"bytes,1,opt,name=patient_id,json=patientId,proto3" json:"patient_id,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Vital) GetTakenAt() int64 {
+func (x *WatchVitalsRequest) Reset() {
+	*x = WatchVitalsRequest{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchVitalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchVitalsRequest) ProtoMessage() {}
+
+func (x *WatchVitalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[30]
 	if x != nil {
-		return x.TakenAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *Vital) GetReceivedAt() int64 {
+// Deprecated: Use WatchVitalsRequest.ProtoReflect.Descriptor instead.
+func (*WatchVitalsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WatchVitalsRequest) GetPatientId() string {
 	if x != nil {
-		return x.ReceivedAt
+		return x.PatientId
 	}
-	return 0
+	return ""
 }
 
-type Alert struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Vital         *Vital                 `protobuf:"bytes,2,opt,name=vital,proto3" json:"vital,omitempty"`
-	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	Status        AlertStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=vitals.v1.AlertStatus" json:"status,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *WatchVitalsRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
 }
 
-func (x *Alert) Reset() {
-	*x = Alert{}
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[7]
+type WatchEvent struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Type        WatchEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=vitals.v1.WatchEventType" json:"type,omitempty"`
+	ResumeToken string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	Vital       *Vital                 `protobuf:"bytes,3,opt,name=vital,proto3" json:"vital,omitempty"`
+	Alert       *Alert                 `protobuf:"bytes,4,opt,name=alert,proto3" json:"alert,omitempty"`
+	// Set for ALERT_STATUS_CHANGED.
+	//
+	// Deprecated: Marked as deprecated in proto/vitals/v1/vitals.proto.
+	PreviousStatus AlertStatus `protobuf:"varint,5,opt,name=previous_status,json=previousStatus,proto3,enum=vitals.v1.AlertStatus" json:"previous_status,omitempty"`
+	PreviousState  AlertState  `protobuf:"varint,6,opt,name=previous_state,json=previousState,proto3,enum=vitals.v1.AlertState" json:"previous_state,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Alert) String() string {
+func (x *WatchEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Alert) ProtoMessage() {}
+func (*WatchEvent) ProtoMessage() {}
 
-func (x *Alert) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[7]
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_vitals_v1_vitals_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -478,69 +2688,111 @@ func (x *Alert) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
-func (*Alert) Descriptor() ([]byte, []int) {
-	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_proto_vitals_v1_vitals_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *Alert) GetId() int64 {
+func (x *WatchEvent) GetType() WatchEventType {
 	if x != nil {
-		return x.Id
+		return x.Type
 	}
-	return 0
+	return WatchEventType_WATCH_EVENT_TYPE_UNSPECIFIED
 }
 
-func (x *Alert) GetVital() *Vital {
+func (x *WatchEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetVital() *Vital {
 	if x != nil {
 		return x.Vital
 	}
 	return nil
 }
 
-func (x *Alert) GetReason() string {
+func (x *WatchEvent) GetAlert() *Alert {
 	if x != nil {
-		return x.Reason
+		return x.Alert
 	}
-	return ""
+	return nil
 }
 
-func (x *Alert) GetCreatedAt() int64 {
+// Deprecated: Marked as deprecated in proto/vitals/v1/vitals.proto.
+func (x *WatchEvent) GetPreviousStatus() AlertStatus {
 	if x != nil {
-		return x.CreatedAt
+		return x.PreviousStatus
 	}
-	return 0
+	return AlertStatus_ALERT_STATUS_ACTIVE
 }
 
-func (x *Alert) GetStatus() AlertStatus {
+func (x *WatchEvent) GetPreviousState() AlertState {
 	if x != nil {
-		return x.Status
+		return x.PreviousState
 	}
-	return AlertStatus_ALERT_STATUS_ACTIVE
+	return AlertState_ALERT_STATE_UNSPECIFIED
 }
 
 var File_proto_vitals_v1_vitals_proto protoreflect.FileDescriptor
 
 const file_proto_vitals_v1_vitals_proto_rawDesc = "" +
 	"\n" +
-	"\x1cproto/vitals/v1/vitals.proto\x12\tvitals.v1\"\x88\x01\n" +
+	"\x1cproto/vitals/v1/vitals.proto\x12\tvitals.v1\"\x85\x02\n" +
 	"\x12IngestVitalRequest\x12\x1d\n" +
 	"\n" +
 	"patient_id\x18\x01 \x01(\tR\tpatientId\x12\x1a\n" +
 	"\bsystolic\x18\x02 \x01(\x05R\bsystolic\x12\x1c\n" +
 	"\tdiastolic\x18\x03 \x01(\x05R\tdiastolic\x12\x19\n" +
-	"\btaken_at\x18\x04 \x01(\x03R\atakenAt\"=\n" +
+	"\btaken_at\x18\x04 \x01(\x03R\atakenAt\x12'\n" +
+	"\x0fidempotency_key\x18\x05 \x01(\tR\x0eidempotencyKey\x12(\n" +
+	"\x04kind\x18\x06 \x01(\x0e2\x14.vitals.v1.VitalKindR\x04kind\x12\x14\n" +
+	"\x05value\x18\a \x01(\x01R\x05value\x12\x12\n" +
+	"\x04unit\x18\b \x01(\tR\x04unit\"=\n" +
 	"\x13IngestVitalResponse\x12&\n" +
-	"\x05vital\x18\x01 \x01(\v2\x10.vitals.v1.VitalR\x05vital\"2\n" +
+	"\x05vital\x18\x01 \x01(\v2\x10.vitals.v1.VitalR\x05vital\"Q\n" +
+	"\x18BatchIngestVitalsRequest\x125\n" +
+	"\x06vitals\x18\x01 \x03(\v2\x1d.vitals.v1.IngestVitalRequestR\x06vitals\"\x95\x01\n" +
+	"\x11IngestVitalResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12&\n" +
+	"\x05vital\x18\x02 \x01(\v2\x10.vitals.v1.VitalR\x05vital\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x03 \x01(\x05R\terrorCode\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"\xa1\x01\n" +
+	"\x19BatchIngestVitalsResponse\x126\n" +
+	"\aresults\x18\x01 \x03(\v2\x1c.vitals.v1.IngestVitalResultR\aresults\x12%\n" +
+	"\x0eaccepted_count\x18\x02 \x01(\x05R\racceptedCount\x12%\n" +
+	"\x0erejected_count\x18\x03 \x01(\x05R\rrejectedCount\"\xc5\x02\n" +
 	"\x11ListAlertsRequest\x12\x1d\n" +
 	"\n" +
-	"patient_id\x18\x01 \x01(\tR\tpatientId\">\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x1f\n" +
+	"\vtaken_after\x18\x04 \x01(\x03R\n" +
+	"takenAfter\x12!\n" +
+	"\ftaken_before\x18\x05 \x01(\x03R\vtakenBefore\x12*\n" +
+	"\x05order\x18\x06 \x01(\x0e2\x14.vitals.v1.SortOrderR\x05order\x126\n" +
+	"\bstatuses\x18\a \x03(\x0e2\x16.vitals.v1.AlertStatusB\x02\x18\x01R\bstatuses\x12-\n" +
+	"\x06states\x18\b \x03(\x0e2\x15.vitals.v1.AlertStateR\x06states\"f\n" +
 	"\x12ListAlertsResponse\x12(\n" +
-	"\x06alerts\x18\x01 \x03(\v2\x10.vitals.v1.AlertR\x06alerts\"2\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x10.vitals.v1.AlertR\x06alerts\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xde\x01\n" +
 	"\x11ListVitalsRequest\x12\x1d\n" +
 	"\n" +
-	"patient_id\x18\x01 \x01(\tR\tpatientId\">\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x1f\n" +
+	"\vtaken_after\x18\x04 \x01(\x03R\n" +
+	"takenAfter\x12!\n" +
+	"\ftaken_before\x18\x05 \x01(\x03R\vtakenBefore\x12*\n" +
+	"\x05order\x18\x06 \x01(\x0e2\x14.vitals.v1.SortOrderR\x05order\"f\n" +
 	"\x12ListVitalsResponse\x12(\n" +
-	"\x06vitals\x18\x01 \x03(\v2\x10.vitals.v1.VitalR\x06vitals\"\xac\x01\n" +
+	"\x06vitals\x18\x01 \x03(\v2\x10.vitals.v1.VitalR\x06vitals\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xbd\x02\n" +
 	"\x05Vital\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
 	"\n" +
@@ -549,24 +2801,209 @@ const file_proto_vitals_v1_vitals_proto_rawDesc = "" +
 	"\tdiastolic\x18\x04 \x01(\x05R\tdiastolic\x12\x19\n" +
 	"\btaken_at\x18\x05 \x01(\x03R\atakenAt\x12\x1f\n" +
 	"\vreceived_at\x18\x06 \x01(\x03R\n" +
-	"receivedAt\"\xa6\x01\n" +
+	"receivedAt\x12(\n" +
+	"\x04kind\x18\a \x01(\x0e2\x14.vitals.v1.VitalKindR\x04kind\x12\x14\n" +
+	"\x05value\x18\b \x01(\x01R\x05value\x12\x12\n" +
+	"\x04unit\x18\t \x01(\tR\x04unit\x12;\n" +
+	"\rquality_flags\x18\n" +
+	" \x03(\x0e2\x16.vitals.v1.QualityFlagR\fqualityFlags\"\xf9\x03\n" +
 	"\x05Alert\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12&\n" +
 	"\x05vital\x18\x02 \x01(\v2\x10.vitals.v1.VitalR\x05vital\x12\x16\n" +
 	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x12.\n" +
-	"\x06status\x18\x05 \x01(\x0e2\x16.vitals.v1.AlertStatusR\x06status*a\n" +
+	"created_at\x18\x04 \x01(\x03R\tcreatedAt\x122\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x16.vitals.v1.AlertStatusB\x02\x18\x01R\x06status\x12/\n" +
+	"\bseverity\x18\x06 \x01(\x0e2\x13.vitals.v1.SeverityR\bseverity\x12+\n" +
+	"\x11threshold_version\x18\a \x01(\x03R\x10thresholdVersion\x12@\n" +
+	"\x0facknowledgement\x18\b \x01(\v2\x16.vitals.v1.AlertActionR\x0facknowledgement\x126\n" +
+	"\n" +
+	"resolution\x18\t \x01(\v2\x16.vitals.v1.AlertActionR\n" +
+	"resolution\x12H\n" +
+	"\x11resolution_reason\x18\n" +
+	" \x01(\x0e2\x1b.vitals.v1.ResolutionReasonR\x10resolutionReason\x12+\n" +
+	"\x05state\x18\v \x01(\x0e2\x15.vitals.v1.AlertStateR\x05state\"O\n" +
+	"\vAlertAction\x12\x1c\n" +
+	"\tclinician\x18\x01 \x01(\tR\tclinician\x12\x0e\n" +
+	"\x02at\x18\x02 \x01(\x03R\x02at\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"\xa9\x03\n" +
+	"\x11AlertHistoryEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12/\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1b.vitals.v1.AlertHistoryTypeR\x04type\x12\x0e\n" +
+	"\x02at\x18\x03 \x01(\x03R\x02at\x124\n" +
+	"\n" +
+	"from_state\x18\x04 \x01(\x0e2\x15.vitals.v1.AlertStateR\tfromState\x12+\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x15.vitals.v1.AlertStateR\x05state\x12\x19\n" +
+	"\bvital_id\x18\x06 \x01(\x03R\avitalId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\a \x01(\x03R\tmessageId\x122\n" +
+	"\trecipient\x18\b \x01(\x0e2\x14.vitals.v1.RecipientR\trecipient\x12\x14\n" +
+	"\x05actor\x18\t \x01(\tR\x05actor\x12\x12\n" +
+	"\x04note\x18\n" +
+	" \x01(\tR\x04note\x12H\n" +
+	"\x11resolution_reason\x18\v \x01(\x0e2\x1b.vitals.v1.ResolutionReasonR\x10resolutionReason\",\n" +
+	"\x0fGetAlertRequest\x12\x19\n" +
+	"\balert_id\x18\x01 \x01(\x03R\aalertId\"r\n" +
+	"\x10GetAlertResponse\x12&\n" +
+	"\x05alert\x18\x01 \x01(\v2\x10.vitals.v1.AlertR\x05alert\x126\n" +
+	"\ahistory\x18\x02 \x03(\v2\x1c.vitals.v1.AlertHistoryEntryR\ahistory\"f\n" +
+	"\x17AcknowledgeAlertRequest\x12\x19\n" +
+	"\balert_id\x18\x01 \x01(\x03R\aalertId\x12\x1c\n" +
+	"\tclinician\x18\x02 \x01(\tR\tclinician\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"B\n" +
+	"\x18AcknowledgeAlertResponse\x12&\n" +
+	"\x05alert\x18\x01 \x01(\v2\x10.vitals.v1.AlertR\x05alert\"\x97\x01\n" +
+	"\x13ResolveAlertRequest\x12\x19\n" +
+	"\balert_id\x18\x01 \x01(\x03R\aalertId\x12\x1c\n" +
+	"\tclinician\x18\x02 \x01(\tR\tclinician\x123\n" +
+	"\x06reason\x18\x03 \x01(\x0e2\x1b.vitals.v1.ResolutionReasonR\x06reason\x12\x12\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\">\n" +
+	"\x14ResolveAlertResponse\x12&\n" +
+	"\x05alert\x18\x01 \x01(\v2\x10.vitals.v1.AlertR\x05alert\"w\n" +
+	"\n" +
+	"Thresholds\x12!\n" +
+	"\fmax_systolic\x18\x01 \x01(\x05R\vmaxSystolic\x12#\n" +
+	"\rmax_diastolic\x18\x02 \x01(\x05R\fmaxDiastolic\x12!\n" +
+	"\fmin_systolic\x18\x03 \x01(\x05R\vminSystolic\"\xe2\x01\n" +
+	"\x10PatientThreshold\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x125\n" +
+	"\n" +
+	"thresholds\x18\x02 \x01(\v2\x15.vitals.v1.ThresholdsR\n" +
+	"thresholds\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12\x15\n" +
+	"\x06set_by\x18\x04 \x01(\tR\x05setBy\x12\x15\n" +
+	"\x06set_at\x18\x05 \x01(\x03R\x05setAt\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x18\n" +
+	"\adeleted\x18\a \x01(\bR\adeleted\"\xa1\x01\n" +
+	"\x1aSetPatientThresholdRequest\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x125\n" +
+	"\n" +
+	"thresholds\x18\x02 \x01(\v2\x15.vitals.v1.ThresholdsR\n" +
+	"thresholds\x12\x15\n" +
+	"\x06set_by\x18\x03 \x01(\tR\x05setBy\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"X\n" +
+	"\x1bSetPatientThresholdResponse\x129\n" +
+	"\tthreshold\x18\x01 \x01(\v2\x1b.vitals.v1.PatientThresholdR\tthreshold\";\n" +
+	"\x1aGetPatientThresholdRequest\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\"\x8f\x01\n" +
+	"\x1bGetPatientThresholdResponse\x129\n" +
+	"\tthreshold\x18\x01 \x01(\v2\x1b.vitals.v1.PatientThresholdR\tthreshold\x125\n" +
+	"\ahistory\x18\x02 \x03(\v2\x1b.vitals.v1.PatientThresholdR\ahistory\"\x1e\n" +
+	"\x1cListPatientThresholdsRequest\"\\\n" +
+	"\x1dListPatientThresholdsResponse\x12;\n" +
+	"\n" +
+	"thresholds\x18\x01 \x03(\v2\x1b.vitals.v1.PatientThresholdR\n" +
+	"thresholds\"]\n" +
+	"\x1dDeletePatientThresholdRequest\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x12\x1d\n" +
+	"\n" +
+	"deleted_by\x18\x02 \x01(\tR\tdeletedBy\"[\n" +
+	"\x1eDeletePatientThresholdResponse\x129\n" +
+	"\tthreshold\x18\x01 \x01(\v2\x1b.vitals.v1.PatientThresholdR\tthreshold\"}\n" +
+	"\x12WatchAlertsRequest\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\x12%\n" +
+	"\x0einclude_vitals\x18\x03 \x01(\bR\rincludeVitals\"V\n" +
+	"\x12WatchVitalsRequest\x12\x1d\n" +
+	"\n" +
+	"patient_id\x18\x01 \x01(\tR\tpatientId\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"\xb1\x02\n" +
+	"\n" +
+	"WatchEvent\x12-\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x19.vitals.v1.WatchEventTypeR\x04type\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\x12&\n" +
+	"\x05vital\x18\x03 \x01(\v2\x10.vitals.v1.VitalR\x05vital\x12&\n" +
+	"\x05alert\x18\x04 \x01(\v2\x10.vitals.v1.AlertR\x05alert\x12C\n" +
+	"\x0fprevious_status\x18\x05 \x01(\x0e2\x16.vitals.v1.AlertStatusB\x02\x18\x01R\x0epreviousStatus\x12<\n" +
+	"\x0eprevious_state\x18\x06 \x01(\x0e2\x15.vitals.v1.AlertStateR\rpreviousState*\x84\x01\n" +
 	"\vAlertStatus\x12\x17\n" +
 	"\x13ALERT_STATUS_ACTIVE\x10\x00\x12\x19\n" +
 	"\x15ALERT_STATUS_RESOLVED\x10\x01\x12\x1e\n" +
-	"\x1aALERT_STATUS_AUTO_RESOLVED\x10\x022\xf3\x01\n" +
+	"\x1aALERT_STATUS_AUTO_RESOLVED\x10\x02\x12\x1d\n" +
+	"\x19ALERT_STATUS_ACKNOWLEDGED\x10\x03\x1a\x02\x18\x01*\xe0\x01\n" +
+	"\n" +
+	"AlertState\x12\x1b\n" +
+	"\x17ALERT_STATE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12ALERT_STATE_ACTIVE\x10\x01\x12\x1d\n" +
+	"\x19ALERT_STATE_AUTO_RESOLVED\x10\x02\x12\"\n" +
+	"\x1eALERT_STATE_RESOLVED_BY_RETAKE\x10\x03\x12\"\n" +
+	"\x1eALERT_STATE_CONFIRMED_ABNORMAL\x10\x04\x12\x1c\n" +
+	"\x18ALERT_STATE_ACKNOWLEDGED\x10\x05\x12\x18\n" +
+	"\x14ALERT_STATE_RESOLVED\x10\x06*\xde\x01\n" +
+	"\x10ResolutionReason\x12!\n" +
+	"\x1dRESOLUTION_REASON_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19RESOLUTION_REASON_TREATED\x10\x01\x12!\n" +
+	"\x1dRESOLUTION_REASON_FALSE_ALARM\x10\x02\x12'\n" +
+	"#RESOLUTION_REASON_MEASUREMENT_ERROR\x10\x03\x12\x1f\n" +
+	"\x1bRESOLUTION_REASON_DUPLICATE\x10\x04\x12\x1b\n" +
+	"\x17RESOLUTION_REASON_OTHER\x10\x05*P\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSORT_ORDER_ASC\x10\x01\x12\x13\n" +
+	"\x0fSORT_ORDER_DESC\x10\x02*\xa0\x01\n" +
+	"\tVitalKind\x12\x1a\n" +
+	"\x16VITAL_KIND_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19VITAL_KIND_BLOOD_PRESSURE\x10\x01\x12\x14\n" +
+	"\x10VITAL_KIND_PULSE\x10\x02\x12\x13\n" +
+	"\x0fVITAL_KIND_SPO2\x10\x03\x12\x15\n" +
+	"\x11VITAL_KIND_WEIGHT\x10\x04\x12\x16\n" +
+	"\x12VITAL_KIND_GLUCOSE\x10\x05*t\n" +
+	"\vQualityFlag\x12\x1c\n" +
+	"\x18QUALITY_FLAG_UNSPECIFIED\x10\x00\x12&\n" +
+	"\"QUALITY_FLAG_NARROW_PULSE_PRESSURE\x10\x01\x12\x1f\n" +
+	"\x1bQUALITY_FLAG_ATYPICAL_VALUE\x10\x02*w\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fSEVERITY_LOW\x10\x01\x12\x15\n" +
+	"\x11SEVERITY_MODERATE\x10\x02\x12\x11\n" +
+	"\rSEVERITY_HIGH\x10\x03\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x04*s\n" +
+	"\tRecipient\x12\x19\n" +
+	"\x15RECIPIENT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11RECIPIENT_PATIENT\x10\x01\x12\x1b\n" +
+	"\x17RECIPIENT_ON_CALL_NURSE\x10\x02\x12\x17\n" +
+	"\x13RECIPIENT_PHYSICIAN\x10\x03*\xec\x03\n" +
+	"\x10AlertHistoryType\x12\"\n" +
+	"\x1eALERT_HISTORY_TYPE_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aALERT_HISTORY_TYPE_CREATED\x10\x01\x12%\n" +
+	"!ALERT_HISTORY_TYPE_VITAL_ATTACHED\x10\x02\x12%\n" +
+	"!ALERT_HISTORY_TYPE_STATUS_CHANGED\x10\x03\x12#\n" +
+	"\x1fALERT_HISTORY_TYPE_ACKNOWLEDGED\x10\x04\x12\x1f\n" +
+	"\x1bALERT_HISTORY_TYPE_RESOLVED\x10\x05\x12 \n" +
+	"\x1cALERT_HISTORY_TYPE_ESCALATED\x10\x06\x12*\n" +
+	"&ALERT_HISTORY_TYPE_NOTIFICATION_QUEUED\x10\a\x12(\n" +
+	"$ALERT_HISTORY_TYPE_NOTIFICATION_SENT\x10\b\x12-\n" +
+	")ALERT_HISTORY_TYPE_NOTIFICATION_CANCELLED\x10\t\x12*\n" +
+	"&ALERT_HISTORY_TYPE_NOTIFICATION_FAILED\x10\n" +
+	"\x12-\n" +
+	")ALERT_HISTORY_TYPE_NOTIFICATION_THROTTLED\x10\v*\xa6\x01\n" +
+	"\x0eWatchEventType\x12 \n" +
+	"\x1cWATCH_EVENT_TYPE_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fWATCH_EVENT_TYPE_VITAL_RECEIVED\x10\x01\x12\"\n" +
+	"\x1eWATCH_EVENT_TYPE_ALERT_CREATED\x10\x02\x12)\n" +
+	"%WATCH_EVENT_TYPE_ALERT_STATUS_CHANGED\x10\x032\xd8\t\n" +
 	"\rVitalsService\x12L\n" +
-	"\vIngestVital\x12\x1d.vitals.v1.IngestVitalRequest\x1a\x1e.vitals.v1.IngestVitalResponse\x12I\n" +
+	"\vIngestVital\x12\x1d.vitals.v1.IngestVitalRequest\x1a\x1e.vitals.v1.IngestVitalResponse\x12^\n" +
+	"\x11BatchIngestVitals\x12#.vitals.v1.BatchIngestVitalsRequest\x1a$.vitals.v1.BatchIngestVitalsResponse\x12[\n" +
+	"\x12StreamIngestVitals\x12\x1d.vitals.v1.IngestVitalRequest\x1a$.vitals.v1.BatchIngestVitalsResponse(\x01\x12I\n" +
 	"\n" +
 	"ListAlerts\x12\x1c.vitals.v1.ListAlertsRequest\x1a\x1d.vitals.v1.ListAlertsResponse\x12I\n" +
 	"\n" +
-	"ListVitals\x12\x1c.vitals.v1.ListVitalsRequest\x1a\x1d.vitals.v1.ListVitalsResponseB3Z1cadence-vitals-interview/proto/vitals/v1;vitalsv1b\x06proto3"
+	"ListVitals\x12\x1c.vitals.v1.ListVitalsRequest\x1a\x1d.vitals.v1.ListVitalsResponse\x12C\n" +
+	"\bGetAlert\x12\x1a.vitals.v1.GetAlertRequest\x1a\x1b.vitals.v1.GetAlertResponse\x12[\n" +
+	"\x10AcknowledgeAlert\x12\".vitals.v1.AcknowledgeAlertRequest\x1a#.vitals.v1.AcknowledgeAlertResponse\x12O\n" +
+	"\fResolveAlert\x12\x1e.vitals.v1.ResolveAlertRequest\x1a\x1f.vitals.v1.ResolveAlertResponse\x12d\n" +
+	"\x13SetPatientThreshold\x12%.vitals.v1.SetPatientThresholdRequest\x1a&.vitals.v1.SetPatientThresholdResponse\x12d\n" +
+	"\x13GetPatientThreshold\x12%.vitals.v1.GetPatientThresholdRequest\x1a&.vitals.v1.GetPatientThresholdResponse\x12j\n" +
+	"\x15ListPatientThresholds\x12'.vitals.v1.ListPatientThresholdsRequest\x1a(.vitals.v1.ListPatientThresholdsResponse\x12m\n" +
+	"\x16DeletePatientThreshold\x12(.vitals.v1.DeletePatientThresholdRequest\x1a).vitals.v1.DeletePatientThresholdResponse\x12E\n" +
+	"\vWatchAlerts\x12\x1d.vitals.v1.WatchAlertsRequest\x1a\x15.vitals.v1.WatchEvent0\x01\x12E\n" +
+	"\vWatchVitals\x12\x1d.vitals.v1.WatchVitalsRequest\x1a\x15.vitals.v1.WatchEvent0\x01B3Z1cadence-vitals-interview/proto/vitals/v1;vitalsv1b\x06proto3"
 
 var (
 	file_proto_vitals_v1_vitals_proto_rawDescOnce sync.Once
@@ -580,36 +3017,128 @@ func file_proto_vitals_v1_vitals_proto_rawDescGZIP() []byte {
 	return file_proto_vitals_v1_vitals_proto_rawDescData
 }
 
-var file_proto_vitals_v1_vitals_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_vitals_v1_vitals_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_vitals_v1_vitals_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_proto_vitals_v1_vitals_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_proto_vitals_v1_vitals_proto_goTypes = []any{
-	(AlertStatus)(0),            // 0: vitals.v1.AlertStatus
-	(*IngestVitalRequest)(nil),  // 1: vitals.v1.IngestVitalRequest
-	(*IngestVitalResponse)(nil), // 2: vitals.v1.IngestVitalResponse
-	(*ListAlertsRequest)(nil),   // 3: vitals.v1.ListAlertsRequest
-	(*ListAlertsResponse)(nil),  // 4: vitals.v1.ListAlertsResponse
-	(*ListVitalsRequest)(nil),   // 5: vitals.v1.ListVitalsRequest
-	(*ListVitalsResponse)(nil),  // 6: vitals.v1.ListVitalsResponse
-	(*Vital)(nil),               // 7: vitals.v1.Vital
-	(*Alert)(nil),               // 8: vitals.v1.Alert
+	(AlertStatus)(0),                       // 0: vitals.v1.AlertStatus
+	(AlertState)(0),                        // 1: vitals.v1.AlertState
+	(ResolutionReason)(0),                  // 2: vitals.v1.ResolutionReason
+	(SortOrder)(0),                         // 3: vitals.v1.SortOrder
+	(VitalKind)(0),                         // 4: vitals.v1.VitalKind
+	(QualityFlag)(0),                       // 5: vitals.v1.QualityFlag
+	(Severity)(0),                          // 6: vitals.v1.Severity
+	(Recipient)(0),                         // 7: vitals.v1.Recipient
+	(AlertHistoryType)(0),                  // 8: vitals.v1.AlertHistoryType
+	(WatchEventType)(0),                    // 9: vitals.v1.WatchEventType
+	(*IngestVitalRequest)(nil),             // 10: vitals.v1.IngestVitalRequest
+	(*IngestVitalResponse)(nil),            // 11: vitals.v1.IngestVitalResponse
+	(*BatchIngestVitalsRequest)(nil),       // 12: vitals.v1.BatchIngestVitalsRequest
+	(*IngestVitalResult)(nil),              // 13: vitals.v1.IngestVitalResult
+	(*BatchIngestVitalsResponse)(nil),      // 14: vitals.v1.BatchIngestVitalsResponse
+	(*ListAlertsRequest)(nil),              // 15: vitals.v1.ListAlertsRequest
+	(*ListAlertsResponse)(nil),             // 16: vitals.v1.ListAlertsResponse
+	(*ListVitalsRequest)(nil),              // 17: vitals.v1.ListVitalsRequest
+	(*ListVitalsResponse)(nil),             // 18: vitals.v1.ListVitalsResponse
+	(*Vital)(nil),                          // 19: vitals.v1.Vital
+	(*Alert)(nil),                          // 20: vitals.v1.Alert
+	(*AlertAction)(nil),                    // 21: vitals.v1.AlertAction
+	(*AlertHistoryEntry)(nil),              // 22: vitals.v1.AlertHistoryEntry
+	(*GetAlertRequest)(nil),                // 23: vitals.v1.GetAlertRequest
+	(*GetAlertResponse)(nil),               // 24: vitals.v1.GetAlertResponse
+	(*AcknowledgeAlertRequest)(nil),        // 25: vitals.v1.AcknowledgeAlertRequest
+	(*AcknowledgeAlertResponse)(nil),       // 26: vitals.v1.AcknowledgeAlertResponse
+	(*ResolveAlertRequest)(nil),            // 27: vitals.v1.ResolveAlertRequest
+	(*ResolveAlertResponse)(nil),           // 28: vitals.v1.ResolveAlertResponse
+	(*Thresholds)(nil),                     // 29: vitals.v1.Thresholds
+	(*PatientThreshold)(nil),               // 30: vitals.v1.PatientThreshold
+	(*SetPatientThresholdRequest)(nil),     // 31: vitals.v1.SetPatientThresholdRequest
+	(*SetPatientThresholdResponse)(nil),    // 32: vitals.v1.SetPatientThresholdResponse
+	(*GetPatientThresholdRequest)(nil),     // 33: vitals.v1.GetPatientThresholdRequest
+	(*GetPatientThresholdResponse)(nil),    // 34: vitals.v1.GetPatientThresholdResponse
+	(*ListPatientThresholdsRequest)(nil),   // 35: vitals.v1.ListPatientThresholdsRequest
+	(*ListPatientThresholdsResponse)(nil),  // 36: vitals.v1.ListPatientThresholdsResponse
+	(*DeletePatientThresholdRequest)(nil),  // 37: vitals.v1.DeletePatientThresholdRequest
+	(*DeletePatientThresholdResponse)(nil), // 38: vitals.v1.DeletePatientThresholdResponse
+	(*WatchAlertsRequest)(nil),             // 39: vitals.v1.WatchAlertsRequest
+	(*WatchVitalsRequest)(nil),             // 40: vitals.v1.WatchVitalsRequest
+	(*WatchEvent)(nil),                     // 41: vitals.v1.WatchEvent
 }
 var file_proto_vitals_v1_vitals_proto_depIdxs = []int32{
-	7, // 0: vitals.v1.IngestVitalResponse.vital:type_name -> vitals.v1.Vital
-	8, // 1: vitals.v1.ListAlertsResponse.alerts:type_name -> vitals.v1.Alert
-	7, // 2: vitals.v1.ListVitalsResponse.vitals:type_name -> vitals.v1.Vital
-	7, // 3: vitals.v1.Alert.vital:type_name -> vitals.v1.Vital
-	0, // 4: vitals.v1.Alert.status:type_name -> vitals.v1.AlertStatus
-	1, // 5: vitals.v1.VitalsService.IngestVital:input_type -> vitals.v1.IngestVitalRequest
-	3, // 6: vitals.v1.VitalsService.ListAlerts:input_type -> vitals.v1.ListAlertsRequest
-	5, // 7: vitals.v1.VitalsService.ListVitals:input_type -> vitals.v1.ListVitalsRequest
-	2, // 8: vitals.v1.VitalsService.IngestVital:output_type -> vitals.v1.IngestVitalResponse
-	4, // 9: vitals.v1.VitalsService.ListAlerts:output_type -> vitals.v1.ListAlertsResponse
-	6, // 10: vitals.v1.VitalsService.ListVitals:output_type -> vitals.v1.ListVitalsResponse
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	4,  // 0: vitals.v1.IngestVitalRequest.kind:type_name -> vitals.v1.VitalKind
+	19, // 1: vitals.v1.IngestVitalResponse.vital:type_name -> vitals.v1.Vital
+	10, // 2: vitals.v1.BatchIngestVitalsRequest.vitals:type_name -> vitals.v1.IngestVitalRequest
+	19, // 3: vitals.v1.IngestVitalResult.vital:type_name -> vitals.v1.Vital
+	13, // 4: vitals.v1.BatchIngestVitalsResponse.results:type_name -> vitals.v1.IngestVitalResult
+	3,  // 5: vitals.v1.ListAlertsRequest.order:type_name -> vitals.v1.SortOrder
+	0,  // 6: vitals.v1.ListAlertsRequest.statuses:type_name -> vitals.v1.AlertStatus
+	1,  // 7: vitals.v1.ListAlertsRequest.states:type_name -> vitals.v1.AlertState
+	20, // 8: vitals.v1.ListAlertsResponse.alerts:type_name -> vitals.v1.Alert
+	3,  // 9: vitals.v1.ListVitalsRequest.order:type_name -> vitals.v1.SortOrder
+	19, // 10: vitals.v1.ListVitalsResponse.vitals:type_name -> vitals.v1.Vital
+	4,  // 11: vitals.v1.Vital.kind:type_name -> vitals.v1.VitalKind
+	5,  // 12: vitals.v1.Vital.quality_flags:type_name -> vitals.v1.QualityFlag
+	19, // 13: vitals.v1.Alert.vital:type_name -> vitals.v1.Vital
+	0,  // 14: vitals.v1.Alert.status:type_name -> vitals.v1.AlertStatus
+	6,  // 15: vitals.v1.Alert.severity:type_name -> vitals.v1.Severity
+	21, // 16: vitals.v1.Alert.acknowledgement:type_name -> vitals.v1.AlertAction
+	21, // 17: vitals.v1.Alert.resolution:type_name -> vitals.v1.AlertAction
+	2,  // 18: vitals.v1.Alert.resolution_reason:type_name -> vitals.v1.ResolutionReason
+	1,  // 19: vitals.v1.Alert.state:type_name -> vitals.v1.AlertState
+	8,  // 20: vitals.v1.AlertHistoryEntry.type:type_name -> vitals.v1.AlertHistoryType
+	1,  // 21: vitals.v1.AlertHistoryEntry.from_state:type_name -> vitals.v1.AlertState
+	1,  // 22: vitals.v1.AlertHistoryEntry.state:type_name -> vitals.v1.AlertState
+	7,  // 23: vitals.v1.AlertHistoryEntry.recipient:type_name -> vitals.v1.Recipient
+	2,  // 24: vitals.v1.AlertHistoryEntry.resolution_reason:type_name -> vitals.v1.ResolutionReason
+	20, // 25: vitals.v1.GetAlertResponse.alert:type_name -> vitals.v1.Alert
+	22, // 26: vitals.v1.GetAlertResponse.history:type_name -> vitals.v1.AlertHistoryEntry
+	20, // 27: vitals.v1.AcknowledgeAlertResponse.alert:type_name -> vitals.v1.Alert
+	2,  // 28: vitals.v1.ResolveAlertRequest.reason:type_name -> vitals.v1.ResolutionReason
+	20, // 29: vitals.v1.ResolveAlertResponse.alert:type_name -> vitals.v1.Alert
+	29, // 30: vitals.v1.PatientThreshold.thresholds:type_name -> vitals.v1.Thresholds
+	29, // 31: vitals.v1.SetPatientThresholdRequest.thresholds:type_name -> vitals.v1.Thresholds
+	30, // 32: vitals.v1.SetPatientThresholdResponse.threshold:type_name -> vitals.v1.PatientThreshold
+	30, // 33: vitals.v1.GetPatientThresholdResponse.threshold:type_name -> vitals.v1.PatientThreshold
+	30, // 34: vitals.v1.GetPatientThresholdResponse.history:type_name -> vitals.v1.PatientThreshold
+	30, // 35: vitals.v1.ListPatientThresholdsResponse.thresholds:type_name -> vitals.v1.PatientThreshold
+	30, // 36: vitals.v1.DeletePatientThresholdResponse.threshold:type_name -> vitals.v1.PatientThreshold
+	9,  // 37: vitals.v1.WatchEvent.type:type_name -> vitals.v1.WatchEventType
+	19, // 38: vitals.v1.WatchEvent.vital:type_name -> vitals.v1.Vital
+	20, // 39: vitals.v1.WatchEvent.alert:type_name -> vitals.v1.Alert
+	0,  // 40: vitals.v1.WatchEvent.previous_status:type_name -> vitals.v1.AlertStatus
+	1,  // 41: vitals.v1.WatchEvent.previous_state:type_name -> vitals.v1.AlertState
+	10, // 42: vitals.v1.VitalsService.IngestVital:input_type -> vitals.v1.IngestVitalRequest
+	12, // 43: vitals.v1.VitalsService.BatchIngestVitals:input_type -> vitals.v1.BatchIngestVitalsRequest
+	10, // 44: vitals.v1.VitalsService.StreamIngestVitals:input_type -> vitals.v1.IngestVitalRequest
+	15, // 45: vitals.v1.VitalsService.ListAlerts:input_type -> vitals.v1.ListAlertsRequest
+	17, // 46: vitals.v1.VitalsService.ListVitals:input_type -> vitals.v1.ListVitalsRequest
+	23, // 47: vitals.v1.VitalsService.GetAlert:input_type -> vitals.v1.GetAlertRequest
+	25, // 48: vitals.v1.VitalsService.AcknowledgeAlert:input_type -> vitals.v1.AcknowledgeAlertRequest
+	27, // 49: vitals.v1.VitalsService.ResolveAlert:input_type -> vitals.v1.ResolveAlertRequest
+	31, // 50: vitals.v1.VitalsService.SetPatientThreshold:input_type -> vitals.v1.SetPatientThresholdRequest
+	33, // 51: vitals.v1.VitalsService.GetPatientThreshold:input_type -> vitals.v1.GetPatientThresholdRequest
+	35, // 52: vitals.v1.VitalsService.ListPatientThresholds:input_type -> vitals.v1.ListPatientThresholdsRequest
+	37, // 53: vitals.v1.VitalsService.DeletePatientThreshold:input_type -> vitals.v1.DeletePatientThresholdRequest
+	39, // 54: vitals.v1.VitalsService.WatchAlerts:input_type -> vitals.v1.WatchAlertsRequest
+	40, // 55: vitals.v1.VitalsService.WatchVitals:input_type -> vitals.v1.WatchVitalsRequest
+	11, // 56: vitals.v1.VitalsService.IngestVital:output_type -> vitals.v1.IngestVitalResponse
+	14, // 57: vitals.v1.VitalsService.BatchIngestVitals:output_type -> vitals.v1.BatchIngestVitalsResponse
+	14, // 58: vitals.v1.VitalsService.StreamIngestVitals:output_type -> vitals.v1.BatchIngestVitalsResponse
+	16, // 59: vitals.v1.VitalsService.ListAlerts:output_type -> vitals.v1.ListAlertsResponse
+	18, // 60: vitals.v1.VitalsService.ListVitals:output_type -> vitals.v1.ListVitalsResponse
+	24, // 61: vitals.v1.VitalsService.GetAlert:output_type -> vitals.v1.GetAlertResponse
+	26, // 62: vitals.v1.VitalsService.AcknowledgeAlert:output_type -> vitals.v1.AcknowledgeAlertResponse
+	28, // 63: vitals.v1.VitalsService.ResolveAlert:output_type -> vitals.v1.ResolveAlertResponse
+	32, // 64: vitals.v1.VitalsService.SetPatientThreshold:output_type -> vitals.v1.SetPatientThresholdResponse
+	34, // 65: vitals.v1.VitalsService.GetPatientThreshold:output_type -> vitals.v1.GetPatientThresholdResponse
+	36, // 66: vitals.v1.VitalsService.ListPatientThresholds:output_type -> vitals.v1.ListPatientThresholdsResponse
+	38, // 67: vitals.v1.VitalsService.DeletePatientThreshold:output_type -> vitals.v1.DeletePatientThresholdResponse
+	41, // 68: vitals.v1.VitalsService.WatchAlerts:output_type -> vitals.v1.WatchEvent
+	41, // 69: vitals.v1.VitalsService.WatchVitals:output_type -> vitals.v1.WatchEvent
+	56, // [56:70] is the sub-list for method output_type
+	42, // [42:56] is the sub-list for method input_type
+	42, // [42:42] is the sub-list for extension type_name
+	42, // [42:42] is the sub-list for extension extendee
+	0,  // [0:42] is the sub-list for field type_name
 }
 
 func init() { file_proto_vitals_v1_vitals_proto_init() }
@@ -622,8 +3151,8 @@ func file_proto_vitals_v1_vitals_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_vitals_v1_vitals_proto_rawDesc), len(file_proto_vitals_v1_vitals_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   8,
+			NumEnums:      10,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,9 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	VitalsService_IngestVital_FullMethodName = "/vitals.v1.VitalsService/IngestVital"
-	VitalsService_ListAlerts_FullMethodName  = "/vitals.v1.VitalsService/ListAlerts"
-	VitalsService_ListVitals_FullMethodName  = "/vitals.v1.VitalsService/ListVitals"
+	VitalsService_IngestVital_FullMethodName            = "/vitals.v1.VitalsService/IngestVital"
+	VitalsService_BatchIngestVitals_FullMethodName      = "/vitals.v1.VitalsService/BatchIngestVitals"
+	VitalsService_StreamIngestVitals_FullMethodName     = "/vitals.v1.VitalsService/StreamIngestVitals"
+	VitalsService_ListAlerts_FullMethodName             = "/vitals.v1.VitalsService/ListAlerts"
+	VitalsService_ListVitals_FullMethodName             = "/vitals.v1.VitalsService/ListVitals"
+	VitalsService_GetAlert_FullMethodName               = "/vitals.v1.VitalsService/GetAlert"
+	VitalsService_AcknowledgeAlert_FullMethodName       = "/vitals.v1.VitalsService/AcknowledgeAlert"
+	VitalsService_ResolveAlert_FullMethodName           = "/vitals.v1.VitalsService/ResolveAlert"
+	VitalsService_SetPatientThreshold_FullMethodName    = "/vitals.v1.VitalsService/SetPatientThreshold"
+	VitalsService_GetPatientThreshold_FullMethodName    = "/vitals.v1.VitalsService/GetPatientThreshold"
+	VitalsService_ListPatientThresholds_FullMethodName  = "/vitals.v1.VitalsService/ListPatientThresholds"
+	VitalsService_DeletePatientThreshold_FullMethodName = "/vitals.v1.VitalsService/DeletePatientThreshold"
+	VitalsService_WatchAlerts_FullMethodName            = "/vitals.v1.VitalsService/WatchAlerts"
+	VitalsService_WatchVitals_FullMethodName            = "/vitals.v1.VitalsService/WatchVitals"
 )
 
 // VitalsServiceClient is the client API for VitalsService service.
@@ -29,8 +40,23 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type VitalsServiceClient interface {
 	IngestVital(ctx context.Context, in *IngestVitalRequest, opts ...grpc.CallOption) (*IngestVitalResponse, error)
+	BatchIngestVitals(ctx context.Context, in *BatchIngestVitalsRequest, opts ...grpc.CallOption) (*BatchIngestVitalsResponse, error)
+	// Readings are buffered until the client closes the stream, then ingested
+	// as one batch.
+	StreamIngestVitals(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestVitalRequest, BatchIngestVitalsResponse], error)
 	ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error)
 	ListVitals(ctx context.Context, in *ListVitalsRequest, opts ...grpc.CallOption) (*ListVitalsResponse, error)
+	GetAlert(ctx context.Context, in *GetAlertRequest, opts ...grpc.CallOption) (*GetAlertResponse, error)
+	// Acknowledging leaves the alert open to retakes; resolving closes it.
+	// Both fail with FAILED_PRECONDITION once the alert is closed.
+	AcknowledgeAlert(ctx context.Context, in *AcknowledgeAlertRequest, opts ...grpc.CallOption) (*AcknowledgeAlertResponse, error)
+	ResolveAlert(ctx context.Context, in *ResolveAlertRequest, opts ...grpc.CallOption) (*ResolveAlertResponse, error)
+	SetPatientThreshold(ctx context.Context, in *SetPatientThresholdRequest, opts ...grpc.CallOption) (*SetPatientThresholdResponse, error)
+	GetPatientThreshold(ctx context.Context, in *GetPatientThresholdRequest, opts ...grpc.CallOption) (*GetPatientThresholdResponse, error)
+	ListPatientThresholds(ctx context.Context, in *ListPatientThresholdsRequest, opts ...grpc.CallOption) (*ListPatientThresholdsResponse, error)
+	DeletePatientThreshold(ctx context.Context, in *DeletePatientThresholdRequest, opts ...grpc.CallOption) (*DeletePatientThresholdResponse, error)
+	WatchAlerts(ctx context.Context, in *WatchAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+	WatchVitals(ctx context.Context, in *WatchVitalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
 }
 
 type vitalsServiceClient struct {
@@ -51,6 +77,29 @@ func (c *vitalsServiceClient) IngestVital(ctx context.Context, in *IngestVitalRe
 	return out, nil
 }
 
+func (c *vitalsServiceClient) BatchIngestVitals(ctx context.Context, in *BatchIngestVitalsRequest, opts ...grpc.CallOption) (*BatchIngestVitalsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchIngestVitalsResponse)
+	err := c.cc.Invoke(ctx, VitalsService_BatchIngestVitals_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) StreamIngestVitals(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[IngestVitalRequest, BatchIngestVitalsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VitalsService_ServiceDesc.Streams[0], VitalsService_StreamIngestVitals_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[IngestVitalRequest, BatchIngestVitalsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VitalsService_StreamIngestVitalsClient = grpc.ClientStreamingClient[IngestVitalRequest, BatchIngestVitalsResponse]
+
 func (c *vitalsServiceClient) ListAlerts(ctx context.Context, in *ListAlertsRequest, opts ...grpc.CallOption) (*ListAlertsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListAlertsResponse)
@@ -71,13 +120,136 @@ func (c *vitalsServiceClient) ListVitals(ctx context.Context, in *ListVitalsRequ
 	return out, nil
 }
 
+func (c *vitalsServiceClient) GetAlert(ctx context.Context, in *GetAlertRequest, opts ...grpc.CallOption) (*GetAlertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAlertResponse)
+	err := c.cc.Invoke(ctx, VitalsService_GetAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) AcknowledgeAlert(ctx context.Context, in *AcknowledgeAlertRequest, opts ...grpc.CallOption) (*AcknowledgeAlertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcknowledgeAlertResponse)
+	err := c.cc.Invoke(ctx, VitalsService_AcknowledgeAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) ResolveAlert(ctx context.Context, in *ResolveAlertRequest, opts ...grpc.CallOption) (*ResolveAlertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveAlertResponse)
+	err := c.cc.Invoke(ctx, VitalsService_ResolveAlert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) SetPatientThreshold(ctx context.Context, in *SetPatientThresholdRequest, opts ...grpc.CallOption) (*SetPatientThresholdResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetPatientThresholdResponse)
+	err := c.cc.Invoke(ctx, VitalsService_SetPatientThreshold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) GetPatientThreshold(ctx context.Context, in *GetPatientThresholdRequest, opts ...grpc.CallOption) (*GetPatientThresholdResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPatientThresholdResponse)
+	err := c.cc.Invoke(ctx, VitalsService_GetPatientThreshold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) ListPatientThresholds(ctx context.Context, in *ListPatientThresholdsRequest, opts ...grpc.CallOption) (*ListPatientThresholdsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPatientThresholdsResponse)
+	err := c.cc.Invoke(ctx, VitalsService_ListPatientThresholds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) DeletePatientThreshold(ctx context.Context, in *DeletePatientThresholdRequest, opts ...grpc.CallOption) (*DeletePatientThresholdResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeletePatientThresholdResponse)
+	err := c.cc.Invoke(ctx, VitalsService_DeletePatientThreshold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vitalsServiceClient) WatchAlerts(ctx context.Context, in *WatchAlertsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VitalsService_ServiceDesc.Streams[1], VitalsService_WatchAlerts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAlertsRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VitalsService_WatchAlertsClient = grpc.ServerStreamingClient[WatchEvent]
+
+func (c *vitalsServiceClient) WatchVitals(ctx context.Context, in *WatchVitalsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VitalsService_ServiceDesc.Streams[2], VitalsService_WatchVitals_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchVitalsRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VitalsService_WatchVitalsClient = grpc.ServerStreamingClient[WatchEvent]
+
 // VitalsServiceServer is the server API for VitalsService service.
 // All implementations must embed UnimplementedVitalsServiceServer
 // for forward compatibility.
 type VitalsServiceServer interface {
 	IngestVital(context.Context, *IngestVitalRequest) (*IngestVitalResponse, error)
+	BatchIngestVitals(context.Context, *BatchIngestVitalsRequest) (*BatchIngestVitalsResponse, error)
+	// Readings are buffered until the client closes the stream, then ingested
+	// as one batch.
+	StreamIngestVitals(grpc.ClientStreamingServer[IngestVitalRequest, BatchIngestVitalsResponse]) error
 	ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error)
 	ListVitals(context.Context, *ListVitalsRequest) (*ListVitalsResponse, error)
+	GetAlert(context.Context, *GetAlertRequest) (*GetAlertResponse, error)
+	// Acknowledging leaves the alert open to retakes; resolving closes it.
+	// Both fail with FAILED_PRECONDITION once the alert is closed.
+	AcknowledgeAlert(context.Context, *AcknowledgeAlertRequest) (*AcknowledgeAlertResponse, error)
+	ResolveAlert(context.Context, *ResolveAlertRequest) (*ResolveAlertResponse, error)
+	SetPatientThreshold(context.Context, *SetPatientThresholdRequest) (*SetPatientThresholdResponse, error)
+	GetPatientThreshold(context.Context, *GetPatientThresholdRequest) (*GetPatientThresholdResponse, error)
+	ListPatientThresholds(context.Context, *ListPatientThresholdsRequest) (*ListPatientThresholdsResponse, error)
+	DeletePatientThreshold(context.Context, *DeletePatientThresholdRequest) (*DeletePatientThresholdResponse, error)
+	WatchAlerts(*WatchAlertsRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	WatchVitals(*WatchVitalsRequest, grpc.ServerStreamingServer[WatchEvent]) error
 	mustEmbedUnimplementedVitalsServiceServer()
 }
 
@@ -91,12 +263,45 @@ type UnimplementedVitalsServiceServer struct{}
 func (UnimplementedVitalsServiceServer) IngestVital(context.Context, *IngestVitalRequest) (*IngestVitalResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method IngestVital not implemented")
 }
+func (UnimplementedVitalsServiceServer) BatchIngestVitals(context.Context, *BatchIngestVitalsRequest) (*BatchIngestVitalsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchIngestVitals not implemented")
+}
+func (UnimplementedVitalsServiceServer) StreamIngestVitals(grpc.ClientStreamingServer[IngestVitalRequest, BatchIngestVitalsResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamIngestVitals not implemented")
+}
 func (UnimplementedVitalsServiceServer) ListAlerts(context.Context, *ListAlertsRequest) (*ListAlertsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListAlerts not implemented")
 }
 func (UnimplementedVitalsServiceServer) ListVitals(context.Context, *ListVitalsRequest) (*ListVitalsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListVitals not implemented")
 }
+func (UnimplementedVitalsServiceServer) GetAlert(context.Context, *GetAlertRequest) (*GetAlertResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAlert not implemented")
+}
+func (UnimplementedVitalsServiceServer) AcknowledgeAlert(context.Context, *AcknowledgeAlertRequest) (*AcknowledgeAlertResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AcknowledgeAlert not implemented")
+}
+func (UnimplementedVitalsServiceServer) ResolveAlert(context.Context, *ResolveAlertRequest) (*ResolveAlertResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveAlert not implemented")
+}
+func (UnimplementedVitalsServiceServer) SetPatientThreshold(context.Context, *SetPatientThresholdRequest) (*SetPatientThresholdResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetPatientThreshold not implemented")
+}
+func (UnimplementedVitalsServiceServer) GetPatientThreshold(context.Context, *GetPatientThresholdRequest) (*GetPatientThresholdResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPatientThreshold not implemented")
+}
+func (UnimplementedVitalsServiceServer) ListPatientThresholds(context.Context, *ListPatientThresholdsRequest) (*ListPatientThresholdsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListPatientThresholds not implemented")
+}
+func (UnimplementedVitalsServiceServer) DeletePatientThreshold(context.Context, *DeletePatientThresholdRequest) (*DeletePatientThresholdResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeletePatientThreshold not implemented")
+}
+func (UnimplementedVitalsServiceServer) WatchAlerts(*WatchAlertsRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchAlerts not implemented")
+}
+func (UnimplementedVitalsServiceServer) WatchVitals(*WatchVitalsRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchVitals not implemented")
+}
 func (UnimplementedVitalsServiceServer) mustEmbedUnimplementedVitalsServiceServer() {}
 func (UnimplementedVitalsServiceServer) testEmbeddedByValue()                       {}
 
@@ -136,6 +341,31 @@ func _VitalsService_IngestVital_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VitalsService_BatchIngestVitals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchIngestVitalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).BatchIngestVitals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_BatchIngestVitals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).BatchIngestVitals(ctx, req.(*BatchIngestVitalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_StreamIngestVitals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(VitalsServiceServer).StreamIngestVitals(&grpc.GenericServerStream[IngestVitalRequest, BatchIngestVitalsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VitalsService_StreamIngestVitalsServer = grpc.ClientStreamingServer[IngestVitalRequest, BatchIngestVitalsResponse]
+
 func _VitalsService_ListAlerts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListAlertsRequest)
 	if err := dec(in); err != nil {
@@ -172,6 +402,154 @@ func _VitalsService_ListVitals_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VitalsService_GetAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).GetAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_GetAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).GetAlert(ctx, req.(*GetAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_AcknowledgeAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcknowledgeAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).AcknowledgeAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_AcknowledgeAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).AcknowledgeAlert(ctx, req.(*AcknowledgeAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_ResolveAlert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveAlertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).ResolveAlert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_ResolveAlert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).ResolveAlert(ctx, req.(*ResolveAlertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_SetPatientThreshold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPatientThresholdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).SetPatientThreshold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_SetPatientThreshold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).SetPatientThreshold(ctx, req.(*SetPatientThresholdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_GetPatientThreshold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPatientThresholdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).GetPatientThreshold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_GetPatientThreshold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).GetPatientThreshold(ctx, req.(*GetPatientThresholdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_ListPatientThresholds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPatientThresholdsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).ListPatientThresholds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_ListPatientThresholds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).ListPatientThresholds(ctx, req.(*ListPatientThresholdsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_DeletePatientThreshold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePatientThresholdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VitalsServiceServer).DeletePatientThreshold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VitalsService_DeletePatientThreshold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VitalsServiceServer).DeletePatientThreshold(ctx, req.(*DeletePatientThresholdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VitalsService_WatchAlerts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAlertsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VitalsServiceServer).WatchAlerts(m, &grpc.GenericServerStream[WatchAlertsRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VitalsService_WatchAlertsServer = grpc.ServerStreamingServer[WatchEvent]
+
+func _VitalsService_WatchVitals_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchVitalsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VitalsServiceServer).WatchVitals(m, &grpc.GenericServerStream[WatchVitalsRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VitalsService_WatchVitalsServer = grpc.ServerStreamingServer[WatchEvent]
+
 // VitalsService_ServiceDesc is the grpc.ServiceDesc for VitalsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -183,6 +561,10 @@ var VitalsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "IngestVital",
 			Handler:    _VitalsService_IngestVital_Handler,
 		},
+		{
+			MethodName: "BatchIngestVitals",
+			Handler:    _VitalsService_BatchIngestVitals_Handler,
+		},
 		{
 			MethodName: "ListAlerts",
 			Handler:    _VitalsService_ListAlerts_Handler,
@@ -191,7 +573,51 @@ var VitalsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListVitals",
 			Handler:    _VitalsService_ListVitals_Handler,
 		},
+		{
+			MethodName: "GetAlert",
+			Handler:    _VitalsService_GetAlert_Handler,
+		},
+		{
+			MethodName: "AcknowledgeAlert",
+			Handler:    _VitalsService_AcknowledgeAlert_Handler,
+		},
+		{
+			MethodName: "ResolveAlert",
+			Handler:    _VitalsService_ResolveAlert_Handler,
+		},
+		{
+			MethodName: "SetPatientThreshold",
+			Handler:    _VitalsService_SetPatientThreshold_Handler,
+		},
+		{
+			MethodName: "GetPatientThreshold",
+			Handler:    _VitalsService_GetPatientThreshold_Handler,
+		},
+		{
+			MethodName: "ListPatientThresholds",
+			Handler:    _VitalsService_ListPatientThresholds_Handler,
+		},
+		{
+			MethodName: "DeletePatientThreshold",
+			Handler:    _VitalsService_DeletePatientThreshold_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamIngestVitals",
+			Handler:       _VitalsService_StreamIngestVitals_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchAlerts",
+			Handler:       _VitalsService_WatchAlerts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchVitals",
+			Handler:       _VitalsService_WatchVitals_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/vitals/v1/vitals.proto",
 }